@@ -0,0 +1,59 @@
+// Package address provides address suggestions and normalization.
+package address
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/integrations/dadata"
+	"github.com/RCSE2025/backend-go/internal/model"
+)
+
+var ErrNotRecognized = errors.New("address is not recognized")
+
+// Provider (AddressProvider) подсказывает и стандартизирует адреса
+type Provider interface {
+	// Suggest возвращает до count подсказок для введенной части адреса
+	Suggest(ctx context.Context, query string, count int) ([]model.AddressSuggestion, error)
+	// Normalize приводит адрес к стандартному виду с ФИАС и индексом.
+	// Возвращает ErrNotRecognized, если адрес разобрать не удалось.
+	Normalize(ctx context.Context, raw string) (model.AddressSuggestion, error)
+}
+
+const (
+	ProviderStub   = "stub"
+	ProviderDaData = "dadata"
+)
+
+// MaxSuggestions - ограничение DaData на число подсказок в ответе
+const MaxSuggestions = 20
+
+// New создает провайдера в соответствии с конфигурацией. Stub работает
+// без сети и подходит для разработки и тестов.
+func New(cfg config.AddressConfig, client *dadata.Client) (Provider, error) {
+	var p Provider
+	switch cfg.Provider {
+	case ProviderStub, "":
+		p = NewStub()
+	case ProviderDaData:
+		if !client.Configured() {
+			return nil, errors.New("DADATA_API_KEY is required for dadata address provider")
+		}
+		p = NewDaData(client)
+	default:
+		return nil, fmt.Errorf("unknown address provider %q", cfg.Provider)
+	}
+
+	if cfg.CacheSize > 0 && cfg.CacheTTL > 0 {
+		p = NewCached(p, cfg.CacheTTL, cfg.CacheSize)
+	}
+	return p, nil
+}
+
+// normalizeQuery убирает лишние пробелы, чтобы одинаковые запросы попадали в кеш
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
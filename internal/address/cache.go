@@ -0,0 +1,117 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/model"
+)
+
+// Cached кеширует ответы провайдера в памяти процесса. Подсказки запрашиваются
+// на каждое нажатие клавиши, и без кеша платный лимит DaData быстро кончается.
+// Ошибки не кешируются, кроме ErrNotRecognized.
+type Cached struct {
+	next Provider
+	ttl  time.Duration
+	size int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	suggestions []model.AddressSuggestion
+	err         error
+	expiresAt   time.Time
+}
+
+func NewCached(next Provider, ttl time.Duration, size int) *Cached {
+	return &Cached{next: next, ttl: ttl, size: size, entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cached) Suggest(ctx context.Context, query string, count int) ([]model.AddressSuggestion, error) {
+	query = normalizeQuery(query)
+	key := "s:" + strconv.Itoa(count) + ":" + strings.ToLower(query)
+	if e, ok := c.get(key); ok {
+		return e.suggestions, nil
+	}
+
+	suggestions, err := c.next.Suggest(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, cacheEntry{suggestions: suggestions})
+	return suggestions, nil
+}
+
+func (c *Cached) Normalize(ctx context.Context, raw string) (model.AddressSuggestion, error) {
+	raw = normalizeQuery(raw)
+	key := "n:" + strings.ToLower(raw)
+	if e, ok := c.get(key); ok {
+		if e.err != nil {
+			return model.AddressSuggestion{}, e.err
+		}
+		return e.suggestions[0], nil
+	}
+
+	s, err := c.next.Normalize(ctx, raw)
+	switch {
+	case errors.Is(err, ErrNotRecognized):
+		c.put(key, cacheEntry{err: err})
+		return model.AddressSuggestion{}, err
+	case err != nil:
+		return model.AddressSuggestion{}, err
+	}
+	c.put(key, cacheEntry{suggestions: []model.AddressSuggestion{s}})
+	return s, nil
+}
+
+func (c *Cached) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+func (c *Cached) put(key string, e cacheEntry) {
+	now := time.Now()
+	e.expiresAt = now.Add(c.ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	c.entries[key] = e
+}
+
+// evict удаляет просроченные записи, а если их нет - произвольную половину кеша
+func (c *Cached) evict(now time.Time) {
+	for key, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.size {
+		return
+	}
+	for key := range c.entries {
+		if len(c.entries) < c.size/2+1 {
+			break
+		}
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,93 @@
+package address
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/RCSE2025/backend-go/internal/integrations/dadata"
+	"github.com/RCSE2025/backend-go/internal/model"
+)
+
+// DaData подсказывает адреса через DaData. Стандартизация (clean) платная и
+// требует секретного ключа; без него адрес приводится по первой подсказке.
+type DaData struct {
+	client *dadata.Client
+}
+
+func NewDaData(client *dadata.Client) *DaData {
+	return &DaData{client: client}
+}
+
+func (d *DaData) Suggest(ctx context.Context, query string, count int) ([]model.AddressSuggestion, error) {
+	resp, err := d.client.SuggestAddress(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]model.AddressSuggestion, 0, len(resp))
+	for _, s := range resp {
+		suggestions = append(suggestions, suggestion(s.Data, s.Value))
+	}
+	return suggestions, nil
+}
+
+func (d *DaData) Normalize(ctx context.Context, raw string) (model.AddressSuggestion, error) {
+	if !d.client.CanClean() {
+		suggestions, err := d.Suggest(ctx, raw, 1)
+		if err != nil {
+			return model.AddressSuggestion{}, err
+		}
+		if len(suggestions) == 0 {
+			return model.AddressSuggestion{}, ErrNotRecognized
+		}
+		return suggestions[0], nil
+	}
+
+	results, err := d.client.CleanAddress(ctx, raw)
+	if err != nil {
+		return model.AddressSuggestion{}, err
+	}
+	if len(results) == 0 || results[0].QC == dadata.QCGarbage || results[0].Result == "" {
+		return model.AddressSuggestion{}, ErrNotRecognized
+	}
+	return suggestion(results[0].Address, results[0].Result), nil
+}
+
+func suggestion(a dadata.Address, value string) model.AddressSuggestion {
+	city := a.CityWithType
+	if city == "" {
+		// Для сел и поселков вне городов DaData заполняет только settlement
+		city = a.SettlementWithType
+	}
+
+	house := a.House
+	if house != "" && a.Block != "" {
+		house = strings.TrimSpace(house + " " + a.BlockType + " " + a.Block)
+	}
+
+	return model.AddressSuggestion{
+		Value:     value,
+		Region:    a.RegionWithType,
+		City:      city,
+		Street:    a.StreetWithType,
+		House:     house,
+		Apartment: a.Flat,
+		Postcode:  a.PostalCode,
+		FiasID:    a.FiasID,
+		KladrID:   a.KladrID,
+		Latitude:  parseCoordinate(a.GeoLat),
+		Longitude: parseCoordinate(a.GeoLon),
+	}
+}
+
+func parseCoordinate(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
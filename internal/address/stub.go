@@ -0,0 +1,44 @@
+package address
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/RCSE2025/backend-go/internal/model"
+)
+
+var postcodeRe = regexp.MustCompile(`^\d{6}$`)
+
+// Stub работает без сети: возвращает введенный адрес как единственную
+// подсказку, выделяя из него только почтовый индекс
+type Stub struct{}
+
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (Stub) Suggest(_ context.Context, query string, count int) ([]model.AddressSuggestion, error) {
+	query = normalizeQuery(query)
+	if query == "" || count < 1 {
+		return []model.AddressSuggestion{}, nil
+	}
+	return []model.AddressSuggestion{stubSuggestion(query)}, nil
+}
+
+func (Stub) Normalize(_ context.Context, raw string) (model.AddressSuggestion, error) {
+	raw = normalizeQuery(raw)
+	if raw == "" {
+		return model.AddressSuggestion{}, ErrNotRecognized
+	}
+	return stubSuggestion(raw), nil
+}
+
+func stubSuggestion(value string) model.AddressSuggestion {
+	s := model.AddressSuggestion{Value: value}
+	first, _, _ := strings.Cut(value, ",")
+	if first = strings.TrimSpace(first); postcodeRe.MatchString(first) {
+		s.Postcode = first
+	}
+	return s
+}
@@ -1,21 +1,36 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/RCSE2025/backend-go/internal/address"
+	"github.com/RCSE2025/backend-go/internal/attempts"
 	"github.com/RCSE2025/backend-go/internal/config"
 	"github.com/RCSE2025/backend-go/internal/email"
+	"github.com/RCSE2025/backend-go/internal/geoip"
 	"github.com/RCSE2025/backend-go/internal/http/handlers"
+	mwRatelimit "github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
+	"github.com/RCSE2025/backend-go/internal/integrations/dadata"
+	"github.com/RCSE2025/backend-go/internal/jwks"
 	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/oauth"
+	"github.com/RCSE2025/backend-go/internal/password"
+	"github.com/RCSE2025/backend-go/internal/ratelimit"
 	"github.com/RCSE2025/backend-go/internal/repo"
 	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/internal/sms"
+	"github.com/RCSE2025/backend-go/internal/storage"
 	"github.com/RCSE2025/backend-go/internal/utils"
 	"github.com/RCSE2025/backend-go/pkg/httpserver"
 	"github.com/RCSE2025/backend-go/pkg/logger"
 	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/RCSE2025/backend-go/pkg/scheduler"
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -25,7 +40,8 @@ func Run() {
 	cfg := config.Get()
 
 	log := logger.NewLogger(cfg.Production)
-	//ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	log.Info("app config", slog.Any("config", cfg))
 
@@ -55,26 +71,226 @@ func Run() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.Default()
+	// Адрес клиента нужен для ограничений по IP, блокировки входа и геолокации сессий
+	if cfg.Production && len(cfg.TrustedProxies) == 0 {
+		log.Error("TRUSTED_PROXIES is required in production")
+		return
+	}
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Error("invalid TRUSTED_PROXIES", sl.Err(err))
+		return
+	}
+	r.RemoteIPHeaders = cfg.RemoteIPHeaders
 	//userRepo := repo.New(postgresDB)
-	jwtService := service.NewJWTService()
+	var jwtKeys *jwks.Set
+	switch {
+	case cfg.Auth.JWTSigningKeyFile != "":
+		jwtKeys, err = jwks.Load(cfg.Auth.JWTSigningKeyFile, cfg.Auth.JWTVerificationKeyFiles)
+	case cfg.Production:
+		err = errors.New("JWT_SIGNING_KEY_FILE is required in production")
+	default:
+		log.Warn("JWT_SIGNING_KEY_FILE is not set, using an ephemeral signing key")
+		jwtKeys, err = jwks.Generate()
+	}
+	if err != nil {
+		log.Error("error loading jwt keys", sl.Err(err))
+		return
+	}
+	// Ключом шифруются секреты TOTP; ключ по умолчанию известен всем
+	if cfg.Production && (cfg.Auth.MFAEncryptionKey == "" || cfg.Auth.MFAEncryptionKey == config.DevMFAEncryptionKey) {
+		log.Error("MFA_ENCRYPTION_KEY is required in production")
+		return
+	}
+	tokenRepo := repo.NewTokenRepo(db)
+	sessionChecker := service.NewSessionChecker(tokenRepo, cfg.Auth.SessionCacheTTL)
+	jwtService := service.NewJWTService(jwtKeys, cfg.Auth.JWTIssuer, cfg.Auth.AccessTokenTTL, sessionChecker)
 	mailer := email.NewMailer(cfg.Email)
 
+	geo, err := geoip.Open(cfg.Auth.GeoIPDatabase, cfg.Auth.GeoIPLanguage)
+	if err != nil {
+		log.Error("error opening geoip database", sl.Err(err))
+		return
+	}
+	defer geo.Close()
+
 	userRepo := repo.NewUserRepo(db)
-	userService := service.NewUserService(userRepo, jwtService, mailer, cfg.FrontendURL)
+	loginAttempts, err := attempts.New(cfg.LoginGuard, db)
+	if err != nil {
+		log.Error("error creating login attempts counter", sl.Err(err))
+		return
+	}
+	loginGuard := service.NewLoginGuard(loginAttempts, repo.NewAuditRepo(db), cfg.LoginGuard)
+
+	var limiter ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter, err = ratelimit.New(cfg.RateLimit)
+		if err != nil {
+			log.Error("error creating rate limiter", sl.Err(err))
+			return
+		}
+	}
+
+	// Консольный шлюз пишет коды входа в лог, по ним можно войти в чужой аккаунт
+	if cfg.Production && (cfg.SMS.Backend == sms.BackendConsole || cfg.SMS.Backend == "") {
+		log.Error("SMS_BACKEND must be a real gateway in production")
+		return
+	}
+	smsSender, err := sms.New(cfg.SMS, log)
+	if err != nil {
+		log.Error("error creating sms sender", sl.Err(err))
+		return
+	}
+	// Лимит SMS на номер действует, даже если ограничение запросов выключено
+	smsLimiter := limiter
+	if smsLimiter == nil {
+		smsLimiter = ratelimit.NewMemoryLimiter()
+	}
+	phoneOTP, err := service.NewPhoneOTP(repo.NewPhoneCodeRepo(db), smsSender, smsLimiter, cfg.SMS)
+	if err != nil {
+		log.Error("error creating phone otp", sl.Err(err))
+		return
+	}
+
+	userService := service.NewUserService(
+		userRepo,
+		tokenRepo,
+		repo.NewMFARepo(db),
+		jwtService,
+		mailer,
+		geo,
+		cfg.FrontendURL,
+		cfg.Auth.RefreshTokenTTL,
+		service.MFAConfig{
+			Issuer:        cfg.Auth.MFAIssuer,
+			EncryptionKey: cfg.Auth.MFAEncryptionKey,
+			RequiredRoles: cfg.Auth.MFARequiredRoles,
+		},
+		loginGuard,
+		password.NewHasher(cfg.Password),
+		password.NewPolicy(cfg.Password),
+		phoneOTP,
+		sessionChecker,
+	)
+
+	oauthProviders, err := oauth.New(ctx, cfg.OAuth, cfg.FrontendURL)
+	if err != nil {
+		log.Error("error configuring oauth providers", sl.Err(err))
+		return
+	}
+	oauthService := service.NewOAuthService(oauthProviders, repo.NewOAuthRepo(db), userRepo, userService, cfg.OAuth.StateTTL)
+
+	permissionService := service.NewPermissionService(repo.NewPermissionRepo(db), userRepo, cfg.Auth.PermissionsCacheTTL)
+	if err := permissionService.EnsureDefaults(ctx); err != nil {
+		log.Error("error seeding role permissions", sl.Err(err))
+		return
+	}
 
 	// Создаем репозиторий и сервис для работы с продуктами
-	yookassa := service.NewYookassaPayment(cfg.Yookassa.AccountId, cfg.Yookassa.SecretKey)
+	yookassa := service.NewYookassaPayment(cfg.Yookassa)
 	productRepo := repo.NewProductRepo(db)
 	cartRepo := repo.NewCartRepo(db, productRepo)
 	orderRepo := repo.NewOrderRepo(db, productRepo)
-	s3Worker := utils.NewS3WorkerAPI("products", cfg.S3WorkerURL)
-	s3WorkerReview := utils.NewS3WorkerAPI("reviews", cfg.S3WorkerURL)
-	productService := service.NewProductService(productRepo, s3Worker, s3WorkerReview)
+	// Ключом подписываются ссылки на чтение и загрузку файлов локального хранилища
+	if cfg.Production && cfg.Storage.Backend == storage.BackendLocal &&
+		(cfg.Storage.SigningKey == "" || cfg.Storage.SigningKey == config.DevStorageSigningKey) {
+		log.Error("STORAGE_SIGNING_KEY is required for local storage in production")
+		return
+	}
+	productStorage, err := storage.New(cfg, cfg.Storage.ProductsBucket)
+	if err != nil {
+		log.Error("error creating products storage", sl.Err(err))
+		return
+	}
+	reviewStorage, err := storage.New(cfg, cfg.Storage.ReviewsBucket)
+	if err != nil {
+		log.Error("error creating reviews storage", sl.Err(err))
+		return
+	}
+	exportStorage, err := storage.New(cfg, cfg.Storage.ExportsBucket)
+	if err != nil {
+		log.Error("error creating exports storage", sl.Err(err))
+		return
+	}
+	documentStorage, err := storage.New(cfg, cfg.Storage.DocumentsBucket)
+	if err != nil {
+		log.Error("error creating documents storage", sl.Err(err))
+		return
+	}
+	addressRepo := repo.NewAddressRepo(db)
+	privacyService := service.NewPrivacyService(
+		repo.NewPrivacyRepo(db), userRepo, orderRepo, addressRepo, repo.NewOAuthRepo(db),
+		exportStorage, mailer, sessionChecker, cfg.FrontendURL, cfg.Privacy,
+	)
+	businessRepo := repo.NewBusinessRepo(db)
+	productService := service.NewProductService(productRepo, productStorage, reviewStorage)
+	policyService := service.NewPolicyService(productRepo, businessRepo, permissionService)
 	cartService := service.NewCartService(cartRepo, productRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo, yookassa, cartService)
+	var orderNotifier sms.Sender
+	if cfg.SMS.OrderNotifySMS {
+		orderNotifier = smsSender
+	}
+	dadataCache := dadata.NewPostgresCache(db)
+	dadataClient := dadata.NewClient(cfg.DaData, dadataCache)
+	if cfg.Production && !dadataClient.Configured() {
+		log.Warn("DADATA_API_KEY is not set, business details will not be filled")
+	}
+	if cfg.Production && (cfg.Address.Provider == address.ProviderStub || cfg.Address.Provider == "") {
+		log.Warn("ADDRESS_PROVIDER is stub, addresses will not be normalized")
+	}
+	addressProvider, err := address.New(cfg.Address, dadataClient)
+	if err != nil {
+		log.Error("error creating address provider", sl.Err(err))
+		return
+	}
+	addressService := service.NewAddressService(addressRepo, addressProvider)
+	ledgerRepo := repo.NewLedgerRepo(db)
+	ledgerService := service.NewLedgerService(ledgerRepo, businessRepo, cfg.Finance)
+	payoutService := service.NewPayoutService(repo.NewPayoutRepo(db), ledgerRepo, ledgerService, policyService, cfg.Finance)
+	orderService := service.NewOrderService(orderRepo, productRepo, userRepo, yookassa, ledgerService, cartService, addressService, orderNotifier)
 
-	businessService := service.NewBusinessService(repo.NewBusinessRepo(db), userRepo)
-	handlers.NewRouter(r, log, userService, jwtService, productService, cartService, businessService, orderService, yookassa)
+	storefrontRepo := repo.NewStorefrontRepo(db)
+	storefrontService := service.NewStorefrontService(storefrontRepo, businessRepo, productRepo, productStorage)
+	analyticsService := service.NewAnalyticsService(repo.NewAnalyticsRepo(db), cfg.Analytics)
+	uploadService := service.NewUploadService(
+		repo.NewUploadRepo(db), productRepo, storefrontRepo, businessRepo, policyService, productStorage, reviewStorage,
+		utils.NewModeratorAPI(), cfg.Storage.UploadMaxSize, cfg.Storage.UploadURLTTL,
+	)
+
+	businessService := service.NewBusinessService(businessRepo, userRepo, dadataClient)
+	businessVerificationService := service.NewBusinessVerificationService(
+		repo.NewBusinessVerificationRepo(db), businessRepo, dadataClient, documentStorage, cfg.Business,
+	)
+	businessMemberService := service.NewBusinessMemberService(
+		repo.NewBusinessMemberRepo(db), businessRepo, userRepo, permissionService, mailer, cfg.FrontendURL, cfg.Business,
+	)
+	limits, err := mwRatelimit.NewPolicies(limiter, cfg.RateLimit.Policies)
+	if err != nil {
+		log.Error("error parsing rate limit policies", sl.Err(err))
+		return
+	}
+
+	handlers.NewRouter(r, log, userService, jwtService, productService, cartService, businessService, businessVerificationService, businessMemberService, storefrontService, analyticsService, ledgerService, payoutService, orderService, yookassa, uploadService, policyService, permissionService, oauthService, privacyService, addressService, limits)
+	if cfg.Storage.Backend == storage.BackendLocal {
+		fileServer := gin.WrapH(http.StripPrefix("/storage", storage.LocalFileServer(cfg.Storage.LocalDir, cfg.Storage.SigningKey, cfg.Storage.UploadMaxSize)))
+		r.GET("/storage/*path", fileServer)
+		r.PUT("/storage/*path", fileServer)
+	}
+
+	jobs := scheduler.New(log)
+	jobs.Every("uploads-gc", cfg.Storage.UploadGCEvery, uploadService.CleanupExpiredUploads)
+	jobs.Every("refresh-tokens-gc", cfg.Auth.TokenGCEvery, userService.CleanupExpiredTokens)
+	jobs.Every("login-attempts-gc", cfg.LoginGuard.GCEvery, loginGuard.Cleanup)
+	jobs.Every("verification-codes-gc", cfg.Auth.VerificationCodeGCEvery, userService.CleanupExpiredVerificationCodes)
+	jobs.Every("phone-codes-gc", cfg.SMS.CodeGCEvery, phoneOTP.Cleanup)
+	jobs.Every("oauth-states-gc", cfg.OAuth.StateTTL, oauthService.Cleanup)
+	jobs.Every("data-exports", cfg.Privacy.ExportPollEvery, privacyService.ProcessExports)
+	jobs.Every("data-exports-gc", cfg.Privacy.ExportGCEvery, privacyService.CleanupExports)
+	jobs.Every("account-erasure", cfg.Privacy.ErasureEvery, privacyService.EraseDueAccounts)
+	jobs.Every("dadata-parties-gc", cfg.DaData.PartyCacheGCEvery, dadataCache.Cleanup)
+	jobs.Every("business-invitations-gc", cfg.Business.InvitationGCEvery, businessMemberService.CleanupInvitations)
+	jobs.Every("analytics-rollup", cfg.Analytics.RollupEvery, analyticsService.Rollup)
+	jobs.Every("payout-batches", cfg.Finance.PayoutEvery, payoutService.GenerateBatch)
+	jobs.Start(ctx)
 
 	httpServer := httpserver.New(r, httpserver.Port(cfg.Port))
 
@@ -89,6 +305,8 @@ func Run() {
 		log.Error("app - Run - httpServer.Notify: %w", sl.Err(err))
 	}
 
+	jobs.Stop()
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		log.Error("app - Run - httpServer.Shutdown: %w", sl.Err(err))
@@ -108,5 +326,4 @@ func Run() {
 	log.Info("app - Run - db closed")
 
 	log.Info("app - Run - exiting")
-	//cancel()
 }
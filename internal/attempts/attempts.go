@@ -0,0 +1,47 @@
+// Package attempts counts failed login attempts per key (account, IP address)
+// so that the caller can apply backoff and temporary lockout.
+package attempts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+	"gorm.io/gorm"
+)
+
+// State - счетчик неудачных попыток по ключу
+type State struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// Counter хранит счетчики неудачных попыток. Счетчик обнуляется,
+// если с последней ошибки прошло больше окна, переданного в Fail.
+type Counter interface {
+	Get(ctx context.Context, key string) (State, error)
+	// Fail атомарно увеличивает счетчик и возвращает новое состояние
+	Fail(ctx context.Context, key string, now time.Time, window time.Duration) (State, error)
+	Reset(ctx context.Context, key string) error
+	// Cleanup удаляет счетчики, окно которых истекло к моменту now
+	Cleanup(ctx context.Context, now time.Time) error
+}
+
+const (
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory"
+)
+
+// New создает хранилище счетчиков в соответствии с конфигурацией.
+// In-memory подходит только для одного экземпляра приложения.
+func New(cfg config.LoginGuardConfig, db *gorm.DB) (Counter, error) {
+	switch cfg.Backend {
+	case BackendPostgres, "":
+		return NewPostgresCounter(db), nil
+	case BackendMemory:
+		return NewMemoryCounter(), nil
+	default:
+		return nil, fmt.Errorf("unknown login attempts backend %q", cfg.Backend)
+	}
+}
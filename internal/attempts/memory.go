@@ -0,0 +1,68 @@
+package attempts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+type MemoryCounter struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{entries: make(map[string]memoryEntry)}
+}
+
+func (m *MemoryCounter) Get(ctx context.Context, key string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return State{}, nil
+	}
+	return e.state, nil
+}
+
+func (m *MemoryCounter) Fail(ctx context.Context, key string, now time.Time, window time.Duration) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || now.After(e.expiresAt) {
+		e = memoryEntry{}
+	}
+	e.state.Failures++
+	e.state.LastFailureAt = now
+	e.expiresAt = now.Add(window)
+	m.entries[key] = e
+
+	return e.state, nil
+}
+
+func (m *MemoryCounter) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *MemoryCounter) Cleanup(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
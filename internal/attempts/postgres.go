@@ -0,0 +1,58 @@
+package attempts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+)
+
+type PostgresCounter struct {
+	db *gorm.DB
+}
+
+func NewPostgresCounter(db *gorm.DB) *PostgresCounter {
+	return &PostgresCounter{db: db}
+}
+
+func (p *PostgresCounter) Get(ctx context.Context, key string) (State, error) {
+	var row model.LoginAttempt
+	err := p.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, time.Now()).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, err
+	}
+	return State{Failures: row.Failures, LastFailureAt: row.LastFailureAt}, nil
+}
+
+// Fail увеличивает счетчик одним запросом, чтобы параллельные попытки не терялись
+func (p *PostgresCounter) Fail(ctx context.Context, key string, now time.Time, window time.Duration) (State, error) {
+	var row model.LoginAttempt
+	err := p.db.WithContext(ctx).Raw(`
+		INSERT INTO login_attempts (key, failures, last_failure_at, expires_at)
+		VALUES (?, 1, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.expires_at <= EXCLUDED.last_failure_at
+				THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at,
+			expires_at = EXCLUDED.expires_at
+		RETURNING key, failures, last_failure_at, expires_at`,
+		key, now, now.Add(window),
+	).Scan(&row).Error
+	if err != nil {
+		return State{}, err
+	}
+	return State{Failures: row.Failures, LastFailureAt: row.LastFailureAt}, nil
+}
+
+func (p *PostgresCounter) Reset(ctx context.Context, key string) error {
+	return p.db.WithContext(ctx).Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+}
+
+func (p *PostgresCounter) Cleanup(ctx context.Context, now time.Time) error {
+	return p.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.LoginAttempt{}).Error
+}
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"log/slog"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -13,21 +16,193 @@ type DatabaseConfig struct {
 	Host     string `env:"DB_HOST" env-required:"true"`
 	Port     int    `env:"DB_PORT" env-required:"true"`
 	User     string `env:"DB_USER" env-required:"true"`
-	Password string `env:"DB_PASSWORD" env-required:"true"`
+	Password string `env:"DB_PASSWORD" env-required:"true" secret:"true"`
 	DBName   string `env:"DB_NAME" env-required:"true"`
 }
 
 type EmailConfig struct {
 	AuthEmail    string `env:"SMTP_EMAIL" env-required:"true"`
-	AuthPassword string `env:"SMTP_PASSWORD" env-required:"true"`
+	AuthPassword string `env:"SMTP_PASSWORD" env-required:"true" secret:"true"`
 	Host         string `env:"SMTP_HOST" env-required:"true"`
 	Port         int    `env:"SMTP_PORT" env-required:"true"`
 	From         string `env:"MAIL_FROM" env-required:"true"`
 }
 
 type YookassaСonfig struct {
-	AccountId string `env:"YOOKASSA_ACCOUNT_ID" env-required:"true"`
-	SecretKey string `env:"YOOKASSA_SECRET_KEY" env-required:"true"`
+	AccountId       string `env:"YOOKASSA_ACCOUNT_ID"       env-required:"true"`
+	SecretKey       string `env:"YOOKASSA_SECRET_KEY"       env-required:"true" secret:"true"`
+	ReceiptsEnabled bool   `env:"YOOKASSA_RECEIPTS_ENABLED" env-default:"false"` // передавать чек по 54-ФЗ
+	VatCode         int    `env:"YOOKASSA_VAT_CODE"         env-default:"1"`     // 1 - без НДС
+}
+
+// DevStorageSigningKey - значение STORAGE_SIGNING_KEY по умолчанию, только для разработки
+const DevStorageSigningKey = "dev-storage-key"
+
+type StorageConfig struct {
+	Backend         string        `env:"STORAGE_BACKEND"         env-default:"worker"` // local, s3, worker
+	ProductsBucket  string        `env:"STORAGE_PRODUCTS_BUCKET" env-default:"products"`
+	ReviewsBucket   string        `env:"STORAGE_REVIEWS_BUCKET"  env-default:"reviews"`
+	ExportsBucket   string        `env:"STORAGE_EXPORTS_BUCKET"  env-default:"exports"`
+	DocumentsBucket string        `env:"STORAGE_DOCUMENTS_BUCKET" env-default:"business-documents"`
+	Timeout         time.Duration `env:"STORAGE_TIMEOUT"         env-default:"30s"`
+	Retries         int           `env:"STORAGE_RETRIES"         env-default:"3"`
+	LocalDir        string        `env:"STORAGE_LOCAL_DIR"       env-default:"./data/storage"`
+	PublicURL       string        `env:"STORAGE_PUBLIC_URL"      env-default:"http://localhost/storage"`
+	SigningKey      string        `env:"STORAGE_SIGNING_KEY"     env-default:"dev-storage-key" secret:"true"`
+	S3Endpoint      string        `env:"S3_ENDPOINT"             env-default:"localhost:9000"`
+	S3AccessKey     string        `env:"S3_ACCESS_KEY" secret:"true"`
+	S3SecretKey     string        `env:"S3_SECRET_KEY" secret:"true"`
+	S3Region        string        `env:"S3_REGION"               env-default:"us-east-1"`
+	S3UseSSL        bool          `env:"S3_USE_SSL"              env-default:"false"`
+	UploadMaxSize   int64         `env:"UPLOAD_MAX_SIZE"         env-default:"10485760"`
+	UploadURLTTL    time.Duration `env:"UPLOAD_URL_TTL"          env-default:"15m"`
+	UploadGCEvery   time.Duration `env:"UPLOAD_GC_INTERVAL"      env-default:"1h"`
+}
+
+// DevMFAEncryptionKey - значение MFA_ENCRYPTION_KEY по умолчанию, только для разработки
+const DevMFAEncryptionKey = "dev-mfa-key"
+
+type AuthConfig struct {
+	PermissionsCacheTTL     time.Duration `env:"PERMISSIONS_CACHE_TTL"      env-default:"30s"`
+	AccessTokenTTL          time.Duration `env:"ACCESS_TOKEN_TTL"           env-default:"15m"`
+	RefreshTokenTTL         time.Duration `env:"REFRESH_TOKEN_TTL"          env-default:"720h"`
+	SessionCacheTTL         time.Duration `env:"SESSION_CACHE_TTL"          env-default:"10s"` // через сколько отзыв сессии заметят другие экземпляры
+	TokenGCEvery            time.Duration `env:"TOKEN_GC_INTERVAL"          env-default:"6h"`
+	VerificationCodeGCEvery time.Duration `env:"VERIFICATION_CODE_GC_INTERVAL" env-default:"1h"`
+	GeoIPDatabase           string        `env:"GEOIP_DB_PATH"`
+	GeoIPLanguage           string        `env:"GEOIP_LANGUAGE"             env-default:"ru"`
+	JWTIssuer               string        `env:"JWT_ISSUER"                 env-default:"rcse-backend"`
+	JWTSigningKeyFile       string        `env:"JWT_SIGNING_KEY_FILE"`                         // PEM, RSA или Ed25519; обязателен в production
+	JWTVerificationKeyFiles []string      `env:"JWT_VERIFICATION_KEY_FILES" env-separator:","` // старые ключи на время ротации
+	MFAIssuer               string        `env:"MFA_ISSUER"                 env-default:"RCSE"`
+	MFAEncryptionKey        string        `env:"MFA_ENCRYPTION_KEY"         env-default:"dev-mfa-key" secret:"true"`
+	MFARequiredRoles        []string      `env:"MFA_REQUIRED_ROLES"         env-separator:","`
+}
+
+type LoginGuardConfig struct {
+	Backend             string        `env:"LOGIN_ATTEMPTS_BACKEND"      env-default:"postgres"` // postgres, memory
+	FreeAttempts        int           `env:"LOGIN_FREE_ATTEMPTS"         env-default:"3"`
+	BaseDelay           time.Duration `env:"LOGIN_BASE_DELAY"            env-default:"1s"`
+	MaxDelay            time.Duration `env:"LOGIN_MAX_DELAY"             env-default:"5m"`
+	AccountLockAttempts int           `env:"LOGIN_ACCOUNT_LOCK_ATTEMPTS" env-default:"10"`
+	IPLockAttempts      int           `env:"LOGIN_IP_LOCK_ATTEMPTS"      env-default:"100"`
+	LockDuration        time.Duration `env:"LOGIN_LOCK_DURATION"         env-default:"30m"`
+	Window              time.Duration `env:"LOGIN_ATTEMPTS_WINDOW"       env-default:"24h"`
+	GCEvery             time.Duration `env:"LOGIN_ATTEMPTS_GC_INTERVAL"  env-default:"1h"`
+	AuditRetention      time.Duration `env:"AUTH_AUDIT_RETENTION"        env-default:"2160h"`
+}
+
+type RateLimitConfig struct {
+	Enabled  bool              `env:"RATE_LIMIT_ENABLED"  env-default:"true"`
+	Backend  string            `env:"RATE_LIMIT_BACKEND"  env-default:"memory"` // memory, redis
+	RedisURL string            `env:"REDIS_URL"           env-default:"redis://localhost:6379/0" secret:"true"`
+	Policies map[string]string `env:"RATE_LIMIT_POLICIES" env-separator:","` // name:limit/period, например password_reset:3/1h
+}
+
+type PasswordConfig struct {
+	MinLength         int    `env:"PASSWORD_MIN_LENGTH"      env-default:"8"`
+	MaxLength         int    `env:"PASSWORD_MAX_LENGTH"      env-default:"128"`
+	RequireLower      bool   `env:"PASSWORD_REQUIRE_LOWER"   env-default:"false"`
+	RequireUpper      bool   `env:"PASSWORD_REQUIRE_UPPER"   env-default:"false"`
+	RequireDigit      bool   `env:"PASSWORD_REQUIRE_DIGIT"   env-default:"true"`
+	RequireSymbol     bool   `env:"PASSWORD_REQUIRE_SYMBOL"  env-default:"false"`
+	RejectCommon      bool   `env:"PASSWORD_REJECT_COMMON"   env-default:"true"`
+	RejectPersonal    bool   `env:"PASSWORD_REJECT_PERSONAL" env-default:"true"`
+	Argon2Memory      uint32 `env:"ARGON2_MEMORY"            env-default:"19456"` // КиБ
+	Argon2Iterations  uint32 `env:"ARGON2_ITERATIONS"        env-default:"2"`
+	Argon2Parallelism uint8  `env:"ARGON2_PARALLELISM"       env-default:"1"`
+	Argon2SaltLength  uint32 `env:"ARGON2_SALT_LENGTH"       env-default:"16"`
+	Argon2KeyLength   uint32 `env:"ARGON2_KEY_LENGTH"        env-default:"32"`
+}
+
+type SMSConfig struct {
+	Backend        string        `env:"SMS_BACKEND"             env-default:"console"` // console, smsru, smsc
+	Sender         string        `env:"SMS_SENDER"`                                    // имя отправителя, согласованное с шлюзом
+	Timeout        time.Duration `env:"SMS_TIMEOUT"             env-default:"10s"`
+	SMSRuAPIID     string        `env:"SMSRU_API_ID" secret:"true"`
+	SMSCLogin      string        `env:"SMSC_LOGIN"`
+	SMSCPassword   string        `env:"SMSC_PASSWORD" secret:"true"`
+	CodeTTL        time.Duration `env:"SMS_CODE_TTL"            env-default:"5m"`
+	ResendCooldown time.Duration `env:"SMS_RESEND_COOLDOWN"     env-default:"1m"`
+	RatePerNumber  string        `env:"SMS_RATE_PER_NUMBER"     env-default:"5/1h"`
+	CodeGCEvery    time.Duration `env:"SMS_CODE_GC_INTERVAL"    env-default:"1h"`
+	OrderNotifySMS bool          `env:"SMS_ORDER_NOTIFICATIONS" env-default:"true"`
+}
+
+type OAuthConfig struct {
+	RedirectURL        string        `env:"OAUTH_REDIRECT_URL"` // по умолчанию FRONTEND_URL/oauth/callback, к адресу добавляется /<провайдер>
+	StateTTL           time.Duration `env:"OAUTH_STATE_TTL"            env-default:"10m"`
+	Timeout            time.Duration `env:"OAUTH_TIMEOUT"              env-default:"10s"`
+	VKIDClientID       string        `env:"OAUTH_VKID_CLIENT_ID"`
+	VKIDClientSecret   string        `env:"OAUTH_VKID_CLIENT_SECRET" secret:"true"`
+	YandexClientID     string        `env:"OAUTH_YANDEX_CLIENT_ID"`
+	YandexClientSecret string        `env:"OAUTH_YANDEX_CLIENT_SECRET" secret:"true"`
+	OIDCName           string        `env:"OAUTH_OIDC_NAME"            env-default:"oidc"`
+	OIDCIssuer         string        `env:"OAUTH_OIDC_ISSUER"` // провайдер включается, если задан издатель
+	OIDCClientID       string        `env:"OAUTH_OIDC_CLIENT_ID"`
+	OIDCClientSecret   string        `env:"OAUTH_OIDC_CLIENT_SECRET" secret:"true"`
+	OIDCScopes         []string      `env:"OAUTH_OIDC_SCOPES"          env-default:"openid,email,profile" env-separator:","`
+	OIDCTrustEmail     bool          `env:"OAUTH_OIDC_TRUST_EMAIL"     env-default:"false"` // считать почту подтвержденной без email_verified
+}
+
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration `env:"ACCOUNT_DELETION_GRACE_PERIOD" env-default:"720h"` // срок, в который можно отменить удаление
+	ErasureEvery        time.Duration `env:"ACCOUNT_ERASURE_INTERVAL"      env-default:"1h"`
+	ExportTTL           time.Duration `env:"DATA_EXPORT_TTL"               env-default:"168h"` // сколько хранится готовый архив
+	ExportPollEvery     time.Duration `env:"DATA_EXPORT_POLL_INTERVAL"     env-default:"30s"`
+	ExportTimeout       time.Duration `env:"DATA_EXPORT_TIMEOUT"           env-default:"10m"` // зависшая выгрузка запускается заново
+	ExportGCEvery       time.Duration `env:"DATA_EXPORT_GC_INTERVAL"       env-default:"1h"`
+}
+
+type DaDataConfig struct {
+	APIKey            string        `env:"DADATA_API_KEY" secret:"true"`
+	SecretKey         string        `env:"DADATA_SECRET_KEY" secret:"true"` // нужен для стандартизации адресов
+	Timeout           time.Duration `env:"DADATA_TIMEOUT"                 env-default:"5s"`
+	Retries           int           `env:"DADATA_RETRIES"                 env-default:"2"` // повторы при сетевых ошибках, 429 и 5xx
+	RetryDelay        time.Duration `env:"DADATA_RETRY_DELAY"             env-default:"300ms"`
+	PartyCacheTTL     time.Duration `env:"DADATA_PARTY_CACHE_TTL"         env-default:"168h"` // 0 отключает кеш
+	PartyCacheGCEvery time.Duration `env:"DADATA_PARTY_CACHE_GC_INTERVAL" env-default:"6h"`
+}
+
+type BusinessConfig struct {
+	DocumentMaxSize   int64         `env:"BUSINESS_DOCUMENT_MAX_SIZE"       env-default:"10485760"`
+	MaxDocuments      int           `env:"BUSINESS_MAX_DOCUMENTS"           env-default:"20"`
+	InvitationTTL     time.Duration `env:"BUSINESS_INVITATION_TTL"          env-default:"168h"`
+	InvitationGCEvery time.Duration `env:"BUSINESS_INVITATION_GC_INTERVAL"  env-default:"6h"`
+}
+
+// AnalyticsConfig - свертка продаж и просмотров в дневные агрегаты для аналитики продавцов
+type AnalyticsConfig struct {
+	RollupEvery time.Duration `env:"ANALYTICS_ROLLUP_INTERVAL" env-default:"1h"`
+	// Lookback - сколько последних дней пересчитывается при каждой свертке:
+	// заказы подтверждаются и возвращаются задним числом
+	Lookback time.Duration `env:"ANALYTICS_ROLLUP_LOOKBACK" env-default:"72h"`
+	// TimeZone - часовой пояс границ дня
+	TimeZone string `env:"ANALYTICS_TIMEZONE" env-default:"Europe/Moscow"`
+	// ForecastWindow - за сколько дней берется средний темп продаж для прогноза остатков
+	ForecastWindow int `env:"ANALYTICS_FORECAST_WINDOW_DAYS" env-default:"28"`
+	// EventRetention - сколько хранятся сырые просмотры и добавления в корзину.
+	// Должно быть больше Lookback, иначе свертка потеряет события.
+	EventRetention time.Duration `env:"ANALYTICS_EVENT_RETENTION" env-default:"2160h"`
+}
+
+// FinanceConfig - комиссия площадки и выплаты продавцам
+type FinanceConfig struct {
+	// CommissionRate - комиссия в процентах, если для бизнеса и категории нет правила
+	CommissionRate float64       `env:"FINANCE_COMMISSION_RATE" env-default:"10"`
+	PayoutEvery    time.Duration `env:"FINANCE_PAYOUT_INTERVAL" env-default:"24h"`
+	// PayoutHold - сколько поступление удерживается до выплаты, чтобы из него можно было вернуть деньги
+	PayoutHold time.Duration `env:"FINANCE_PAYOUT_HOLD" env-default:"336h"`
+	// PayoutMin - минимальная сумма выплаты в рублях, меньший остаток копится до следующего реестра
+	PayoutMin float64 `env:"FINANCE_PAYOUT_MIN" env-default:"1000"`
+	// TimeZone - часовой пояс границ месяца в выписках
+	TimeZone string `env:"FINANCE_TIMEZONE" env-default:"Europe/Moscow"`
+}
+
+type AddressConfig struct {
+	Provider  string        `env:"ADDRESS_PROVIDER"   env-default:"stub"` // dadata, stub
+	CacheTTL  time.Duration `env:"ADDRESS_CACHE_TTL"  env-default:"24h"`
+	CacheSize int           `env:"ADDRESS_CACHE_SIZE" env-default:"10000"` // 0 отключает кеш
 }
 
 type Config struct {
@@ -38,9 +213,27 @@ type Config struct {
 	S3WorkerURL      string `env:"S3_WORKER_URL"  env-default:"http://localhost:8000"`
 	ModerateModelURL string `env:"MODERATE_MODEL_URL" env-required:"http://localhost:8000"`
 	FrontendURL      string `env:"FRONTEND_URL"   env-default:"http://localhost:3000"`
-	Database         DatabaseConfig
-	Email            EmailConfig
-	Yookassa         YookassaСonfig
+	// TrustedProxies - адреса и подсети обратных прокси (Traefik), которым
+	// доверяется заголовок с адресом клиента. Без них адресом клиента считается
+	// адрес прокси, и ограничения по IP становятся общими для всех.
+	TrustedProxies  []string `env:"TRUSTED_PROXIES"   env-separator:","`
+	RemoteIPHeaders []string `env:"REMOTE_IP_HEADERS" env-separator:"," env-default:"X-Forwarded-For,X-Real-IP"`
+	Database        DatabaseConfig
+	Email           EmailConfig
+	Yookassa        YookassaСonfig
+	Storage         StorageConfig
+	Auth            AuthConfig
+	LoginGuard      LoginGuardConfig
+	RateLimit       RateLimitConfig
+	Password        PasswordConfig
+	SMS             SMSConfig
+	OAuth           OAuthConfig
+	Privacy         PrivacyConfig
+	DaData          DaDataConfig
+	Address         AddressConfig
+	Business        BusinessConfig
+	Analytics       AnalyticsConfig
+	Finance         FinanceConfig
 }
 
 var (
@@ -64,6 +257,31 @@ func Get() *Config {
 	return &config
 }
 
+// LogValue скрывает в логах значения полей с тегом secret:"true"
+func (c Config) LogValue() slog.Value {
+	return redact(reflect.ValueOf(c))
+}
+
+func redact(v reflect.Value) slog.Value {
+	t := v.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		switch {
+		case !f.IsExported():
+		case f.Tag.Get("secret") == "true":
+			if !fv.IsZero() {
+				attrs = append(attrs, slog.String(f.Name, "***"))
+			}
+		case fv.Kind() == reflect.Struct:
+			attrs = append(attrs, slog.Attr{Key: f.Name, Value: redact(fv)})
+		default:
+			attrs = append(attrs, slog.Any(f.Name, fv.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
 func (c *Config) GetDSN() string {
 	//dsn := "host=localhost user=postgres password=postgres dbname=hack-2025-backend-go port=5432 sslmode=disable TimeZone=Europe/Moscow"
 	//
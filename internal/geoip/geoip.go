@@ -0,0 +1,75 @@
+// Package geoip determines approximate location of a client by IP address
+// using a local MaxMind GeoLite2/GeoIP2 City database file.
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Locator возвращает приблизительное местоположение по IP
+type Locator interface {
+	// Locate возвращает строку вида "Город, Страна" или пустую строку, если место неизвестно
+	Locate(ip string) string
+	Close() error
+}
+
+// Open открывает базу по пути path. Если путь пуст, возвращает Locator,
+// который никогда не определяет местоположение.
+func Open(path, lang string) (Locator, error) {
+	if path == "" {
+		return nopLocator{}, nil
+	}
+
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open geoip database: %w", err)
+	}
+	return &dbLocator{db: db, lang: lang}, nil
+}
+
+type dbLocator struct {
+	db   *geoip2.Reader
+	lang string
+}
+
+func (l *dbLocator) Locate(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsLoopback() || addr.IsPrivate() {
+		return ""
+	}
+
+	city, err := l.db.City(addr)
+	if err != nil {
+		return ""
+	}
+
+	parts := make([]string, 0, 2)
+	if name := l.name(city.City.Names); name != "" {
+		parts = append(parts, name)
+	}
+	if name := l.name(city.Country.Names); name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (l *dbLocator) name(names map[string]string) string {
+	if name, ok := names[l.lang]; ok {
+		return name
+	}
+	return names["en"]
+}
+
+func (l *dbLocator) Close() error {
+	return l.db.Close()
+}
+
+type nopLocator struct{}
+
+func (nopLocator) Locate(string) string { return "" }
+
+func (nopLocator) Close() error { return nil }
@@ -0,0 +1,342 @@
+package address
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type addressRoutes struct {
+	s *service.AddressService
+}
+
+func NewAddressRoutes(h *gin.RouterGroup, s *service.AddressService, jwtService service.JWTService, limits *ratelimit.Policies) {
+	g := h.Group("/user/addresses")
+
+	ar := addressRoutes{s: s}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	suggestLimit := limits.Limit("address_suggest", "120/1m", ratelimit.ByUser)
+	g.GET("", validateJWTmw, ar.GetAddresses)
+	g.POST("", validateJWTmw, ar.CreateAddress)
+	g.GET("/:id", validateJWTmw, ar.GetAddress)
+	g.PUT("/:id", validateJWTmw, ar.UpdateAddress)
+	g.DELETE("/:id", validateJWTmw, ar.DeleteAddress)
+	g.POST("/:id/default", validateJWTmw, ar.SetDefaultAddress)
+
+	h.GET("/address/suggest", validateJWTmw, suggestLimit, ar.Suggest)
+	h.POST("/address/normalize", validateJWTmw, suggestLimit, ar.Normalize)
+}
+
+// abortAddress отвечает клиенту по ошибке адресной книги
+func abortAddress(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvalidPhone), errors.Is(err, service.ErrAddressNotRecognized):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrTooManyAddresses):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
+
+type SuggestRequest struct {
+	Query string `form:"query" binding:"required,max=300"`
+	Count int    `form:"count" binding:"omitempty,min=1,max=20"`
+}
+
+// Suggest
+// @Summary     Address suggestions
+// @Description Suggestions for a partially typed address, with FIAS ID, postcode and coordinates
+// @Tags  	    address
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param       query query string true "Partially typed address"
+// @Param       count query int false "Number of suggestions, 10 by default"
+// @Success     200 {object} []model.AddressSuggestion
+// @Router      /address/suggest [get]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) Suggest(c *gin.Context) {
+	const op = "handlers.address.Suggest"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	suggestions, err := r.s.Suggest(c.Request.Context(), req.Query, req.Count)
+	if err != nil {
+		log.Error("cannot suggest address", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// Normalize
+// @Summary     Normalize address
+// @Description Clean up a free-text address and resolve its FIAS ID and postcode. Checkout applies the same step to a free-text address.
+// @Tags  	    address
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param       request body model.AddressNormalizeRequest true "request"
+// @Success     200 {object} model.AddressSuggestion
+// @Router      /address/normalize [post]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) Normalize(c *gin.Context) {
+	const op = "handlers.address.Normalize"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.AddressNormalizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	address, err := r.s.Normalize(c.Request.Context(), req.Address)
+	if err != nil {
+		if !errors.Is(err, service.ErrAddressNotRecognized) {
+			log.Error("cannot normalize address", sl.Err(err))
+		}
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+func addressID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetAddresses
+// @Summary     Saved addresses
+// @Description Saved delivery addresses, the default one first
+// @Tags  	    address
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {object} []model.UserAddress
+// @Router      /user/addresses [get]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) GetAddresses(c *gin.Context) {
+	const op = "handlers.address.GetAddresses"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	addresses, err := r.s.GetAddresses(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot get addresses", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, addresses)
+}
+
+// GetAddress
+// @Summary     Saved address
+// @Tags  	    address
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       id path int true "Address id"
+// @Success     200 {object} model.UserAddress
+// @Router      /user/addresses/{id} [get]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) GetAddress(c *gin.Context) {
+	const op = "handlers.address.GetAddress"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	address, err := r.s.GetAddress(c.GetInt64("user_id"), id)
+	if err != nil {
+		if !errors.Is(err, service.ErrAddressNotFound) {
+			log.Error("cannot get address", sl.Err(err))
+		}
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// CreateAddress
+// @Summary     Save address
+// @Description Save a delivery address. The first address becomes the default one.
+// @Tags  	    address
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param       request body model.UserAddressInput true "request"
+// @Success     201 {object} model.UserAddress
+// @Router      /user/addresses [post]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) CreateAddress(c *gin.Context) {
+	const op = "handlers.address.CreateAddress"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.UserAddressInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	address, err := r.s.CreateAddress(c.GetInt64("user_id"), req)
+	if err != nil {
+		log.Error("cannot create address", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+// UpdateAddress
+// @Summary     Update address
+// @Description Replace a saved address. Orders keep the address they were placed with.
+// @Tags  	    address
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       id path int true "Address id"
+// @Param       request body model.UserAddressInput true "request"
+// @Success     200 {object} model.UserAddress
+// @Router      /user/addresses/{id} [put]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) UpdateAddress(c *gin.Context) {
+	const op = "handlers.address.UpdateAddress"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	var req model.UserAddressInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	address, err := r.s.UpdateAddress(c.GetInt64("user_id"), id, req)
+	if err != nil {
+		log.Error("cannot update address", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// SetDefaultAddress
+// @Summary     Set default address
+// @Tags  	    address
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       id path int true "Address id"
+// @Success     200 {object} response.Response
+// @Router      /user/addresses/{id}/default [post]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) SetDefaultAddress(c *gin.Context) {
+	const op = "handlers.address.SetDefaultAddress"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.s.SetDefaultAddress(c.GetInt64("user_id"), id); err != nil {
+		log.Error("cannot set default address", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK())
+}
+
+// DeleteAddress
+// @Summary     Delete address
+// @Description Delete a saved address. If it was the default, the most recently updated one becomes the default.
+// @Tags  	    address
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       id path int true "Address id"
+// @Success     200 {object} response.Response
+// @Router      /user/addresses/{id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *addressRoutes) DeleteAddress(c *gin.Context) {
+	const op = "handlers.address.DeleteAddress"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.s.DeleteAddress(c.GetInt64("user_id"), id); err != nil {
+		log.Error("cannot delete address", sl.Err(err))
+		abortAddress(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK())
+}
@@ -0,0 +1,60 @@
+package business
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+// GetAnalytics
+// @Summary     Get sales analytics
+// @Description Revenue, orders, average order value, units and refunds over a date range bucketed by day, week or month, top products, views to cart to order funnel and stock-out forecast per product. Data comes from periodic rollups, updated_at shows the last one.
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param query query model.AnalyticsQuery false "period"
+// @Success     200 {object} model.BusinessAnalytics
+// @Router      /business/{id}/analytics [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetAnalytics(c *gin.Context) {
+	const op = "handlers.business.GetAnalytics"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var query model.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	analytics, err := r.a.GetBusinessAnalytics(id, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAnalyticsDate), errors.Is(err, service.ErrInvalidAnalyticsRange):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		default:
+			log.Error("cannot get business analytics", sl.Err(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
@@ -2,14 +2,15 @@ package business
 
 import (
 	"errors"
-	"github.com/RCSE2025/backend-go/internal/http/middleware/admin"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/policy"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
 	"github.com/RCSE2025/backend-go/pkg/logger/sl"
 	"github.com/gin-contrib/requestid"
 	"log/slog"
 	"strconv"
 
-	//"github.com/RCSE2025/backend-go/internal/http/middleware/admin"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/service"
@@ -19,64 +20,124 @@ import (
 )
 
 type businessRoutes struct {
-	s *service.BusinessService
+	s  *service.BusinessService
+	v  *service.BusinessVerificationService
+	m  *service.BusinessMemberService
+	sf *service.StorefrontService
+	a  *service.AnalyticsService
+	l  *service.LedgerService
+	p  *service.PayoutService
 }
 
-func NewBusinessRoutes(h *gin.RouterGroup, s *service.BusinessService, jwtService service.JWTService) {
+func NewBusinessRoutes(h *gin.RouterGroup, s *service.BusinessService, v *service.BusinessVerificationService, m *service.BusinessMemberService, sf *service.StorefrontService, a *service.AnalyticsService, l *service.LedgerService, p *service.PayoutService, jwtService service.JWTService, policyService *service.PolicyService, permissionService service.PermissionChecker, userService *service.UserService, limits *ratelimit.Policies) {
 	g := h.Group("/business")
 
 	ur := businessRoutes{s: s}
 
 	validateJWTmw := auth.ValidateJWT(jwtService)
-	onlyAdmin := admin.OnlyAdmin()
+	canReadAll := permission.RequirePermission(permissionService, model.PermBusinessRead)
+	canRead := policy.Business(policyService, service.ActionRead, "id")
+	canWrite := policy.Business(policyService, service.ActionWrite, "id")
+	canMembers := policy.Business(policyService, service.ActionMembers, "id")
+	canOwn := policy.Business(policyService, service.ActionOwn, "id")
+	canFinance := policy.Business(policyService, service.ActionFinance, "id")
+	canVerify := permission.RequirePermission(permissionService, model.PermBusinessVerify)
 
-	g.GET("/get_business_info/:inn", ur.GetBusinessInfoByINN)
+	// Каждый запрос расходует квоту DaData
+	g.GET("/get_business_info/:inn", limits.Limit("business_info", "30/1h", ratelimit.ByIP), ur.GetBusinessInfoByINN)
 
-	br := businessRoutes{s: s}
+	br := businessRoutes{s: s, v: v, m: m, sf: sf, a: a, l: l, p: p}
 
-	g.POST("", validateJWTmw, br.CreateBusiness)
-	g.GET("/all", validateJWTmw, onlyAdmin, br.GetAllBusinesses)
+	g.POST("", validateJWTmw, auth.RequireVerifiedEmail(userService), br.CreateBusiness)
+	g.GET("/all", validateJWTmw, canReadAll, br.GetAllBusinesses)
 	g.GET("/:id", validateJWTmw, br.GetBusinessByID)
-	g.PUT("/:id", validateJWTmw, br.UpdateBusiness)
-	g.DELETE("/:id", validateJWTmw, br.DeleteBusiness)
+	g.PUT("/:id", validateJWTmw, canWrite, br.UpdateBusiness)
+	g.DELETE("/:id", validateJWTmw, canOwn, br.DeleteBusiness)
 	g.GET("/inn/:inn", validateJWTmw, br.GetBusinessByINN)
 	g.GET("/ogrn/:ogrn", validateJWTmw, br.GetBusinessByOGRN)
 	g.GET("/user", validateJWTmw, br.GetUserBusinesses)
-	g.GET("/:id/users", validateJWTmw, br.GetBusinessUsers)
-	g.POST("/:id/user/:user_id", validateJWTmw, br.AddUserToBusiness)
-	g.DELETE("/:id/user/:user_id", validateJWTmw, br.RemoveUserFromBusiness)
+	g.GET("/:id/users", validateJWTmw, canRead, br.GetBusinessUsers)
+
+	// Участники добавляются только через приглашение на почту
+	inviteLimit := limits.Limit("business_invite", "20/1h", ratelimit.ByUser)
+
+	g.GET("/:id/members", validateJWTmw, canRead, br.GetMembers)
+	g.PUT("/:id/members/:user_id", validateJWTmw, canMembers, br.UpdateMemberRole)
+	// Выйти из бизнеса может любой участник, остальное проверяет сервис
+	g.DELETE("/:id/members/:user_id", validateJWTmw, canRead, br.RemoveMember)
+	g.POST("/:id/ownership", validateJWTmw, canOwn, br.TransferOwnership)
+	g.GET("/:id/invitations", validateJWTmw, canMembers, br.GetInvitations)
+	g.POST("/:id/invitations", validateJWTmw, canMembers, inviteLimit, br.InviteMember)
+	g.DELETE("/:id/invitations/:invitation_id", validateJWTmw, canMembers, br.RevokeInvitation)
+	g.POST("/invitations/accept", validateJWTmw, auth.RequireVerifiedEmail(userService), br.AcceptInvitation)
+	g.POST("/invitations/decline", validateJWTmw, auth.RequireVerifiedEmail(userService), br.DeclineInvitation)
+
+	g.PUT("/:id/storefront", validateJWTmw, canWrite, br.UpdateStorefront)
+	g.DELETE("/:id/storefront/:media", validateJWTmw, canWrite, br.RemoveStorefrontMedia)
+
+	g.GET("/:id/analytics", validateJWTmw, canFinance, br.GetAnalytics)
+	g.GET("/:id/balance", validateJWTmw, canFinance, br.GetBalance)
+	g.GET("/:id/payouts", validateJWTmw, canFinance, br.GetPayouts)
+	g.GET("/:id/statements/:month", validateJWTmw, canFinance, br.GetStatement)
+
+	// Витрина публичная: страница продавца доступна без авторизации
+	store := h.Group("/store")
+	store.GET("/:slug", br.GetStorefront)
+	store.GET("/:slug/products", br.GetStorefrontProducts)
+
+	// Подача заявки сверяет реквизиты через DaData
+	submitLimit := limits.Limit("business_verification", "5/1h", ratelimit.ByUser)
+	documentLimit := limits.Limit("business_document", "30/1h", ratelimit.ByUser)
+
+	g.GET("/verifications", validateJWTmw, canVerify, br.GetPendingVerifications)
+	g.GET("/:id/verification", validateJWTmw, canRead, br.GetVerification)
+	g.POST("/:id/verification", validateJWTmw, canWrite, submitLimit, br.SubmitVerification)
+	g.POST("/:id/verification/approve", validateJWTmw, canVerify, br.ApproveVerification)
+	g.POST("/:id/verification/reject", validateJWTmw, canVerify, br.RejectVerification)
+	g.GET("/:id/documents", validateJWTmw, canRead, br.GetDocuments)
+	g.POST("/:id/documents", validateJWTmw, canWrite, documentLimit, br.UploadDocument)
+	g.GET("/:id/documents/:doc_id", validateJWTmw, canRead, br.DownloadDocument)
+	g.DELETE("/:id/documents/:doc_id", validateJWTmw, canWrite, br.DeleteDocument)
 }
 
 // GetBusinessInfoByINN
 // @Summary     Get business info by INN from api
-// @Description Get business info by INN
+// @Description Get organization or individual entrepreneur details from EGRUL/EGRIP by INN
 // @Tags  	    business
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     503 {object} response.Response
 // @Failure     404 {object} response.Response
-// @Success     201 {object} model.User`
+// @Failure     400 {object} response.Response
+// @Success     200 {object} dadata.Party
 // @Router      /business/get_business_info/{inn} [get]
 // @Param inn path string true "inn"
 func (ur *businessRoutes) GetBusinessInfoByINN(c *gin.Context) {
-	inn := c.Param("inn")
+	const op = "handlers.business.GetBusinessInfoByINN"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
 
-	resp, err := ur.s.GetBusinessInfoByINN(inn)
+	party, err := ur.s.GetBusinessInfoByINN(c.Request.Context(), c.Param("inn"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Error("invalid inn"))
+		log.Error("cannot get business info", sl.Err(err))
+		abortBusiness(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, party)
 }
 
 // CreateBusiness
 // @Summary     Create business
-// @Description Create new business
+// @Description Create new business. Empty OGRN, names, address and owner are filled from EGRUL by INN
 // @Tags  	    business
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
 // @Failure     400 {object} response.Response
 // @Param request body model.Business true "request"
 // @Success     201 {object} model.Business
@@ -97,16 +158,31 @@ func (r *businessRoutes) CreateBusiness(c *gin.Context) {
 	}
 
 	userID := c.GetInt64("user_id")
-	err := r.s.CreateBusiness(userID, business)
+	business, err := r.s.CreateBusiness(c.Request.Context(), userID, business)
 	if err != nil {
 		log.Error("cannot create business", sl.Err(err))
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		abortBusiness(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, business)
 }
 
+func abortBusiness(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidINN), errors.Is(err, service.ErrInvalidOGRN):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrBusinessExists), errors.Is(err, service.ErrRequisitesLocked):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	case errors.Is(err, service.ErrBusinessNotFound), errors.Is(err, service.ErrBusinessInfoNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrBusinessInfoUnavailable):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.Error("business info is unavailable"))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+	}
+}
+
 // GetAllBusinesses
 // @Summary     Get all businesses
 // @Description Get all businesses
@@ -187,6 +263,9 @@ func (r *businessRoutes) GetBusinessByID(c *gin.Context) {
 // @Produce     json
 // @Failure     500 {object} response.Response
 // @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     400 {object} response.Response
 // @Param id path string true "id"
 // @Param request body model.Business true "request"
 // @Success     200 {object} response.Response
@@ -221,11 +300,7 @@ func (r *businessRoutes) UpdateBusiness(c *gin.Context) {
 	err = r.s.UpdateBusiness(idInt, business)
 	if err != nil {
 		log.Error("cannot update business", sl.Err(err))
-		if errors.Is(err, service.ErrBusinessNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		abortBusiness(c, err)
 		return
 	}
 
@@ -240,6 +315,7 @@ func (r *businessRoutes) UpdateBusiness(c *gin.Context) {
 // @Produce     json
 // @Failure     500 {object} response.Response
 // @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
 // @Param id path string true "id"
 // @Success     200 {object} response.Response
 // @Router      /business/{id} [delete]
@@ -403,6 +479,7 @@ func (r *businessRoutes) GetUserBusinesses(c *gin.Context) {
 // @Produce     json
 // @Failure     500 {object} response.Response
 // @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
 // @Param id path string true "id"
 // @Success     200 {array} model.User
 // @Router      /business/{id}/users [get]
@@ -439,119 +516,3 @@ func (r *businessRoutes) GetBusinessUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, users)
 }
-
-// AddUserToBusiness
-// @Summary     Add user to business
-// @Description Add user to business
-// @Tags  	    business
-// @Accept      json
-// @Produce     json
-// @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
-// @Param id path string true "business id"
-// @Param user_id path string true "user id"
-// @Success     200 {object} response.Response
-// @Router      /business/{id}/user/{user_id} [post]
-// @Security OAuth2PasswordBearer
-func (r *businessRoutes) AddUserToBusiness(c *gin.Context) {
-	const op = "handlers.business.AddUserToBusiness"
-	log := logger.FromContext(c).With(
-		slog.String("op", op),
-		slog.String("request_id", requestid.Get(c)),
-	)
-
-	id := c.Param("id")
-	if id == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid business id param"))
-		return
-	}
-
-	idInt, err := strconv.ParseInt(id, 10, 64)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse business id"))
-		return
-	}
-
-	userID := c.Param("user_id")
-	if userID == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid user id param"))
-		return
-	}
-
-	userIDInt, err := strconv.ParseInt(userID, 10, 64)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse user id"))
-		return
-	}
-
-	err = r.s.AddUserToBusiness(userIDInt, idInt)
-	if err != nil {
-		log.Error("cannot add user to business", sl.Err(err))
-		if errors.Is(err, service.ErrBusinessNotFound) || errors.Is(err, service.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, response.Success("user added to business"))
-}
-
-// RemoveUserFromBusiness
-// @Summary     Remove user from business
-// @Description Remove user from business
-// @Tags  	    business
-// @Accept      json
-// @Produce     json
-// @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
-// @Param id path string true "business id"
-// @Param user_id path string true "user id"
-// @Success     200 {object} response.Response
-// @Router      /business/{id}/user/{user_id} [delete]
-// @Security OAuth2PasswordBearer
-func (r *businessRoutes) RemoveUserFromBusiness(c *gin.Context) {
-	const op = "handlers.business.RemoveUserFromBusiness"
-	log := logger.FromContext(c).With(
-		slog.String("op", op),
-		slog.String("request_id", requestid.Get(c)),
-	)
-
-	id := c.Param("id")
-	if id == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid business id param"))
-		return
-	}
-
-	idInt, err := strconv.ParseInt(id, 10, 64)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse business id"))
-		return
-	}
-
-	userID := c.Param("user_id")
-	if userID == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid user id param"))
-		return
-	}
-
-	userIDInt, err := strconv.ParseInt(userID, 10, 64)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse user id"))
-		return
-	}
-
-	err = r.s.RemoveUserFromBusiness(userIDInt, idInt)
-	if err != nil {
-		log.Error("cannot remove user from business", sl.Err(err))
-		if errors.Is(err, service.ErrBusinessNotFound) || errors.Is(err, service.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, response.Success("user removed from business"))
-}
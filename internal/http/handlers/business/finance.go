@@ -0,0 +1,131 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+// GetBalance
+// @Summary     Get seller balance
+// @Description What the platform owes the business in kopecks. available is the part that goes into the next payout: receipts are held for the refund period. in_transit is sent to the bank and awaits confirmation.
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} model.SellerBalance
+// @Router      /business/{id}/balance [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetBalance(c *gin.Context) {
+	const op = "handlers.business.GetBalance"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	balance, err := r.l.GetSellerBalance(id)
+	if err != nil {
+		log.Error("cannot get seller balance", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetPayouts
+// @Summary     Get seller payouts
+// @Description Last 100 payouts of the business, amounts in kopecks
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {array} model.Payout
+// @Router      /business/{id}/payouts [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetPayouts(c *gin.Context) {
+	const op = "handlers.business.GetPayouts"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	payouts, err := r.p.GetBusinessPayouts(id)
+	if err != nil {
+		log.Error("cannot get payouts", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, payouts)
+}
+
+// GetStatement
+// @Summary     Get monthly statement
+// @Description Sales, refunds, commission and payouts of the business for a month with opening and closing balance. Amounts in kopecks; with format=csv returns a semicolon separated file with amounts in rubles for accounting software.
+// @Tags  	    business
+// @Produce     json
+// @Produce     text/csv
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param month path string true "month" example(2026-01)
+// @Param format query string false "json or csv"
+// @Success     200 {object} model.Statement
+// @Router      /business/{id}/statements/{month} [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetStatement(c *gin.Context) {
+	const op = "handlers.business.GetStatement"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	statement, err := r.l.GetStatement(id, c.Param("month"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatementMonth) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+		log.Error("cannot get statement", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, statement)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%d-%s.csv"`, id, statement.Month))
+	c.Status(http.StatusOK)
+	if err := service.WriteStatementCSV(c.Writer, statement); err != nil {
+		log.Error("cannot write statement", sl.Err(err))
+	}
+}
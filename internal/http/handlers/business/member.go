@@ -0,0 +1,408 @@
+package business
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// abortMember отвечает клиенту по ошибке работы с участниками и приглашениями
+func abortMember(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBusinessNotFound), errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrInvitationNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrForbidden), errors.Is(err, service.ErrInvitationForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Error(err.Error()))
+	case errors.Is(err, service.ErrOwnershipToSelf):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrLastOwner), errors.Is(err, service.ErrAlreadyMember):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvitationExpired):
+		c.AbortWithStatusJSON(http.StatusGone, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+	}
+}
+
+// parsePathID разбирает параметр :id и второй параметр name, при ошибке отвечает 400
+func parsePathID(c *gin.Context, name string) (int64, int64, bool) {
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	second, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse "+name))
+		return 0, 0, false
+	}
+	return id, second, true
+}
+
+// GetMembers
+// @Summary     Get business members
+// @Description Get business members with their roles
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} []model.BusinessMember
+// @Router      /business/{id}/members [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetMembers(c *gin.Context) {
+	const op = "handlers.business.GetMembers"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	members, err := r.m.GetMembers(id)
+	if err != nil {
+		log.Error("cannot get business members", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// UpdateMemberRole
+// @Summary     Change member role
+// @Description Change the role of a business member. Managers cannot change owners or grant the owner role. The last owner cannot be demoted.
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param user_id path string true "user id"
+// @Param request body model.MemberRoleUpdate true "request"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/members/{user_id} [put]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) UpdateMemberRole(c *gin.Context) {
+	const op = "handlers.business.UpdateMemberRole"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, userID, ok := parsePathID(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var req model.MemberRoleUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	actorID := c.GetInt64("user_id")
+	if err := r.m.UpdateMemberRole(c.Request.Context(), id, actorID, userID, req.Role); err != nil {
+		log.Warn("cannot change member role", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	log.Info("member role changed", slog.Int64("business_id", id), slog.Int64("member_id", userID),
+		slog.String("role", string(req.Role)), slog.Int64("actor_id", actorID))
+	c.JSON(http.StatusOK, response.Success("member role changed"))
+}
+
+// RemoveMember
+// @Summary     Remove member from business
+// @Description Remove a member from the business. Any member may leave; owners and managers may remove others, managers cannot remove owners. The last owner cannot be removed.
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Param user_id path string true "user id"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/members/{user_id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) RemoveMember(c *gin.Context) {
+	const op = "handlers.business.RemoveMember"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, userID, ok := parsePathID(c, "user_id")
+	if !ok {
+		return
+	}
+
+	actorID := c.GetInt64("user_id")
+	if err := r.m.RemoveMember(c.Request.Context(), id, actorID, userID); err != nil {
+		log.Warn("cannot remove member", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	log.Info("member removed", slog.Int64("business_id", id), slog.Int64("member_id", userID), slog.Int64("actor_id", actorID))
+	c.JSON(http.StatusOK, response.Success("member removed"))
+}
+
+// TransferOwnership
+// @Summary     Transfer business ownership
+// @Description Make another member an owner. The current owner becomes a manager.
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.OwnershipTransfer true "request"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/ownership [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) TransferOwnership(c *gin.Context) {
+	const op = "handlers.business.TransferOwnership"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req model.OwnershipTransfer
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	actorID := c.GetInt64("user_id")
+	if err := r.m.TransferOwnership(id, actorID, req.UserID); err != nil {
+		log.Warn("cannot transfer ownership", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	log.Info("business ownership transferred", slog.Int64("business_id", id), slog.Int64("from", actorID), slog.Int64("to", req.UserID))
+	c.JSON(http.StatusOK, response.Success("ownership transferred"))
+}
+
+// GetInvitations
+// @Summary     Get business invitations
+// @Description Get pending invitations of the business
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} []model.BusinessInvitation
+// @Router      /business/{id}/invitations [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetInvitations(c *gin.Context) {
+	const op = "handlers.business.GetInvitations"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	invs, err := r.m.GetInvitations(id)
+	if err != nil {
+		log.Error("cannot get business invitations", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invs)
+}
+
+// InviteMember
+// @Summary     Invite member by email
+// @Description Send an invitation link to the email. The link expires; inviting the same email again revokes the previous link. Only owners may invite owners.
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.BusinessInvitationCreate true "request"
+// @Success     201 {object} model.BusinessInvitation
+// @Router      /business/{id}/invitations [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) InviteMember(c *gin.Context) {
+	const op = "handlers.business.InviteMember"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req model.BusinessInvitationCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	inv, err := r.m.Invite(c.Request.Context(), id, c.GetInt64("user_id"), req)
+	if err != nil {
+		log.Warn("cannot invite member", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	log.Info("member invited", slog.Int64("business_id", id), slog.Int64("invitation_id", inv.ID), slog.String("role", string(inv.Role)))
+	c.JSON(http.StatusCreated, inv)
+}
+
+// RevokeInvitation
+// @Summary     Revoke invitation
+// @Description Revoke a pending invitation. Only owners may revoke invitations of owners.
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Param invitation_id path string true "invitation id"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/invitations/{invitation_id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) RevokeInvitation(c *gin.Context) {
+	const op = "handlers.business.RevokeInvitation"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, invID, ok := parsePathID(c, "invitation_id")
+	if !ok {
+		return
+	}
+
+	if err := r.m.RevokeInvitation(c.Request.Context(), id, c.GetInt64("user_id"), invID); err != nil {
+		log.Warn("cannot revoke invitation", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("invitation revoked"))
+}
+
+// AcceptInvitation
+// @Summary     Accept business invitation
+// @Description Join the business with the role from the invitation. The invitation must be addressed to the current user's email.
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     410 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param request body model.BusinessInvitationToken true "request"
+// @Success     200 {object} model.Business
+// @Router      /business/invitations/accept [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) AcceptInvitation(c *gin.Context) {
+	const op = "handlers.business.AcceptInvitation"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.BusinessInvitationToken
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	userID := c.GetInt64("user_id")
+	business, err := r.m.AcceptInvitation(userID, req.Token)
+	if err != nil {
+		log.Warn("cannot accept invitation", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	log.Info("invitation accepted", slog.Int64("business_id", business.ID), slog.Int64("user_id", userID))
+	c.JSON(http.StatusOK, business)
+}
+
+// DeclineInvitation
+// @Summary     Decline business invitation
+// @Description Decline an invitation addressed to the current user's email
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     410 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param request body model.BusinessInvitationToken true "request"
+// @Success     200 {object} response.Response
+// @Router      /business/invitations/decline [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) DeclineInvitation(c *gin.Context) {
+	const op = "handlers.business.DeclineInvitation"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.BusinessInvitationToken
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.m.DeclineInvitation(c.GetInt64("user_id"), req.Token); err != nil {
+		log.Warn("cannot decline invitation", sl.Err(err))
+		abortMember(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("invitation declined"))
+}
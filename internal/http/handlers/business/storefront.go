@@ -0,0 +1,175 @@
+package business
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+// abortStorefront отвечает клиенту по ошибке работы с витриной
+func abortStorefront(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrStorefrontNotFound), errors.Is(err, service.ErrBusinessNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrInvalidMedia):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrSlugTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+	}
+}
+
+// GetStorefront
+// @Summary     Get seller storefront
+// @Description Public page of a verified seller by slug or business id: name, description, logo, banner, rating aggregated from reviews of published products and the legal details required for distance selling
+// @Tags  	    storefront
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param slug path string true "slug or business id"
+// @Success     200 {object} model.Storefront
+// @Router      /store/{slug} [get]
+func (r *businessRoutes) GetStorefront(c *gin.Context) {
+	const op = "handlers.business.GetStorefront"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	storefront, err := r.sf.GetStorefront(c.Param("slug"))
+	if err != nil {
+		if !errors.Is(err, service.ErrStorefrontNotFound) {
+			log.Error("cannot get storefront", sl.Err(err))
+		}
+		abortStorefront(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, storefront)
+}
+
+// GetStorefrontProducts
+// @Summary     Get storefront products
+// @Description Published products of a verified seller with the catalog filters, sorting and pagination
+// @Tags  	    storefront
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param slug path string true "slug or business id"
+// @Param filters query model.ProductQueryParams false "Filter criteria"
+// @Success     200 {object} model.ProductPage
+// @Router      /store/{slug}/products [get]
+func (r *businessRoutes) GetStorefrontProducts(c *gin.Context) {
+	const op = "handlers.business.GetStorefrontProducts"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var filters model.ProductQueryParams
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		log.Error("cannot parse filters", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid filter data"))
+		return
+	}
+
+	page, err := r.sf.GetProducts(c.Request.Context(), c.Param("slug"), filters)
+	if err != nil {
+		if !errors.Is(err, service.ErrStorefrontNotFound) {
+			log.Error("cannot get storefront products", sl.Err(err))
+		}
+		abortStorefront(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// UpdateStorefront
+// @Summary     Update storefront
+// @Description Change storefront slug and description. An empty string clears the field. Logo and banner are uploaded via /uploads with business_logo and business_banner targets.
+// @Tags  	    storefront
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.StorefrontUpdate true "request"
+// @Success     200 {object} model.Business
+// @Router      /business/{id}/storefront [put]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) UpdateStorefront(c *gin.Context) {
+	const op = "handlers.business.UpdateStorefront"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req model.StorefrontUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	business, err := r.sf.UpdateStorefront(id, req)
+	if err != nil {
+		log.Warn("cannot update storefront", sl.Err(err))
+		abortStorefront(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, business)
+}
+
+// RemoveStorefrontMedia
+// @Summary     Remove storefront logo or banner
+// @Description Remove storefront logo or banner
+// @Tags  	    storefront
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param media path string true "logo or banner"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/storefront/{media} [delete]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) RemoveStorefrontMedia(c *gin.Context) {
+	const op = "handlers.business.RemoveStorefrontMedia"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.sf.RemoveMedia(c.Request.Context(), id, model.StorefrontMedia(c.Param("media"))); err != nil {
+		log.Warn("cannot remove storefront media", sl.Err(err))
+		abortStorefront(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("storefront media removed"))
+}
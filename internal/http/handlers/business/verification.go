@@ -0,0 +1,409 @@
+package business
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// abortVerification отвечает клиенту по ошибке проверки бизнеса или работы с документами
+func abortVerification(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBusinessNotFound), errors.Is(err, service.ErrDocumentNotFound),
+		errors.Is(err, service.ErrVerificationNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvalidDocumentKind), errors.Is(err, service.ErrUnsupportedDocument):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrDocumentTooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.Error(err.Error()))
+	case errors.Is(err, service.ErrVerificationPending), errors.Is(err, service.ErrBusinessAlreadyVerified),
+		errors.Is(err, service.ErrVerificationNotPending), errors.Is(err, service.ErrTooManyDocuments):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	case errors.Is(err, service.ErrDocumentsRequired), errors.Is(err, service.ErrBusinessNotInRegistry),
+		errors.Is(err, service.ErrBusinessInactive), errors.Is(err, service.ErrBusinessRegistryOGRN),
+		errors.Is(err, service.ErrBusinessNameMismatch):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+	}
+}
+
+// parseBusinessID разбирает параметр :id, при ошибке отвечает 400
+func parseBusinessID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// parseDocumentPath разбирает параметры :id и :doc_id, при ошибке отвечает 400
+func parseDocumentPath(c *gin.Context) (int64, int64, bool) {
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	docID, err := strconv.ParseInt(c.Param("doc_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse doc_id"))
+		return 0, 0, false
+	}
+	return id, docID, true
+}
+
+// GetVerification
+// @Summary     Get business verification
+// @Description Get the latest verification request of the business with the registry cross-check result and review decision
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} model.BusinessVerification
+// @Router      /business/{id}/verification [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetVerification(c *gin.Context) {
+	const op = "handlers.business.GetVerification"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	v, err := r.v.GetVerification(id)
+	if err != nil {
+		log.Error("cannot get business verification", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v)
+}
+
+// SubmitVerification
+// @Summary     Request business verification
+// @Description Cross-check the business with EGRUL/EGRIP (active status, OGRN and name) and queue it for admin review of the uploaded documents. Only verified businesses may publish products and receive payouts.
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     422 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param id path string true "id"
+// @Success     202 {object} model.BusinessVerification
+// @Router      /business/{id}/verification [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) SubmitVerification(c *gin.Context) {
+	const op = "handlers.business.SubmitVerification"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	v, err := r.v.Submit(c.Request.Context(), id, c.GetInt64("user_id"))
+	if err != nil {
+		log.Warn("cannot submit business verification", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	log.Info("business verification requested", slog.Int64("business_id", id), slog.String("registry_status", v.RegistryStatus))
+	c.JSON(http.StatusAccepted, v)
+}
+
+// GetPendingVerifications
+// @Summary     Get pending business verifications
+// @Description Get verification requests waiting for review, oldest first
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Success     200 {array} model.BusinessVerification
+// @Router      /business/verifications [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetPendingVerifications(c *gin.Context) {
+	const op = "handlers.business.GetPendingVerifications"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	vs, err := r.v.GetPendingVerifications()
+	if err != nil {
+		log.Error("cannot get pending verifications", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vs)
+}
+
+// ApproveVerification
+// @Summary     Approve business verification
+// @Description Mark the business as verified after reviewing its documents
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.BusinessVerificationReview false "request"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/verification/approve [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) ApproveVerification(c *gin.Context) {
+	const op = "handlers.business.ApproveVerification"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req model.BusinessVerificationReview
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("cannot parse request", sl.Err(err))
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+	}
+
+	if err := r.v.Approve(id, c.GetInt64("user_id"), req.Comment); err != nil {
+		log.Error("cannot approve business verification", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	log.Info("business verified", slog.Int64("business_id", id), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, response.Success("business verified"))
+}
+
+// RejectVerification
+// @Summary     Reject business verification
+// @Description Reject a pending verification request or revoke the verification of a business. Products of the business are unpublished.
+// @Tags  	    business
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.BusinessVerificationReject true "request"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/verification/reject [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) RejectVerification(c *gin.Context) {
+	const op = "handlers.business.RejectVerification"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req model.BusinessVerificationReject
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.v.Reject(id, c.GetInt64("user_id"), req.Reason); err != nil {
+		log.Error("cannot reject business verification", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	log.Info("business verification rejected", slog.Int64("business_id", id), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, response.Success("business verification rejected"))
+}
+
+// GetDocuments
+// @Summary     Get business documents
+// @Description Get documents uploaded for business verification
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {array} model.BusinessDocument
+// @Router      /business/{id}/documents [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) GetDocuments(c *gin.Context) {
+	const op = "handlers.business.GetDocuments"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	docs, err := r.v.GetDocuments(id)
+	if err != nil {
+		log.Error("cannot get business documents", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, docs)
+}
+
+// UploadDocument
+// @Summary     Upload business document
+// @Description Upload a PDF, JPEG or PNG document for verification. Documents cannot be changed while verification is pending or after it is approved.
+// @Tags  	    business
+// @Accept      multipart/form-data
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     413 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param id path string true "id"
+// @Param kind formData string true "registration, charter, authority or other"
+// @Param file formData file true "document"
+// @Success     201 {object} model.BusinessDocument
+// @Router      /business/{id}/documents [post]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) UploadDocument(c *gin.Context) {
+	const op = "handlers.business.UploadDocument"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseBusinessID(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Error("cannot parse form", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("file is required"))
+		return
+	}
+
+	kind := model.BusinessDocumentKind(c.PostForm("kind"))
+	doc, err := r.v.UploadDocument(c.Request.Context(), id, c.GetInt64("user_id"), kind, file)
+	if err != nil {
+		log.Error("cannot upload business document", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, doc)
+}
+
+// DownloadDocument
+// @Summary     Download business document
+// @Description Download a document uploaded for business verification
+// @Tags  	    business
+// @Produce     application/octet-stream
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path string true "id"
+// @Param doc_id path string true "document id"
+// @Success     200 {file} file
+// @Router      /business/{id}/documents/{doc_id} [get]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) DownloadDocument(c *gin.Context) {
+	const op = "handlers.business.DownloadDocument"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, docID, ok := parseDocumentPath(c)
+	if !ok {
+		return
+	}
+
+	rc, doc, err := r.v.OpenDocument(c.Request.Context(), id, docID)
+	if err != nil {
+		log.Error("cannot open business document", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, doc.Size, doc.ContentType, rc, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": doc.FileName}),
+	})
+}
+
+// DeleteDocument
+// @Summary     Delete business document
+// @Description Delete a document uploaded for business verification
+// @Tags  	    business
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param id path string true "id"
+// @Param doc_id path string true "document id"
+// @Success     200 {object} response.Response
+// @Router      /business/{id}/documents/{doc_id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *businessRoutes) DeleteDocument(c *gin.Context) {
+	const op = "handlers.business.DeleteDocument"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, docID, ok := parseDocumentPath(c)
+	if !ok {
+		return
+	}
+
+	if err := r.v.DeleteDocument(c.Request.Context(), id, docID); err != nil {
+		log.Error("cannot delete business document", sl.Err(err))
+		abortVerification(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("document deleted"))
+}
@@ -15,12 +15,13 @@ import (
 
 type cartRoutes struct {
 	cartService *service.CartService
+	analytics   *service.AnalyticsService
 }
 
-func NewCartRoutes(h *gin.RouterGroup, cs *service.CartService, jwtService service.JWTService) {
+func NewCartRoutes(h *gin.RouterGroup, cs *service.CartService, analytics *service.AnalyticsService, jwtService service.JWTService) {
 	g := h.Group("/cart")
 
-	ur := cartRoutes{cartService: cs}
+	ur := cartRoutes{cartService: cs, analytics: analytics}
 
 	validateJWTmw := auth.ValidateJWT(jwtService)
 
@@ -60,12 +61,13 @@ func (cr *cartRoutes) PostCartProduct(c *gin.Context) {
 		return
 	}
 
-	cart, err := cr.cartService.PostInCart(c.GetInt64("user_id"), req.ProductID, req.Quantity)
+	cart, product, err := cr.cartService.PostInCart(c.GetInt64("user_id"), req.ProductID, req.Quantity)
 	if err != nil {
 		log.Error("cannot post product in cart", sl.Err(err))
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
 		return
 	}
+	cr.analytics.Track(model.ProductEventCart, product, c.GetInt64("user_id"))
 	c.JSON(http.StatusOK, cart)
 }
 
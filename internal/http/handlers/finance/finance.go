@@ -0,0 +1,382 @@
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type financeRoutes struct {
+	l *service.LedgerService
+	p *service.PayoutService
+}
+
+func NewFinanceRoutes(h *gin.RouterGroup, l *service.LedgerService, p *service.PayoutService, jwtService service.JWTService, permissionService *service.PermissionService) {
+	g := h.Group("/finance")
+
+	fr := financeRoutes{l: l, p: p}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	canManage := permission.RequirePermission(permissionService, model.PermFinanceManage)
+
+	g.GET("/balance", validateJWTmw, canManage, fr.GetPlatformBalance)
+	g.GET("/commissions", validateJWTmw, canManage, fr.GetCommissionRules)
+	g.POST("/commissions", validateJWTmw, canManage, fr.SaveCommissionRule)
+	g.DELETE("/commissions/:id", validateJWTmw, canManage, fr.DeleteCommissionRule)
+	g.GET("/payouts/batches", validateJWTmw, canManage, fr.GetPayoutBatches)
+	g.GET("/payouts/batches/:id", validateJWTmw, canManage, fr.GetPayoutBatch)
+	g.GET("/payouts/batches/:id/export", validateJWTmw, canManage, fr.ExportPayoutBatch)
+	g.POST("/payouts/:id/paid", validateJWTmw, canManage, fr.MarkPayoutPaid)
+	g.POST("/payouts/:id/failed", validateJWTmw, canManage, fr.MarkPayoutFailed)
+}
+
+// abortFinance отвечает клиенту по ошибке расчетов с продавцами
+func abortFinance(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCommissionRuleNotFound), errors.Is(err, service.ErrBusinessNotFound),
+		errors.Is(err, service.ErrPayoutNotFound), errors.Is(err, service.ErrPayoutBatchNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvalidCategory):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrPayoutNotPending):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("internal error"))
+	}
+}
+
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetPlatformBalance
+// @Summary     Get platform ledger balance
+// @Description Balances of platform ledger accounts in kopecks for reconciliation with Yookassa: cash held, owed to sellers, earned commission and payouts in transit
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Success     200 {object} model.PlatformBalance
+// @Router      /finance/balance [get]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) GetPlatformBalance(c *gin.Context) {
+	const op = "handlers.finance.GetPlatformBalance"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	balance, err := r.l.GetPlatformBalance()
+	if err != nil {
+		log.Error("cannot get platform balance", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetCommissionRules
+// @Summary     Get commission rules
+// @Description Platform commission in percent per business, category or both. Orders without a matching rule use the default rate.
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Success     200 {array} model.CommissionRule
+// @Router      /finance/commissions [get]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) GetCommissionRules(c *gin.Context) {
+	const op = "handlers.finance.GetCommissionRules"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	rules, err := r.l.GetCommissionRules()
+	if err != nil {
+		log.Error("cannot get commission rules", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// SaveCommissionRule
+// @Summary     Save commission rule
+// @Description Set commission for a business, a category or a business within a category. A rule for the same pair is replaced. Applies to orders paid after the change.
+// @Tags  	    finance
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param request body model.CommissionRuleCreate true "request"
+// @Success     200 {object} model.CommissionRule
+// @Router      /finance/commissions [post]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) SaveCommissionRule(c *gin.Context) {
+	const op = "handlers.finance.SaveCommissionRule"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.CommissionRuleCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	rule, err := r.l.SaveCommissionRule(req)
+	if err != nil {
+		log.Warn("cannot save commission rule", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	log.Info("commission rule saved", slog.Int64("id", rule.ID), slog.Float64("rate", rule.Rate), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteCommissionRule
+// @Summary     Delete commission rule
+// @Description Delete commission rule
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} response.Response
+// @Router      /finance/commissions/{id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) DeleteCommissionRule(c *gin.Context) {
+	const op = "handlers.finance.DeleteCommissionRule"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.l.DeleteCommissionRule(id); err != nil {
+		log.Warn("cannot delete commission rule", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	log.Info("commission rule deleted", slog.Int64("id", id), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, response.Success("commission rule deleted"))
+}
+
+// GetPayoutBatches
+// @Summary     Get payout batches
+// @Description Last 100 payout batches, amounts in kopecks
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Success     200 {array} model.PayoutBatch
+// @Router      /finance/payouts/batches [get]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) GetPayoutBatches(c *gin.Context) {
+	const op = "handlers.finance.GetPayoutBatches"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	batches, err := r.p.GetBatches()
+	if err != nil {
+		log.Error("cannot get payout batches", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, batches)
+}
+
+// GetPayoutBatch
+// @Summary     Get payout batch
+// @Description Payout batch with its payouts, amounts in kopecks
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} model.PayoutBatch
+// @Router      /finance/payouts/batches/{id} [get]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) GetPayoutBatch(c *gin.Context) {
+	const op = "handlers.finance.GetPayoutBatch"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	batch, err := r.p.GetBatch(id)
+	if err != nil {
+		if !errors.Is(err, service.ErrPayoutBatchNotFound) {
+			log.Error("cannot get payout batch", sl.Err(err))
+		}
+		abortFinance(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// ExportPayoutBatch
+// @Summary     Export payout batch
+// @Description Semicolon separated file with pending payouts of the batch for the bank: recipient INN, name and amount in rubles
+// @Tags  	    finance
+// @Produce     text/csv
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {file} file
+// @Router      /finance/payouts/batches/{id}/export [get]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) ExportPayoutBatch(c *gin.Context) {
+	const op = "handlers.finance.ExportPayoutBatch"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := r.p.GetBatch(id); err != nil {
+		if !errors.Is(err, service.ErrPayoutBatchNotFound) {
+			log.Error("cannot get payout batch", sl.Err(err))
+		}
+		abortFinance(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="payouts-%d.csv"`, id))
+	c.Status(http.StatusOK)
+	if err := r.p.ExportBatchCSV(c.Writer, id); err != nil {
+		log.Error("cannot export payout batch", sl.Err(err))
+	}
+}
+
+// MarkPayoutPaid
+// @Summary     Mark payout paid
+// @Description Record that the bank has transferred the payout
+// @Tags  	    finance
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} model.Payout
+// @Router      /finance/payouts/{id}/paid [post]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) MarkPayoutPaid(c *gin.Context) {
+	const op = "handlers.finance.MarkPayoutPaid"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	payout, err := r.p.MarkPaid(id)
+	if err != nil {
+		log.Warn("cannot mark payout paid", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	log.Info("payout paid", slog.Int64("id", id), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, payout)
+}
+
+// MarkPayoutFailed
+// @Summary     Mark payout failed
+// @Description Record that the bank has rejected the payout. The amount returns to the seller balance and goes into the next batch.
+// @Tags  	    finance
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Param request body model.PayoutFailure true "request"
+// @Success     200 {object} model.Payout
+// @Router      /finance/payouts/{id}/failed [post]
+// @Security OAuth2PasswordBearer
+func (r *financeRoutes) MarkPayoutFailed(c *gin.Context) {
+	const op = "handlers.finance.MarkPayoutFailed"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.PayoutFailure
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	payout, err := r.p.MarkFailed(id, req.Reason)
+	if err != nil {
+		log.Warn("cannot mark payout failed", sl.Err(err))
+		abortFinance(c, err)
+		return
+	}
+
+	log.Info("payout failed", slog.Int64("id", id), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, payout)
+}
@@ -0,0 +1,248 @@
+package oauth
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type oauthRoutes struct {
+	s *service.OAuthService
+}
+
+func NewOAuthRoutes(h *gin.RouterGroup, s *service.OAuthService, jwtService service.JWTService, limits *ratelimit.Policies) {
+	g := h.Group("/user/oauth")
+
+	or := oauthRoutes{s: s}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	loginLimit := limits.Limit("login", "20/1m", ratelimit.ByIP)
+
+	g.GET("/providers", or.Providers)
+	g.GET("/identities", validateJWTmw, or.Identities)
+	g.GET("/:provider/authorize", or.Authorize)
+	g.POST("/:provider/callback", loginLimit, or.Callback)
+	g.POST("/:provider/link", validateJWTmw, or.AuthorizeLink)
+	g.POST("/:provider/link/callback", validateJWTmw, or.LinkCallback)
+	g.DELETE("/:provider", validateJWTmw, or.Unlink)
+}
+
+// abortOAuth отвечает клиенту по ошибке входа через провайдера
+func abortOAuth(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownOAuthProvider), errors.Is(err, service.ErrIdentityNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrInvalidOAuthState), errors.Is(err, service.ErrOAuthExchangeFailed),
+		errors.Is(err, service.ErrOAuthEmailRequired):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrOAuthEmailConflict), errors.Is(err, service.ErrIdentityLinked),
+		errors.Is(err, service.ErrProviderAlreadyLinked), errors.Is(err, service.ErrLastLoginMethod):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
+
+// Providers
+// @Summary     OAuth providers
+// @Description Names of the enabled external login providers
+// @Tags  	    oauth
+// @Produce     json
+// @Success     200 {object} model.OAuthProviders
+// @Router      /user/oauth/providers [get]
+func (r *oauthRoutes) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, model.OAuthProviders{Providers: r.s.Providers()})
+}
+
+// Authorize
+// @Summary     Start OAuth login
+// @Description Get the provider authorization URL (authorization code flow with PKCE). After login the provider redirects to the frontend callback with code and state.
+// @Tags  	    oauth
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       provider path string true "Provider, e.g. vkid or yandex"
+// @Success     200 {object} model.OAuthAuthorization
+// @Router      /user/oauth/{provider}/authorize [get]
+func (r *oauthRoutes) Authorize(c *gin.Context) {
+	const op = "handlers.oauth.Authorize"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	url, err := r.s.Authorize(c.Param("provider"), nil)
+	if err != nil {
+		log.Error("cannot start oauth login", sl.Err(err))
+		abortOAuth(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.OAuthAuthorization{AuthorizationURL: url})
+}
+
+// Callback
+// @Summary     Complete OAuth login
+// @Description Exchange code and state from the provider redirect for access and refresh tokens. Registers a new user or links the provider by verified email.
+// @Tags  	    oauth
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param       provider path string true "Provider"
+// @Param       request body model.OAuthCallback true "request"
+// @Success     200 {object} model.Token
+// @Router      /user/oauth/{provider}/callback [post]
+func (r *oauthRoutes) Callback(c *gin.Context) {
+	const op = "handlers.oauth.Callback"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.OAuthCallback
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	meta := model.SessionMeta{UserAgent: c.Request.UserAgent(), IP: c.GetString("real_ip")}
+	token, err := r.s.Login(c.Request.Context(), c.Param("provider"), req, meta)
+	if err != nil {
+		log.Error("cannot complete oauth login", sl.Err(err))
+		abortOAuth(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// Identities
+// @Summary     Linked providers
+// @Description External accounts linked to the current user
+// @Tags  	    oauth
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {object} []model.UserIdentity
+// @Router      /user/oauth/identities [get]
+// @Security OAuth2PasswordBearer
+func (r *oauthRoutes) Identities(c *gin.Context) {
+	const op = "handlers.oauth.Identities"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	identities, err := r.s.Identities(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot get identities", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, identities)
+}
+
+// AuthorizeLink
+// @Summary     Start linking a provider
+// @Description Get the provider authorization URL to link it to the current account. Complete with /user/oauth/{provider}/link/callback.
+// @Tags  	    oauth
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param       provider path string true "Provider"
+// @Success     200 {object} model.OAuthAuthorization
+// @Router      /user/oauth/{provider}/link [post]
+// @Security OAuth2PasswordBearer
+func (r *oauthRoutes) AuthorizeLink(c *gin.Context) {
+	const op = "handlers.oauth.AuthorizeLink"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	userID := c.GetInt64("user_id")
+	url, err := r.s.Authorize(c.Param("provider"), &userID)
+	if err != nil {
+		log.Error("cannot start oauth link", sl.Err(err))
+		abortOAuth(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.OAuthAuthorization{AuthorizationURL: url})
+}
+
+// LinkCallback
+// @Summary     Complete linking a provider
+// @Description Link the provider account from the redirect to the current user
+// @Tags  	    oauth
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param       provider path string true "Provider"
+// @Param       request body model.OAuthCallback true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/oauth/{provider}/link/callback [post]
+// @Security OAuth2PasswordBearer
+func (r *oauthRoutes) LinkCallback(c *gin.Context) {
+	const op = "handlers.oauth.LinkCallback"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.OAuthCallback
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.Link(c.Request.Context(), c.GetInt64("user_id"), c.Param("provider"), req); err != nil {
+		log.Error("cannot link provider", sl.Err(err))
+		abortOAuth(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("provider linked"))
+}
+
+// Unlink
+// @Summary     Unlink a provider
+// @Description Unlink the provider. The last login method cannot be removed.
+// @Tags  	    oauth
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param       provider path string true "Provider"
+// @Success     200 {object} response.Response
+// @Router      /user/oauth/{provider} [delete]
+// @Security OAuth2PasswordBearer
+func (r *oauthRoutes) Unlink(c *gin.Context) {
+	const op = "handlers.oauth.Unlink"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.Unlink(c.GetInt64("user_id"), c.Param("provider")); err != nil {
+		log.Error("cannot unlink provider", sl.Err(err))
+		abortOAuth(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("provider unlinked"))
+}
@@ -1,6 +1,7 @@
 package order
 
 import (
+	"errors"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
 	"github.com/RCSE2025/backend-go/internal/model"
@@ -18,20 +19,37 @@ type orderRoutes struct {
 	cartService *service.CartService
 }
 
-func NewOrderRoutes(h *gin.RouterGroup, s *service.OrderService, jwtService service.JWTService, cartService *service.CartService) {
+func NewOrderRoutes(h *gin.RouterGroup, s *service.OrderService, jwtService service.JWTService, cartService *service.CartService, userService *service.UserService) {
 	g := h.Group("/order")
 
 	validateJWTmw := auth.ValidateJWT(jwtService)
+	emailVerified := auth.RequireVerifiedEmail(userService)
 	ordR := orderRoutes{ordService: s, cartService: cartService}
 
-	g.POST("/create_order_manual", validateJWTmw, ordR.CreateOrder)
-	g.POST("/create_order_yookassa", validateJWTmw, ordR.CreateOrderYookassa)
+	g.POST("/create_order_manual", validateJWTmw, emailVerified, ordR.CreateOrder)
+	g.POST("/create_order_yookassa", validateJWTmw, emailVerified, ordR.CreateOrderYookassa)
 	g.GET("", validateJWTmw, ordR.GetListOrders)
 	g.PUT("", validateJWTmw, ordR.SetOrderStatus)
 }
 
+// CreateOrderRequest - адрес доставки: сохраненный address_id или строка address
 type CreateOrderRequest struct {
-	Address string `json:"address"`
+	AddressID *int64 `json:"address_id,omitempty"`
+	Address   string `json:"address"`
+}
+
+// abortCreateOrder отвечает клиенту по ошибке оформления заказа
+func abortCreateOrder(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrAddressRequired):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrAddressNotRecognized):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("Can't create order"))
+	}
 }
 
 // CreateOrder
@@ -41,6 +59,8 @@ type CreateOrderRequest struct {
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     422 {object} response.Response
 // @Param request body CreateOrderRequest true "request"
 // @Success     201 {object} response.Response`
 // @Router      /order/create_order_manual [post]
@@ -60,10 +80,10 @@ func (ordR *orderRoutes) CreateOrder(c *gin.Context) {
 	}
 
 	userID := c.GetInt64("user_id")
-	order, err := ordR.ordService.CreateOrder(userID, req.Address)
+	order, err := ordR.ordService.CreateOrder(c.Request.Context(), userID, req.AddressID, req.Address)
 	if err != nil {
 		log.Error("can't create order", slog.String("error", err.Error()))
-		c.JSON(http.StatusBadRequest, response.Error("Can't create order"))
+		abortCreateOrder(c, err)
 		return
 	}
 
@@ -178,6 +198,8 @@ type CreateOrderYookassaRequest struct {
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     422 {object} response.Response
 // @Param request body CreateOrderRequest true "request"
 // @Success     201 {object} CreateOrderYookassaRequest`
 // @Router      /order/create_order_yookassa [post]
@@ -197,10 +219,10 @@ func (ordR *orderRoutes) CreateOrderYookassa(c *gin.Context) {
 	}
 
 	userID := c.GetInt64("user_id")
-	order, err := ordR.ordService.CreateOrder(userID, req.Address)
+	order, err := ordR.ordService.CreateOrder(c.Request.Context(), userID, req.AddressID, req.Address)
 	if err != nil {
 		log.Error("can't create order", slog.String("error", err.Error()))
-		c.JSON(http.StatusBadRequest, response.Error("Can't create order"))
+		abortCreateOrder(c, err)
 		return
 	}
 
@@ -232,7 +254,7 @@ func (ordR *orderRoutes) CreateOrderYookassa(c *gin.Context) {
 		return
 	}
 
-	url, err := ordR.ordService.CreateOrderPayment(order.ID, totalPrice)
+	url, err := ordR.ordService.CreateOrderPayment(userID, order.ID, totalPrice)
 	if err != nil {
 		log.Error("can't create order payment", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, response.Error("Can't create order payment"))
@@ -6,7 +6,6 @@ import (
 	"github.com/RCSE2025/backend-go/pkg/api/response"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strconv"
 )
 
 type paymentRoutes struct {
@@ -49,23 +48,35 @@ func (pr *paymentRoutes) notification(c *gin.Context) {
 
 	fmt.Println(t)
 
-	if object, ok := t["object"].(map[string]interface{}); ok {
-		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
-			if orderID, ok := metadata["order_id"].(string); ok {
-				fmt.Println("Order ID:", orderID)
-				i, err := strconv.ParseInt(orderID, 10, 64)
-				if err != nil {
-					c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
-					return
-				}
-				err = pr.orderService.ConfirmOrderPayment(i)
-				if err != nil {
-					c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
-					return
-				}
-				c.Status(http.StatusOK)
-				return
-			}
+	if t["event"] == "refund.succeeded" {
+		object, _ := t["object"].(map[string]interface{})
+		refundID, _ := object["id"].(string)
+		if refundID == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("refund id is required"))
+			return
 		}
+		if err := pr.orderService.RecordRefund(refundID); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if t["event"] == "payment.succeeded" {
+		object, _ := t["object"].(map[string]interface{})
+		paymentID, _ := object["id"].(string)
+		if paymentID == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("payment id is required"))
+			return
+		}
+		if err := pr.orderService.ConfirmYookassaPayment(paymentID); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+		c.Status(http.StatusOK)
+		return
 	}
+
+	c.Status(http.StatusOK)
 }
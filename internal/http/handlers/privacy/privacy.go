@@ -0,0 +1,229 @@
+package privacy
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type privacyRoutes struct {
+	s *service.PrivacyService
+}
+
+func NewPrivacyRoutes(h *gin.RouterGroup, s *service.PrivacyService, jwtService service.JWTService, permissionService *service.PermissionService, limits *ratelimit.Policies) {
+	g := h.Group("/user")
+
+	pr := privacyRoutes{s: s}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	canDelete := permission.RequirePermission(permissionService, model.PermUserDelete)
+	exportLimit := limits.Limit("data_export", "3/24h", ratelimit.ByUser)
+
+	g.GET("/self/export", validateJWTmw, pr.GetExport)
+	g.POST("/self/export", validateJWTmw, exportLimit, pr.RequestExport)
+	g.GET("/self/export/download", validateJWTmw, pr.DownloadExport)
+	g.DELETE("/self", validateJWTmw, pr.DeleteSelf)
+	g.DELETE("/self/deletion", validateJWTmw, pr.CancelDeletion)
+	g.DELETE("/:id", validateJWTmw, canDelete, pr.DeleteUserByID)
+}
+
+// abortPrivacy отвечает клиенту по ошибке выгрузки или удаления данных
+func abortPrivacy(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrExportNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrExportNotReady), errors.Is(err, service.ErrDeletionNotScheduled),
+		errors.Is(err, service.ErrSoleBusinessOwner):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
+
+// RequestExport
+// @Summary     Request personal data export
+// @Description Queue a ZIP archive with profile, orders, reviews, sessions and addresses. Poll GET /user/self/export for the status; an email is sent when the archive is ready.
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Success     202 {object} model.DataExport
+// @Router      /user/self/export [post]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) RequestExport(c *gin.Context) {
+	const op = "handlers.privacy.RequestExport"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	export, err := r.s.RequestExport(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot request data export", sl.Err(err))
+		abortPrivacy(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// GetExport
+// @Summary     Personal data export status
+// @Description Status of the latest personal data export
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Success     200 {object} model.DataExport
+// @Router      /user/self/export [get]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) GetExport(c *gin.Context) {
+	const op = "handlers.privacy.GetExport"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	export, err := r.s.LatestExport(c.GetInt64("user_id"))
+	if err != nil {
+		if !errors.Is(err, service.ErrExportNotFound) {
+			log.Error("cannot get data export", sl.Err(err))
+		}
+		abortPrivacy(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadExport
+// @Summary     Download personal data export
+// @Description Download the latest ready personal data archive
+// @Tags  	    user
+// @Produce     application/zip
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Success     200 {file} file
+// @Router      /user/self/export/download [get]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) DownloadExport(c *gin.Context) {
+	const op = "handlers.privacy.DownloadExport"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	rc, info, err := r.s.OpenExport(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot open data export", sl.Err(err))
+		abortPrivacy(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, info.Size, "application/zip", rc, map[string]string{
+		"Content-Disposition": `attachment; filename="personal-data.zip"`,
+	})
+}
+
+// DeleteSelf
+// @Summary     Delete self
+// @Description Schedule account deletion. After the grace period personal data is anonymized; orders are kept for accounting. The deletion can be canceled until then. The last owner of a business must transfer ownership first.
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Success     202 {object} model.AccountDeletion
+// @Router      /user/self [delete]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) DeleteSelf(c *gin.Context) {
+	const op = "handlers.privacy.DeleteSelf"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	deletion, err := r.s.ScheduleDeletion(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot schedule account deletion", sl.Err(err))
+		abortPrivacy(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deletion)
+}
+
+// CancelDeletion
+// @Summary     Cancel account deletion
+// @Description Cancel the scheduled account deletion
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/self/deletion [delete]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) CancelDeletion(c *gin.Context) {
+	const op = "handlers.privacy.CancelDeletion"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.CancelDeletion(c.GetInt64("user_id")); err != nil {
+		log.Error("cannot cancel account deletion", sl.Err(err))
+		abortPrivacy(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("account deletion canceled"))
+}
+
+// DeleteUserByID
+// @Summary     Delete user by id
+// @Description Anonymize the user immediately, without a grace period. Orders are kept for accounting. The last owner of a business must transfer ownership first.
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param id path string true "id"
+// @Success     200 {object} response.Response
+// @Router      /user/{id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *privacyRoutes) DeleteUserByID(c *gin.Context) {
+	const op = "handlers.privacy.DeleteUserByID"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid id"))
+		return
+	}
+
+	if err := r.s.Erase(c.Request.Context(), id); err != nil {
+		log.Error("cannot delete user", sl.Err(err))
+		abortPrivacy(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK())
+}
@@ -1,12 +1,17 @@
 package product
 
 import (
+	"errors"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/policy"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/service"
 	"github.com/RCSE2025/backend-go/internal/utils"
 	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"log/slog"
@@ -17,31 +22,46 @@ import (
 	"time"
 )
 
+const fileURLExpiration = time.Hour
+
 type productRoutes struct {
 	productService *service.ProductService
+	analytics      *service.AnalyticsService
+	policyService  *service.PolicyService
 	moderateAPI    *utils.ModeratorAPI
 }
 
-func NewProductRoutes(h *gin.RouterGroup, jwtService service.JWTService, productService *service.ProductService) {
+func NewProductRoutes(h *gin.RouterGroup, jwtService service.JWTService, productService *service.ProductService, analyticsService *service.AnalyticsService, policyService *service.PolicyService, permissionService service.PermissionChecker, limits *ratelimit.Policies) {
 	g := h.Group("/product")
 
 	pr := productRoutes{
 		productService: productService,
+		analytics:      analyticsService,
+		policyService:  policyService,
 		moderateAPI:    utils.NewModeratorAPI(),
 	}
 
 	validateJWTmw := auth.ValidateJWT(jwtService)
-	g.POST("/images/upload", pr.uploadImages)
+	canWrite := policy.Product(policyService, service.ActionProducts, "id")
+	canModerate := permission.RequirePermission(permissionService, model.PermProductModerate)
+
+	g.POST("/images/upload", validateJWTmw, pr.uploadImages)
 	g.GET("/categories", pr.getCategories)
 	g.GET("/:id", pr.getProduct)
 	g.GET("/:id/reviews", pr.getProductReviews)
-	g.POST("/:id/reviews", pr.addProductReview)
+	g.POST("/:id/reviews", validateJWTmw, limits.Limit("review", "10/1h", ratelimit.ByUser), pr.addProductReview)
 	g.GET("/filter", pr.filterProducts)
-	g.POST("", pr.createProduct)
-	g.PUT("/:id", pr.updateProduct)
-	g.DELETE("/:id", pr.deleteProduct)
-	g.POST("/:id/images/upload", pr.UploadReviewFile)
+	g.POST("", validateJWTmw, pr.createProduct)
+	g.PUT("/:id", validateJWTmw, canWrite, pr.updateProduct)
+	g.DELETE("/:id", validateJWTmw, canWrite, pr.deleteProduct)
+	g.PUT("/:id/status", validateJWTmw, canModerate, pr.setProductStatus)
+	g.POST("/:id/images/upload", validateJWTmw, pr.UploadReviewFile)
 	g.GET("", validateJWTmw, pr.GetUserProduct)
+
+	g.GET("/:id/images", pr.getProductImages)
+	g.PUT("/:id/images", validateJWTmw, canWrite, pr.reorderProductImages)
+	g.DELETE("/:id/images/:image_id", validateJWTmw, canWrite, pr.deleteProductImage)
+	g.PUT("/:id/images/:image_id/primary", validateJWTmw, canWrite, pr.setPrimaryProductImage)
 }
 
 // uploadImages
@@ -56,7 +76,9 @@ func NewProductRoutes(h *gin.RouterGroup, jwtService service.JWTService, product
 // @Success     200 {object} response.Response{data=[]model.ProductImage} "Successful upload"
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     500 {object} response.Response "Internal server error"
+// @Failure     403 {object} response.Response "Forbidden"
 // @Router      /product/images/upload [post]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) uploadImages(c *gin.Context) {
 	const op = "handlers.product.uploadImages"
 	log := logger.FromContext(c).With(
@@ -75,6 +97,12 @@ func (pr *productRoutes) uploadImages(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, response.Error("Invalid product ID"))
 			return
 		}
+
+		if err := pr.authorizeProduct(c, productID); err != nil {
+			log.Warn("product access denied", sl.Err(err))
+			policy.Abort(c, err)
+			return
+		}
 	}
 
 	// Получаем флаг основного изображения из query параметра (если есть)
@@ -102,8 +130,8 @@ func (pr *productRoutes) uploadImages(c *gin.Context) {
 		return
 	}
 
-	// Получаем S3 клиент из сервиса
-	s3Worker := pr.productService.GetS3Worker()
+	// Получаем хранилище из сервиса
+	blob := pr.productService.ProductStorage()
 
 	// Загружаем файлы в S3
 	var uploadedImages []model.ProductImage
@@ -114,10 +142,10 @@ func (pr *productRoutes) uploadImages(c *gin.Context) {
 		//	continue
 		//}
 
-		// Загружаем файл в S3
-		filename, err := s3Worker.UploadFileFromMultipart(file)
+		// Загружаем файл в хранилище
+		filename, err := pr.productService.UploadProductFile(c.Request.Context(), file)
 		if err != nil {
-			log.Error("failed to upload file to S3",
+			log.Error("failed to upload file to storage",
 				slog.String("filename", file.Filename),
 				slog.String("error", err.Error()),
 			)
@@ -125,7 +153,7 @@ func (pr *productRoutes) uploadImages(c *gin.Context) {
 		}
 
 		// Получаем URL файла
-		fileURL, err := s3Worker.GetFileURL(filename)
+		fileURL, err := blob.SignedURL(c.Request.Context(), filename, fileURLExpiration)
 		if err != nil {
 			log.Error("failed to get file URL",
 				slog.String("filename", filename),
@@ -243,6 +271,8 @@ func (pr *productRoutes) getProduct(c *gin.Context) {
 	}
 
 	log.Info("product retrieved", slog.Int64("id", id))
+	// Маршрут публичный, поэтому просмотры учитываются как анонимные
+	pr.analytics.Track(model.ProductEventView, product, 0)
 	c.JSON(http.StatusOK, product)
 }
 
@@ -286,17 +316,18 @@ func (pr *productRoutes) getProductReviews(c *gin.Context) {
 
 // addProductReview
 // @Summary     Add product review
-// @Description Add a new review for a product
+// @Description Add a review of the current user for a product. Rating is from 1 to 5.
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Product ID"
-// @Param       review body model.ProductReview true "Review data"
-// @Success     201 {object} response.Response{data=model.ProductReview} "Review created"
+// @Param       review body model.ProductReviewRequest true "Review data"
+// @Success     201 {object} model.ProductReview "Review created"
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     404 {object} response.Response "Product not found"
 // @Failure     500 {object} response.Response "Internal server error"
 // @Router      /product/{id}/reviews [post]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) addProductReview(c *gin.Context) {
 	const op = "handlers.product.addProductReview"
 	log := logger.FromContext(c).With(
@@ -312,14 +343,14 @@ func (pr *productRoutes) addProductReview(c *gin.Context) {
 		return
 	}
 
-	var review model.ProductReview
-	if err := c.ShouldBindJSON(&review); err != nil {
+	var req model.ProductReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error("failed to bind review data", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, response.Error("Invalid review data"))
 		return
 	}
 
-	isGood, err := pr.moderateAPI.IsModerateContent(review.Comment, nil, true)
+	isGood, err := pr.moderateAPI.IsModerateContent(req.Comment, nil, true)
 	//fmt.Println(isGood)
 	if isGood == false {
 		log.Error("", slog.String("unwanted content", ""))
@@ -327,22 +358,17 @@ func (pr *productRoutes) addProductReview(c *gin.Context) {
 		return
 	}
 
-	// Устанавливаем ID продукта из URL
-	review.ProductID = id
-
-	// Устанавливаем текущую дату
-	review.Date = time.Now()
-
-	// Добавление отзыва через сервис
-	createdReview, err := pr.productService.AddProductReview(c.Request.Context(), review)
+	review, err := pr.productService.AddProductReview(c.Request.Context(), c.GetInt64("user_id"), id, req)
 	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.Error("Product not found"))
+			return
+		}
 		log.Error("failed to add product review", slog.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, response.Error("Failed to add product review"))
 		return
 	}
 
-	review = *createdReview
-
 	log.Info("product review added", slog.Int64("product_id", id), slog.Int64("review_id", review.ID))
 	c.JSON(http.StatusCreated, review)
 }
@@ -386,7 +412,7 @@ func (pr *productRoutes) filterProducts(c *gin.Context) {
 
 // createProduct
 // @Summary     Create a new product
-// @Description Create a new product
+// @Description Create a new product. Only verified businesses may publish products
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
@@ -394,7 +420,9 @@ func (pr *productRoutes) filterProducts(c *gin.Context) {
 // @Success     201 {object} response.Response{data=model.Product} "Product created"
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     500 {object} response.Response "Internal server error"
+// @Failure     403 {object} response.Response "Forbidden"
 // @Router      /product [post]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) createProduct(c *gin.Context) {
 	const op = "handlers.product.createProduct"
 	log := logger.FromContext(c).With(
@@ -409,6 +437,17 @@ func (pr *productRoutes) createProduct(c *gin.Context) {
 		return
 	}
 
+	if err := pr.authorizeBusiness(c, productRequest.BusinessID); err != nil {
+		log.Warn("business access denied", sl.Err(err))
+		policy.Abort(c, err)
+		return
+	}
+	if err := pr.policyService.RequireVerifiedBusiness(productRequest.BusinessID); err != nil {
+		log.Warn("business cannot publish products", sl.Err(err))
+		policy.Abort(c, err)
+		return
+	}
+
 	// Преобразуем запрос в модель продукта
 	product := productRequest.ToProduct()
 	isGood, err := pr.moderateAPI.IsModerateContent(productRequest.Title+" "+productRequest.Description, nil, true)
@@ -432,7 +471,7 @@ func (pr *productRoutes) createProduct(c *gin.Context) {
 
 // updateProduct
 // @Summary     Update a product
-// @Description Update an existing product
+// @Description Update an existing product. The product is republished, so its business must be verified
 // @Tags  	    product
 // @Accept      json
 // @Produce     json
@@ -442,7 +481,9 @@ func (pr *productRoutes) createProduct(c *gin.Context) {
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     404 {object} response.Response "Product not found"
 // @Failure     500 {object} response.Response "Internal server error"
+// @Failure     403 {object} response.Response "Forbidden"
 // @Router      /product/{id} [put]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) updateProduct(c *gin.Context) {
 	const op = "handlers.product.updateProduct"
 	log := logger.FromContext(c).With(
@@ -473,9 +514,29 @@ func (pr *productRoutes) updateProduct(c *gin.Context) {
 		return
 	}
 
+	// Перенос товара в другой бизнес требует доступа и к нему
+	if updateRequest.BusinessID != 0 && updateRequest.BusinessID != existingProduct.BusinessID {
+		if err := pr.authorizeBusiness(c, updateRequest.BusinessID); err != nil {
+			log.Warn("business access denied", sl.Err(err))
+			policy.Abort(c, err)
+			return
+		}
+	}
+
+	// После изменения товар публикуется снова, а это доступно только подтвержденному бизнесу
+	businessID := existingProduct.BusinessID
+	if updateRequest.BusinessID != 0 {
+		businessID = updateRequest.BusinessID
+	}
+	if err := pr.policyService.RequireVerifiedBusiness(businessID); err != nil {
+		log.Warn("business cannot publish products", sl.Err(err))
+		policy.Abort(c, err)
+		return
+	}
+
 	isGood, err := pr.moderateAPI.IsModerateContent(updateRequest.Title+" "+updateRequest.Description, nil, true)
 	if isGood == false || err != nil {
-		log.Warn("can't moderate content or content it's nsfw", err)
+		log.Warn("can't moderate content or content it's nsfw", sl.Err(err))
 		c.JSON(http.StatusBadRequest, response.Error("can't moderate content or content it's nsfw"))
 		return
 	}
@@ -484,7 +545,7 @@ func (pr *productRoutes) updateProduct(c *gin.Context) {
 	updateRequest.ApplyToProduct(existingProduct)
 	err = pr.productService.SetProductStatus(existingProduct.ID, "approve")
 	if err != nil {
-		log.Warn("can't update product", err)
+		log.Warn("can't update product", sl.Err(err))
 		c.JSON(http.StatusBadRequest, response.Error("can't update product "+err.Error()))
 		return
 	}
@@ -511,7 +572,9 @@ func (pr *productRoutes) updateProduct(c *gin.Context) {
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     404 {object} response.Response "Product not found"
 // @Failure     500 {object} response.Response "Internal server error"
+// @Failure     403 {object} response.Response "Forbidden"
 // @Router      /product/{id} [delete]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) deleteProduct(c *gin.Context) {
 	const op = "handlers.product.deleteProduct"
 	log := logger.FromContext(c).With(
@@ -538,6 +601,67 @@ func (pr *productRoutes) deleteProduct(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// setProductStatus
+// @Summary     Moderate a product
+// @Description Set product moderation status. Approval requires a verified business
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Product ID"
+// @Param       request body model.ProductStatusUpdate true "New status"
+// @Success     200 {object} response.Response "Status updated"
+// @Failure     400 {object} response.Response "Bad request"
+// @Failure     403 {object} response.Response "Forbidden"
+// @Failure     404 {object} response.Response "Product not found"
+// @Failure     500 {object} response.Response "Internal server error"
+// @Router      /product/{id}/status [put]
+// @Security OAuth2PasswordBearer
+func (pr *productRoutes) setProductStatus(c *gin.Context) {
+	const op = "handlers.product.setProductStatus"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid product id", sl.Err(err))
+		c.JSON(http.StatusBadRequest, response.Error("Invalid product ID"))
+		return
+	}
+
+	var req model.ProductStatusUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("failed to bind status", sl.Err(err))
+		c.JSON(http.StatusBadRequest, response.Error("Invalid status: "+err.Error()))
+		return
+	}
+
+	product, err := pr.productService.GetProductByID(c.Request.Context(), id)
+	if err != nil {
+		log.Error("failed to get product", sl.Err(err))
+		c.JSON(http.StatusNotFound, response.Error("Product not found"))
+		return
+	}
+
+	if req.Status == model.StatusApprove {
+		if err := pr.policyService.RequireVerifiedBusiness(product.BusinessID); err != nil {
+			log.Warn("business cannot publish products", sl.Err(err))
+			policy.Abort(c, err)
+			return
+		}
+	}
+
+	if err := pr.productService.SetProductStatus(id, string(req.Status)); err != nil {
+		log.Error("failed to set product status", sl.Err(err))
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to set product status"))
+		return
+	}
+
+	log.Info("product moderated", slog.Int64("id", id), slog.String("status", string(req.Status)), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, response.Success("status updated"))
+}
+
 // UploadReviewFile
 // @Summary     Upload multiple images for review
 // @Description Upload multiple images for review
@@ -551,6 +675,7 @@ func (pr *productRoutes) deleteProduct(c *gin.Context) {
 // @Failure     400 {object} response.Response "Bad request"
 // @Failure     500 {object} response.Response "Internal server error"
 // @Router      /product/{id}/images/upload [post]
+// @Security OAuth2PasswordBearer
 func (pr *productRoutes) UploadReviewFile(c *gin.Context) {
 	const op = "handlers.product.UploadReviewFile"
 	log := logger.FromContext(c).With(
@@ -597,8 +722,8 @@ func (pr *productRoutes) UploadReviewFile(c *gin.Context) {
 		return
 	}
 
-	// Получаем S3 клиент из сервиса
-	s3Worker := pr.productService.GetS3WorkerReview()
+	// Получаем хранилище из сервиса
+	blob := pr.productService.ReviewStorage()
 
 	// Загружаем файлы в S3
 	var uploadedImages []model.ReviewImages
@@ -609,10 +734,10 @@ func (pr *productRoutes) UploadReviewFile(c *gin.Context) {
 			continue
 		}
 
-		// Загружаем файл в S3
-		filename, err := s3Worker.UploadFileFromMultipart(file)
+		// Загружаем файл в хранилище
+		filename, err := pr.productService.UploadReviewFile(c.Request.Context(), file)
 		if err != nil {
-			log.Error("failed to upload file to S3",
+			log.Error("failed to upload file to storage",
 				slog.String("filename", file.Filename),
 				slog.String("error", err.Error()),
 			)
@@ -620,7 +745,7 @@ func (pr *productRoutes) UploadReviewFile(c *gin.Context) {
 		}
 
 		// Получаем URL файла
-		fileURL, err := s3Worker.GetFileURL(filename)
+		fileURL, err := blob.SignedURL(c.Request.Context(), filename, fileURLExpiration)
 		if err != nil {
 			log.Error("failed to get file URL",
 				slog.String("filename", filename),
@@ -696,3 +821,193 @@ func (pr *productRoutes) GetUserProduct(c *gin.Context) {
 
 	c.JSON(http.StatusOK, products)
 }
+
+// getProductImages
+// @Summary     Get product images
+// @Description Get product images in display order
+// @Tags  	    product
+// @Produce     json
+// @Param       id path int true "Product ID"
+// @Success     200 {object} []model.ProductImage "Successful operation"
+// @Failure     400 {object} response.Response "Bad request"
+// @Failure     500 {object} response.Response "Internal server error"
+// @Router      /product/{id}/images [get]
+func (pr *productRoutes) getProductImages(c *gin.Context) {
+	const op = "handlers.product.getProductImages"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid product id", sl.Err(err))
+		c.JSON(http.StatusBadRequest, response.Error("Invalid product ID"))
+		return
+	}
+
+	images, err := pr.productService.GetProductImages(c.Request.Context(), id)
+	if err != nil {
+		log.Error("failed to get product images", sl.Err(err))
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to get product images"))
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// reorderProductImages
+// @Summary     Reorder product images
+// @Description Set display order of product images. The list must contain every image of the product.
+// @Tags  	    product
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Product ID"
+// @Param       request body model.ProductImagesReorderRequest true "Image IDs in new order"
+// @Success     200 {object} []model.ProductImage "Successful operation"
+// @Failure     400 {object} response.Response "Bad request"
+// @Failure     403 {object} response.Response "Forbidden"
+// @Failure     404 {object} response.Response "Product not found"
+// @Failure     500 {object} response.Response "Internal server error"
+// @Router      /product/{id}/images [put]
+// @Security OAuth2PasswordBearer
+func (pr *productRoutes) reorderProductImages(c *gin.Context) {
+	const op = "handlers.product.reorderProductImages"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid product id", sl.Err(err))
+		c.JSON(http.StatusBadRequest, response.Error("Invalid product ID"))
+		return
+	}
+
+	var req model.ProductImagesReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("failed to bind reorder data", sl.Err(err))
+		c.JSON(http.StatusBadRequest, response.Error("Invalid reorder data: "+err.Error()))
+		return
+	}
+
+	images, err := pr.productService.ReorderProductImages(c.Request.Context(), id, req.ImageIDs)
+	if err != nil {
+		log.Error("failed to reorder product images", sl.Err(err))
+		if errors.Is(err, service.ErrInvalidImageOrder) {
+			c.JSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to reorder product images"))
+		return
+	}
+
+	log.Info("product images reordered", slog.Int64("product_id", id))
+	c.JSON(http.StatusOK, images)
+}
+
+// deleteProductImage
+// @Summary     Delete product image
+// @Description Delete product image and its file from storage
+// @Tags  	    product
+// @Produce     json
+// @Param       id path int true "Product ID"
+// @Param       image_id path int true "Image ID"
+// @Success     200 {object} response.Response "Image deleted"
+// @Failure     400 {object} response.Response "Bad request"
+// @Failure     403 {object} response.Response "Forbidden"
+// @Failure     404 {object} response.Response "Image not found"
+// @Failure     500 {object} response.Response "Internal server error"
+// @Router      /product/{id}/images/{image_id} [delete]
+// @Security OAuth2PasswordBearer
+func (pr *productRoutes) deleteProductImage(c *gin.Context) {
+	const op = "handlers.product.deleteProductImage"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, imageID, ok := parseImagePath(c)
+	if !ok {
+		return
+	}
+
+	if err := pr.productService.DeleteProductImage(c.Request.Context(), id, imageID); err != nil {
+		log.Error("failed to delete product image", sl.Err(err))
+		if errors.Is(err, service.ErrProductImageNotFound) {
+			c.JSON(http.StatusNotFound, response.Error(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to delete product image"))
+		return
+	}
+
+	log.Info("product image deleted", slog.Int64("product_id", id), slog.Int64("image_id", imageID))
+	c.JSON(http.StatusOK, response.Success("image deleted"))
+}
+
+// setPrimaryProductImage
+// @Summary     Set primary product image
+// @Description Make image the primary image of the product
+// @Tags  	    product
+// @Produce     json
+// @Param       id path int true "Product ID"
+// @Param       image_id path int true "Image ID"
+// @Success     200 {object} response.Response "Primary image set"
+// @Failure     400 {object} response.Response "Bad request"
+// @Failure     403 {object} response.Response "Forbidden"
+// @Failure     404 {object} response.Response "Image not found"
+// @Failure     500 {object} response.Response "Internal server error"
+// @Router      /product/{id}/images/{image_id}/primary [put]
+// @Security OAuth2PasswordBearer
+func (pr *productRoutes) setPrimaryProductImage(c *gin.Context) {
+	const op = "handlers.product.setPrimaryProductImage"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	id, imageID, ok := parseImagePath(c)
+	if !ok {
+		return
+	}
+
+	if err := pr.productService.SetPrimaryProductImage(c.Request.Context(), id, imageID); err != nil {
+		log.Error("failed to set primary product image", sl.Err(err))
+		if errors.Is(err, service.ErrProductImageNotFound) {
+			c.JSON(http.StatusNotFound, response.Error(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to set primary product image"))
+		return
+	}
+
+	log.Info("primary product image set", slog.Int64("product_id", id), slog.Int64("image_id", imageID))
+	c.JSON(http.StatusOK, response.Success("primary image set"))
+}
+
+// parseImagePath разбирает параметры :id и :image_id, при ошибке отвечает 400
+func parseImagePath(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid product ID"))
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.ParseInt(c.Param("image_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid image ID"))
+		return 0, 0, false
+	}
+
+	return id, imageID, true
+}
+
+func (pr *productRoutes) authorizeProduct(c *gin.Context, productID int64) error {
+	return pr.policyService.AuthorizeProduct(c.Request.Context(), c.GetInt64("user_id"), productID, service.ActionProducts)
+}
+
+func (pr *productRoutes) authorizeBusiness(c *gin.Context, businessID int64) error {
+	return pr.policyService.AuthorizeBusiness(c.Request.Context(), c.GetInt64("user_id"), businessID, service.ActionProducts)
+}
@@ -0,0 +1,177 @@
+package role
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type roleRoutes struct {
+	s *service.PermissionService
+}
+
+func NewRoleRoutes(h *gin.RouterGroup, s *service.PermissionService, jwtService service.JWTService) {
+	g := h.Group("/role")
+
+	rr := roleRoutes{s: s}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	canManage := permission.RequirePermission(s, model.PermRoleManage)
+
+	g.GET("", validateJWTmw, canManage, rr.GetRoles)
+	g.GET("/permissions", validateJWTmw, canManage, rr.GetPermissions)
+	g.PUT("/:role", validateJWTmw, canManage, rr.SetRolePermissions)
+	g.PUT("/user/:user_id", validateJWTmw, canManage, rr.SetUserRole)
+}
+
+// GetRoles
+// @Summary     Get roles
+// @Description Get all roles with their permissions
+// @Tags  	    role
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Success     200 {array} model.RolePermissions
+// @Router      /role [get]
+// @Security OAuth2PasswordBearer
+func (r *roleRoutes) GetRoles(c *gin.Context) {
+	const op = "handlers.role.GetRoles"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	roles, err := r.s.GetRoles(c.Request.Context())
+	if err != nil {
+		log.Error("cannot get roles", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetPermissions
+// @Summary     Get permissions
+// @Description Get all permissions known to the application
+// @Tags  	    role
+// @Accept      json
+// @Produce     json
+// @Failure     403 {object} response.Response
+// @Success     200 {array} string
+// @Router      /role/permissions [get]
+// @Security OAuth2PasswordBearer
+func (r *roleRoutes) GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, model.AllPermissions)
+}
+
+// SetRolePermissions
+// @Summary     Set role permissions
+// @Description Replace all permissions of the role. Takes effect for every user with this role without reissuing tokens.
+// @Tags  	    role
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param role path string true "role"
+// @Param request body model.RolePermissionsUpdate true "request"
+// @Success     200 {object} model.RolePermissions
+// @Router      /role/{role} [put]
+// @Security OAuth2PasswordBearer
+func (r *roleRoutes) SetRolePermissions(c *gin.Context) {
+	const op = "handlers.role.SetRolePermissions"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.RolePermissionsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	role := model.UserRoleType(c.Param("role"))
+	result, err := r.s.SetRolePermissions(c.Request.Context(), role, req.Permissions)
+	if err != nil {
+		log.Error("cannot set role permissions", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrUnknownRole):
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUnknownPermission), errors.Is(err, service.ErrAdminLockout):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	log.Info("role permissions updated", slog.String("role", string(role)), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, result)
+}
+
+// SetUserRole
+// @Summary     Set user role
+// @Description Change role of the user. Takes effect on the next request.
+// @Tags  	    role
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param user_id path int true "user id"
+// @Param request body model.UserRoleUpdate true "request"
+// @Success     200 {object} response.Response
+// @Router      /role/user/{user_id} [put]
+// @Security OAuth2PasswordBearer
+func (r *roleRoutes) SetUserRole(c *gin.Context) {
+	const op = "handlers.role.SetUserRole"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse user_id"))
+		return
+	}
+
+	var req model.UserRoleUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.SetUserRole(c.Request.Context(), userID, req.Role); err != nil {
+		log.Error("cannot set user role", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrUnknownRole):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUserNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	log.Info("user role updated", slog.Int64("user_id", userID), slog.String("role", string(req.Role)), slog.Int64("by", c.GetInt64("user_id")))
+	c.JSON(http.StatusOK, response.Success("role updated"))
+}
@@ -2,15 +2,23 @@ package handlers
 
 import (
 	"github.com/RCSE2025/backend-go/docs"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/address"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/business"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/cart"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/finance"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/oauth"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/order"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/payment"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/privacy"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/product"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/role"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/upload"
 	"github.com/RCSE2025/backend-go/internal/http/handlers/user"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/wellknown"
 	"github.com/RCSE2025/backend-go/internal/http/middleware"
 	mwLogger "github.com/RCSE2025/backend-go/internal/http/middleware/logger"
 	mvp "github.com/RCSE2025/backend-go/internal/http/middleware/prometheus"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
 	"github.com/RCSE2025/backend-go/internal/service"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -30,18 +38,12 @@ import (
 // @tokenUrl /user/token
 // @scope.read Grants read access
 // @scope.write Grants write access
-func NewRouter(r *gin.Engine, log *slog.Logger, us *service.UserService, jwtService service.JWTService, productService *service.ProductService, cartService *service.CartService, businessService *service.BusinessService, orderService *service.OrderService, paymentService *service.YookassaPayment) {
+func NewRouter(r *gin.Engine, log *slog.Logger, us *service.UserService, jwtService service.JWTService, productService *service.ProductService, cartService *service.CartService, businessService *service.BusinessService, businessVerificationService *service.BusinessVerificationService, businessMemberService *service.BusinessMemberService, storefrontService *service.StorefrontService, analyticsService *service.AnalyticsService, ledgerService *service.LedgerService, payoutService *service.PayoutService, orderService *service.OrderService, paymentService *service.YookassaPayment, uploadService *service.UploadService, policyService *service.PolicyService, permissionService *service.PermissionService, oauthService *service.OAuthService, privacyService *service.PrivacyService, addressService *service.AddressService, limits *ratelimit.Policies) {
 
 	r.Use(requestid.New()) // Equivalent to middleware.RequestID
 
 	r.Use(mwLogger.New(log)) // Logging middleware
 
-	err := r.SetTrustedProxies(nil) //disabled Trusted Proxies
-	if err != nil {
-		log.Error(err.Error())
-		return
-	}
-
 	r.Use(middleware.CORSMiddleware())
 	r.Use(mvp.NewGinPrometheusMiddleware("user-api"))
 	r.Use(gin.Recovery())
@@ -58,13 +60,21 @@ func NewRouter(r *gin.Engine, log *slog.Logger, us *service.UserService, jwtServ
 	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	r.Use(middleware.RealIPMiddleware())
+	r.Use(limits.Limit("global", "600/1m", ratelimit.ByIP))
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	h := r.Group("")
 
-	user.NewUserRoutes(h, us, jwtService)
-	product.NewProductRoutes(h, jwtService, productService)
-	cart.NewCartRoutes(h, cartService, jwtService)
-	order.NewOrderRoutes(h, orderService, jwtService, cartService)
-	business.NewBusinessRoutes(h, businessService, jwtService)
+	user.NewUserRoutes(h, us, jwtService, permissionService, limits)
+	oauth.NewOAuthRoutes(h, oauthService, jwtService, limits)
+	privacy.NewPrivacyRoutes(h, privacyService, jwtService, permissionService, limits)
+	product.NewProductRoutes(h, jwtService, productService, analyticsService, policyService, permissionService, limits)
+	cart.NewCartRoutes(h, cartService, analyticsService, jwtService)
+	address.NewAddressRoutes(h, addressService, jwtService, limits)
+	order.NewOrderRoutes(h, orderService, jwtService, cartService, us)
+	business.NewBusinessRoutes(h, businessService, businessVerificationService, businessMemberService, storefrontService, analyticsService, ledgerService, payoutService, jwtService, policyService, permissionService, us, limits)
 	payment.NewProductRoutes(h, paymentService, orderService)
+	upload.NewUploadRoutes(h, uploadService, jwtService)
+	finance.NewFinanceRoutes(h, ledgerService, payoutService, jwtService, permissionService)
+	role.NewRoleRoutes(h, permissionService, jwtService)
+	wellknown.NewWellKnownRoutes(h, jwtService)
 }
@@ -0,0 +1,138 @@
+package upload
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type uploadRoutes struct {
+	s *service.UploadService
+}
+
+func NewUploadRoutes(h *gin.RouterGroup, s *service.UploadService, jwtService service.JWTService) {
+	g := h.Group("/uploads")
+
+	ur := uploadRoutes{s: s}
+
+	validateJWTmw := auth.ValidateJWT(jwtService)
+	g.POST("", validateJWTmw, ur.CreateUpload)
+	g.POST("/:id/confirm", validateJWTmw, ur.ConfirmUpload)
+}
+
+// CreateUpload
+// @Summary     Create upload
+// @Description Returns presigned PUT URL for direct upload to storage. The file must be confirmed before the URL expires.
+// @Tags  	    upload
+// @Accept      json
+// @Produce     json
+// @Failure     400 {object} response.Response
+// @Failure     413 {object} response.Response
+// @Failure     415 {object} response.Response
+// @Failure     500 {object} response.Response
+// @Param request body model.UploadCreate true "request"
+// @Success     201 {object} model.UploadTicket
+// @Router      /uploads [post]
+// @Security OAuth2PasswordBearer
+func (r *uploadRoutes) CreateUpload(c *gin.Context) {
+	const op = "handlers.upload.CreateUpload"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.UploadCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	ticket, err := r.s.CreateUpload(c.Request.Context(), c.GetInt64("user_id"), req)
+	if err != nil {
+		log.Error("cannot create upload", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrUploadTooLarge):
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadContentType):
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadTargetNotFound):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, storage.ErrNotSupported):
+			c.AbortWithStatusJSON(http.StatusNotImplemented, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// ConfirmUpload
+// @Summary     Confirm upload
+// @Description Verifies uploaded file, runs moderation, strips image metadata and attaches it to product, review or business storefront (business_logo and business_banner replace the previous image)
+// @Tags  	    upload
+// @Accept      json
+// @Produce     json
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     410 {object} response.Response
+// @Failure     413 {object} response.Response
+// @Failure     500 {object} response.Response
+// @Param id path string true "upload id"
+// @Param request body model.UploadConfirm true "request"
+// @Success     200 {object} model.UploadConfirmed
+// @Router      /uploads/{id}/confirm [post]
+// @Security OAuth2PasswordBearer
+func (r *uploadRoutes) ConfirmUpload(c *gin.Context) {
+	const op = "handlers.upload.ConfirmUpload"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.UploadConfirm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("cannot parse request", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	result, err := r.s.ConfirmUpload(c.Request.Context(), c.GetInt64("user_id"), c.Param("id"), req)
+	if err != nil {
+		log.Error("cannot confirm upload", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrUploadNotFound), errors.Is(err, service.ErrUploadTargetNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadForbidden), errors.Is(err, service.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadAlreadyConfirmed), errors.Is(err, service.ErrUploadInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadExpired):
+			c.AbortWithStatusJSON(http.StatusGone, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadTooLarge):
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.Error(err.Error()))
+		case errors.Is(err, service.ErrUploadNotReceived),
+			errors.Is(err, service.ErrUploadInvalidImage),
+			errors.Is(err, service.ErrUploadRejected):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,305 @@
+package user
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+// abortMFA отвечает клиенту по ошибке двухфакторной аутентификации
+func abortMFA(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFAToken), errors.Is(err, service.ErrInvalidMFACode):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(err.Error()))
+	case errors.Is(err, service.ErrMFALocked):
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(err.Error()))
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	case errors.Is(err, service.ErrMFANotEnabled), errors.Is(err, service.ErrMFANotEnrolled):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
+
+// MFAToken
+// @Summary     Complete login with 2FA
+// @Description Exchange mfa_token from /user/token and a TOTP or recovery code for access and refresh tokens
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Success     200 {object} model.Token
+// @Router      /user/token/mfa [post]
+// @Param       mfa_token formData string true "MFA token"
+// @Param       code formData string true "TOTP or recovery code"
+func (r *userRoutes) MFAToken(c *gin.Context) {
+	const op = "handlers.user.MFAToken"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFALogin
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	token, err := r.s.CompleteMFALogin(req.MFAToken, req.Code, sessionMeta(c))
+	if err != nil {
+		log.Error("cannot complete mfa login", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// MFAEnrollOnLogin
+// @Summary     Start required 2FA enrollment
+// @Description Start TOTP enrollment with mfa_token issued by /user/token when the role requires 2FA
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Success     200 {object} model.MFAEnrollment
+// @Router      /user/token/mfa/enroll [post]
+// @Param       mfa_token formData string true "MFA token"
+func (r *userRoutes) MFAEnrollOnLogin(c *gin.Context) {
+	const op = "handlers.user.MFAEnrollOnLogin"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFATokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	userID, err := r.s.MFAEnrollUserID(req.MFAToken)
+	if err != nil {
+		abortMFA(c, err)
+		return
+	}
+
+	enrollment, err := r.s.StartMFAEnrollment(userID)
+	if err != nil {
+		log.Error("cannot start mfa enrollment", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// MFAConfirmOnLogin
+// @Summary     Confirm required 2FA enrollment
+// @Description Enable 2FA with the first TOTP code and finish login. Returns recovery codes and tokens.
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Success     200 {object} model.MFAConfirmed
+// @Router      /user/token/mfa/confirm [post]
+// @Param       mfa_token formData string true "MFA token"
+// @Param       code formData string true "TOTP code"
+func (r *userRoutes) MFAConfirmOnLogin(c *gin.Context) {
+	const op = "handlers.user.MFAConfirmOnLogin"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFALogin
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	confirmed, err := r.s.ConfirmMFALogin(req.MFAToken, req.Code, sessionMeta(c))
+	if err != nil {
+		log.Error("cannot confirm mfa", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, confirmed)
+}
+
+// GetMFAStatus
+// @Summary     Get 2FA status
+// @Description Get 2FA status of the current user
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {object} model.MFAStatus
+// @Router      /user/mfa [get]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) GetMFAStatus(c *gin.Context) {
+	const op = "handlers.user.GetMFAStatus"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	status, err := r.s.GetMFAStatus(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot get mfa status", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollMFA
+// @Summary     Start 2FA enrollment
+// @Description Generate a TOTP secret, provisioning URI and QR code. 2FA is enabled after confirmation.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Success     200 {object} model.MFAEnrollment
+// @Router      /user/mfa/enroll [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) EnrollMFA(c *gin.Context) {
+	const op = "handlers.user.EnrollMFA"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	enrollment, err := r.s.StartMFAEnrollment(c.GetInt64("user_id"))
+	if err != nil {
+		log.Error("cannot start mfa enrollment", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFA
+// @Summary     Confirm 2FA enrollment
+// @Description Enable 2FA with the first TOTP code. Returns recovery codes, they are shown only once.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Param request body model.MFACode true "request"
+// @Success     200 {object} model.MFAConfirmed
+// @Router      /user/mfa/confirm [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) ConfirmMFA(c *gin.Context) {
+	const op = "handlers.user.ConfirmMFA"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFACode
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	codes, err := r.s.ConfirmMFA(c.GetInt64("user_id"), req.Code)
+	if err != nil {
+		log.Error("cannot confirm mfa", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.MFAConfirmed{RecoveryCodes: codes})
+}
+
+// DisableMFA
+// @Summary     Disable 2FA
+// @Description Disable 2FA with a TOTP or recovery code
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Param request body model.MFACode true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/mfa/disable [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) DisableMFA(c *gin.Context) {
+	const op = "handlers.user.DisableMFA"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFACode
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.DisableMFA(c.GetInt64("user_id"), req.Code); err != nil {
+		log.Error("cannot disable mfa", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("two-factor authentication disabled"))
+}
+
+// RegenerateRecoveryCodes
+// @Summary     Regenerate recovery codes
+// @Description Replace all recovery codes. Requires a TOTP code.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Param request body model.MFACode true "request"
+// @Success     200 {object} model.MFAConfirmed
+// @Router      /user/mfa/recovery-codes [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) RegenerateRecoveryCodes(c *gin.Context) {
+	const op = "handlers.user.RegenerateRecoveryCodes"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.MFACode
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	codes, err := r.s.RegenerateRecoveryCodes(c.GetInt64("user_id"), req.Code)
+	if err != nil {
+		log.Error("cannot regenerate recovery codes", sl.Err(err))
+		abortMFA(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.MFAConfirmed{RecoveryCodes: codes})
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/password"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// PasswordPolicyError - ответ на пароль, не прошедший политику
+type PasswordPolicyError struct {
+	response.Response
+	Violations []password.Violation `json:"violations"`
+}
+
+// abortWeakPassword отвечает списком нарушений политики паролей.
+// Возвращает false, если err не связана с политикой.
+func abortWeakPassword(c *gin.Context, err error) bool {
+	var verr *password.ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, PasswordPolicyError{
+		Response:   response.Error(password.ErrWeakPassword.Error()),
+		Violations: verr.Violations,
+	})
+	return true
+}
+
+// PasswordPolicy
+// @Summary     Password policy
+// @Description Requirements for new passwords, to show hints before submitting the form
+// @Tags  	    user
+// @Produce     json
+// @Success     200 {object} password.Policy
+// @Router      /user/password/policy [get]
+func (r *userRoutes) PasswordPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, r.s.PasswordPolicy())
+}
@@ -0,0 +1,201 @@
+package user
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// abortPhone отвечает клиенту по ошибке подтверждения телефона или входа по SMS
+func abortPhone(c *gin.Context, err error) {
+	var retry *service.RetryAfterError
+	switch {
+	case errors.As(err, &retry):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(retry.Err.Error()))
+	case errors.Is(err, service.ErrInvalidPhone), errors.Is(err, service.ErrInvalidCode), errors.Is(err, service.ErrCodeExpired):
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+	case errors.Is(err, service.ErrTooManyCodeAttempts):
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(err.Error()))
+	case errors.Is(err, service.ErrPhoneExists), errors.Is(err, service.ErrPhoneAlreadyVerified):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
+
+// RequestPhoneVerification
+// @Summary     Add phone
+// @Description Send an SMS code to the phone number to link it to the account
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param request body model.PhoneRequest true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/phone [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) RequestPhoneVerification(c *gin.Context) {
+	const op = "handlers.user.RequestPhoneVerification"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.PhoneRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.RequestPhoneVerification(c.Request.Context(), c.GetInt64("user_id"), req.Phone); err != nil {
+		log.Error("cannot send phone verification code", sl.Err(err))
+		abortPhone(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("verification code sent"))
+}
+
+// VerifyPhone
+// @Summary     Verify phone
+// @Description Link the phone number to the account with the code from SMS
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param request body model.PhoneCodeRequest true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/phone/verify [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) VerifyPhone(c *gin.Context) {
+	const op = "handlers.user.VerifyPhone"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.PhoneCodeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.VerifyPhone(c.GetInt64("user_id"), req.Phone, req.Code); err != nil {
+		log.Error("cannot verify phone", sl.Err(err))
+		abortPhone(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("phone verified"))
+}
+
+// RemovePhone
+// @Summary     Remove phone
+// @Description Unlink the phone number; SMS login stops working
+// @Tags  	    user
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/phone [delete]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) RemovePhone(c *gin.Context) {
+	const op = "handlers.user.RemovePhone"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.RemovePhone(c.GetInt64("user_id")); err != nil {
+		log.Error("cannot remove phone", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("phone removed"))
+}
+
+// RequestPhoneLogin
+// @Summary     Request SMS login code
+// @Description Send a login code to a verified phone number. The response is the same for unknown numbers.
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/token/phone/code [post]
+// @Param       phone formData string true "Phone number"
+func (r *userRoutes) RequestPhoneLogin(c *gin.Context) {
+	const op = "handlers.user.RequestPhoneLogin"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.PhoneRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.RequestPhoneLogin(c.Request.Context(), req.Phone); err != nil {
+		log.Error("cannot send login code", sl.Err(err))
+		abortPhone(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("if the number is registered, a code has been sent"))
+}
+
+// PhoneLogin
+// @Summary     Login by SMS code
+// @Description Exchange a phone number and the code from SMS for access and refresh tokens
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Success     200 {object} model.Token
+// @Router      /user/token/phone [post]
+// @Param       phone formData string true "Phone number"
+// @Param       code formData string true "Code from SMS"
+func (r *userRoutes) PhoneLogin(c *gin.Context) {
+	const op = "handlers.user.PhoneLogin"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.PhoneCodeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	token, err := r.s.PhoneLogin(c.Request.Context(), req.Phone, req.Code, sessionMeta(c))
+	if err != nil {
+		log.Error("cannot login by phone", sl.Err(err))
+		abortPhone(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
@@ -2,10 +2,10 @@ package user
 
 import (
 	"errors"
-	"fmt"
-	"github.com/RCSE2025/backend-go/internal/http/middleware/admin"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/auth"
 	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/permission"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/ratelimit"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/service"
 	"github.com/RCSE2025/backend-go/pkg/api/response"
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
 )
@@ -21,26 +22,54 @@ type userRoutes struct {
 	s *service.UserService
 }
 
-func NewUserRoutes(h *gin.RouterGroup, s *service.UserService, jwtService service.JWTService) {
+func NewUserRoutes(h *gin.RouterGroup, s *service.UserService, jwtService service.JWTService, permissionService *service.PermissionService, limits *ratelimit.Policies) {
 	g := h.Group("/user")
 
 	ur := userRoutes{s: s}
 
 	validateJWTmw := auth.ValidateJWT(jwtService)
-	onlyAdmin := admin.OnlyAdmin()
-	g.POST("", ur.CreateUser)
+	canRead := permission.RequirePermission(permissionService, model.PermUserRead)
+	registerLimit := limits.Limit("registration", "10/1h", ratelimit.ByIP)
+	loginLimit := limits.Limit("login", "20/1m", ratelimit.ByIP)
+	resetLimit := limits.Limit("password_reset", "5/1h", ratelimit.ByIP)
+	verifyLimit := limits.Limit("email_verify", "10/1h", ratelimit.ByUser)
+	emailChangeLimit := limits.Limit("email_change", "5/1h", ratelimit.ByUser)
+	smsLimit := limits.Limit("sms", "10/1h", ratelimit.ByIP)
+	g.POST("", registerLimit, ur.CreateUser)
 	g.PUT("", validateJWTmw, ur.UpdateUser)
 	g.GET("/self", validateJWTmw, ur.Self)
-	g.DELETE("/self", validateJWTmw, ur.DeleteSelf)
-	g.GET("/:id", validateJWTmw, onlyAdmin, ur.GetUserByID)
-	g.DELETE("/:id", onlyAdmin, validateJWTmw, ur.DeleteUserByID)
-	g.GET("/all", validateJWTmw, onlyAdmin, ur.GetAllUsers)
-	g.POST("/token", ur.Token)
+	g.GET("/:id", validateJWTmw, canRead, ur.GetUserByID)
+	g.GET("/all", validateJWTmw, canRead, ur.GetAllUsers)
+	g.POST("/token", loginLimit, ur.Token)
+	g.POST("/token/mfa", loginLimit, ur.MFAToken)
+	g.POST("/token/phone/code", smsLimit, ur.RequestPhoneLogin)
+	g.POST("/token/phone", loginLimit, ur.PhoneLogin)
+	g.POST("/token/mfa/enroll", loginLimit, ur.MFAEnrollOnLogin)
+	g.POST("/token/mfa/confirm", loginLimit, ur.MFAConfirmOnLogin)
 	g.POST("/refresh", ur.RefreshToken)
-	g.POST("/email/verify", validateJWTmw, ur.VerifyEmail)
-	g.POST("/password/reset/email", ur.SendResetPasswordEmail)
-	g.POST("/password/reset", ur.RefreshPassword)
-	g.GET("/email", validateJWTmw, onlyAdmin, ur.GetUserByEmail)
+	g.POST("/unlock", loginLimit, ur.UnlockAccount)
+	g.POST("/logout", ur.Logout)
+	g.POST("/logout/all", validateJWTmw, ur.LogoutAll)
+	g.GET("/sessions", validateJWTmw, ur.GetSessions)
+	g.DELETE("/sessions/:id", validateJWTmw, ur.RevokeSession)
+	g.GET("/:id/sessions", validateJWTmw, canRead, ur.GetUserSessions)
+	g.GET("/mfa", validateJWTmw, ur.GetMFAStatus)
+	g.POST("/mfa/enroll", validateJWTmw, ur.EnrollMFA)
+	g.POST("/mfa/confirm", validateJWTmw, ur.ConfirmMFA)
+	g.POST("/mfa/disable", validateJWTmw, ur.DisableMFA)
+	g.POST("/mfa/recovery-codes", validateJWTmw, ur.RegenerateRecoveryCodes)
+	g.POST("/phone", validateJWTmw, smsLimit, ur.RequestPhoneVerification)
+	g.POST("/phone/verify", validateJWTmw, verifyLimit, ur.VerifyPhone)
+	g.DELETE("/phone", validateJWTmw, ur.RemovePhone)
+	g.POST("/email/verify", validateJWTmw, verifyLimit, ur.VerifyEmail)
+	g.POST("/email/verify/resend", validateJWTmw, verifyLimit, ur.ResendVerificationCode)
+	g.POST("/email/change", validateJWTmw, emailChangeLimit, ur.RequestEmailChange)
+	g.POST("/email/change/confirm", validateJWTmw, verifyLimit, ur.ConfirmEmailChange)
+	g.POST("/email/change/revert", loginLimit, ur.RevertEmailChange)
+	g.POST("/password/reset/email", resetLimit, ur.SendResetPasswordEmail)
+	g.POST("/password/reset", resetLimit, ur.RefreshPassword)
+	g.GET("/password/policy", ur.PasswordPolicy)
+	g.GET("/email", validateJWTmw, canRead, ur.GetUserByEmail)
 }
 
 // CreateUser
@@ -50,7 +79,8 @@ func NewUserRoutes(h *gin.RouterGroup, s *service.UserService, jwtService servic
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
+// @Failure     400 {object} PasswordPolicyError
+// @Failure     409 {object} response.Response
 // @Param request body model.UserCreate true "request"
 // @Success     201 {object} model.Token`
 // @Router      /user [post]
@@ -71,16 +101,23 @@ func (r *userRoutes) CreateUser(c *gin.Context) {
 	if err != nil {
 		log.Error("cannot create user", sl.Err(err))
 
-		if errors.Is(err, service.ErrEmailExists) {
+		if abortWeakPassword(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrEmailExists) || errors.Is(err, service.ErrPhoneExists) {
 			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
 			return
 		}
+		if errors.Is(err, service.ErrInvalidPhone) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
 
 		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
 		return
 	}
 
-	token, err := r.s.GetToken(user.Email, user.Password)
+	token, err := r.s.GetToken(c.Request.Context(), user.Email, user.Password, sessionMeta(c))
 	if err != nil {
 		log.Error("cannot get token", sl.Err(err))
 		if errors.Is(err, service.ErrWrongEmailOrPassword) {
@@ -140,52 +177,6 @@ func (r *userRoutes) GetUserByID(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-// DeleteUserByID
-// @Summary     Delete user by id
-// @Description Delete user by id
-// @Tags  	    user
-// @Accept      json
-// @Produce     json
-// @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
-// @Param id path string true "id"
-// @Success     200 {object} response.Response
-// @Router      /user/{id} [delete]
-// @Security OAuth2PasswordBearer
-func (r *userRoutes) DeleteUserByID(c *gin.Context) {
-	const op = "handlers.user.DeleteUserByID"
-	log := logger.FromContext(c).With(
-		slog.String("op", op),
-		slog.String("request_id", requestid.Get(c)),
-	)
-
-	id := c.Param("id")
-	if id == "" {
-		res := response.Error("invalid id")
-		c.AbortWithStatusJSON(http.StatusBadRequest, res)
-		return
-	}
-
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
-		return
-	}
-
-	err = r.s.DeleteUser(int64(idInt))
-	if err != nil {
-		log.Error("cannot delete user", sl.Err(err))
-		if errors.Is(err, service.ErrUserNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, response.OK())
-}
-
 // GetAllUsers
 // @Summary     Get all users
 // @Description Get all users
@@ -221,12 +212,13 @@ type TokenRequest struct {
 
 // Token
 // @Summary     Get token
-// @Description Get token
+// @Description Get token. Repeated failures are delayed and may temporarily lock the account.
 // @Tags  	    user
 // @Accept      application/x-www-form-urlencoded
 // @Produce     json
 // @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
 // @Success     200 {object} model.Token
 // @Router      /user/token [post]
 // @Param       username formData string true "Email"
@@ -243,12 +235,16 @@ func (r *userRoutes) Token(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
 		return
 	}
-	fmt.Println(req.Username)
-	fmt.Println(req.Password)
 
-	token, err := r.s.GetToken(req.Username, req.Password)
+	token, err := r.s.GetToken(c.Request.Context(), req.Username, req.Password, sessionMeta(c))
 	if err != nil {
 		log.Error("cannot get token", sl.Err(err))
+		var retry *service.RetryAfterError
+		if errors.As(err, &retry) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(retry.Err.Error()))
+			return
+		}
 		if errors.Is(err, service.ErrWrongEmailOrPassword) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
 			return
@@ -261,6 +257,43 @@ func (r *userRoutes) Token(c *gin.Context) {
 
 }
 
+// UnlockAccount
+// @Summary     Unlock account
+// @Description Lift a temporary login lockout with the token from the unlock email
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Param request body model.UnlockAccount true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/unlock [post]
+func (r *userRoutes) UnlockAccount(c *gin.Context) {
+	const op = "handlers.user.UnlockAccount"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.UnlockAccount
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.UnlockAccount(c.Request.Context(), req.Token, sessionMeta(c)); err != nil {
+		log.Error("cannot unlock account", sl.Err(err))
+		if errors.Is(err, service.ErrInvalidUnlockToken) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("account unlocked"))
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"required"`
 }
@@ -289,15 +322,80 @@ func (r *userRoutes) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	token, err := r.s.RefreshToken(req.RefreshToken)
+	token, err := r.s.RefreshToken(req.RefreshToken, sessionMeta(c))
 	if err != nil {
 		log.Error("cannot refresh token", sl.Err(err))
-		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) || errors.Is(err, service.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(err.Error()))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, token)
 }
 
+// Logout
+// @Summary     Logout
+// @Description Revoke the refresh token and all tokens issued from the same login
+// @Tags  	    user
+// @Accept      application/x-www-form-urlencoded
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     401 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/logout [post]
+// @Param       refresh_token formData string true "Refresh token"
+func (r *userRoutes) Logout(c *gin.Context) {
+	const op = "handlers.user.Logout"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req RefreshTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.Logout(req.RefreshToken); err != nil {
+		log.Error("cannot logout", sl.Err(err))
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(err.Error()))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, response.Success("logged out"))
+}
+
+// LogoutAll
+// @Summary     Logout everywhere
+// @Description Revoke all refresh tokens of the current user
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/logout/all [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) LogoutAll(c *gin.Context) {
+	const op = "handlers.user.LogoutAll"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.LogoutAll(c.GetInt64("user_id")); err != nil {
+		log.Error("cannot logout", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, response.Success("logged out from all sessions"))
+}
+
 // VerifyEmail
 // @Summary     Verify email
 // @Description Verify email
@@ -305,7 +403,8 @@ func (r *userRoutes) RefreshToken(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     429 {object} response.Response
 // @Success     200 {object} response.Response
 // @Router      /user/email/verify [post]
 // @Param       code query string true "Verification code"
@@ -327,13 +426,57 @@ func (r *userRoutes) VerifyEmail(c *gin.Context) {
 	err := r.s.VerifyEmail(userID, code)
 	if err != nil {
 		log.Error("cannot verify email", sl.Err(err))
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		switch {
+		case errors.Is(err, service.ErrInvalidCode), errors.Is(err, service.ErrCodeExpired):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, service.ErrTooManyCodeAttempts):
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Success("email verified"))
 }
 
+// ResendVerificationCode
+// @Summary     Resend verification code
+// @Description Send a new email verification code. Previous codes stop working.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Success     200 {object} response.Response
+// @Router      /user/email/verify/resend [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) ResendVerificationCode(c *gin.Context) {
+	const op = "handlers.user.ResendVerificationCode"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.ResendVerificationCode(c.GetInt64("user_id")); err != nil {
+		log.Error("cannot resend verification code", sl.Err(err))
+		var retry *service.RetryAfterError
+		switch {
+		case errors.As(err, &retry):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(retry.Err.Error()))
+		case errors.Is(err, service.ErrEmailAlreadyVerified):
+			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("verification code sent"))
+}
+
 // Self
 // @Summary     Get user
 // @Description Get user
@@ -440,6 +583,7 @@ type ResetPasswordRequest struct {
 // @Accept      application/x-www-form-urlencoded
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     400 {object} PasswordPolicyError
 // @Failure     404 {object} response.Response
 // @Success     200 {object} response.Response
 // @Router      /user/password/reset [post]
@@ -462,75 +606,291 @@ func (r *userRoutes) RefreshPassword(c *gin.Context) {
 	err := r.s.RefreshPassword(req.Token, req.Password)
 	if err != nil {
 		log.Error("cannot refresh password", sl.Err(err))
+		if abortWeakPassword(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+			return
+		}
 		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, response.Success("password refreshed"))
 }
 
-// DeleteSelf
-// @Summary     Delete self
-// @Description Delete self
+// UpdateUser
+// @Summary     Update user
+// @Description Update profile fields. Email is changed via /user/email/change.
 // @Tags  	    user
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
-// @Failure     404 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
 // @Success     200 {object} response.Response
-// @Router      /user/self [delete]
+// @Param request body model.UserUpdate true "request"
+// @Router      /user [put]
 // @Security OAuth2PasswordBearer
-func (r *userRoutes) DeleteSelf(c *gin.Context) {
-	const op = "handlers.user.DeleteSelf"
+func (r *userRoutes) UpdateUser(c *gin.Context) {
+	const op = "handlers.user.UpdateUser"
 	log := logger.FromContext(c).With(
 		slog.String("op", op),
 		slog.String("request_id", requestid.Get(c)),
 	)
-	userID := c.GetInt64("user_id")
-	err := c.ShouldBind(&userID)
-	if err != nil {
+	var user model.UserUpdate
+	if err := c.ShouldBindJSON(&user); err != nil {
 		log.Error("cannot bind request", sl.Err(err))
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
 		return
 	}
-	err = r.s.DeleteUser(userID)
+	userID := c.GetInt64("user_id")
+	err := r.s.UpdateUser(userID, user)
 	if err != nil {
-		log.Error("cannot delete user", sl.Err(err))
+		log.Error("cannot update user", sl.Err(err))
+		if errors.Is(err, service.ErrINNLocked) {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Error(err.Error()))
+			return
+		}
 		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, response.Success("user deleted"))
+	c.JSON(http.StatusOK, response.Success("user updated"))
 }
 
-// UpdateUser
-// @Summary     Update user
-// @Description Update user
+// RequestEmailChange
+// @Summary     Change email
+// @Description Send a confirmation code to the new address. The email stays the same until the code is confirmed via /user/email/change/confirm. Requires the current password; accounts without a password must have logged in within the last 10 minutes.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param request body model.EmailChangeRequest true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/email/change [post]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) RequestEmailChange(c *gin.Context) {
+	const op = "handlers.user.RequestEmailChange"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.RequestEmailChange(c.GetInt64("user_id"), c.GetString("session_id"), req); err != nil {
+		log.Error("cannot change email", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrEmailExists):
+			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+		case errors.Is(err, service.ErrWrongPassword), errors.Is(err, service.ErrSameEmail):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, service.ErrReauthenticationNeeded):
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("confirmation code sent to the new email"))
+}
+
+// ConfirmEmailChange
+// @Summary     Confirm email change
+// @Description Switch to the new email with the code sent to it. The old address gets a revert link.
 // @Tags  	    user
 // @Accept      json
 // @Produce     json
 // @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
 // @Failure     404 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Failure     429 {object} response.Response
+// @Param request body model.EmailChangeConfirm true "request"
 // @Success     200 {object} response.Response
-// @Param request body model.User true "request"
-// @Router      /user [put]
+// @Router      /user/email/change/confirm [post]
 // @Security OAuth2PasswordBearer
-func (r *userRoutes) UpdateUser(c *gin.Context) {
-	const op = "handlers.user.UpdateUser"
+func (r *userRoutes) ConfirmEmailChange(c *gin.Context) {
+	const op = "handlers.user.ConfirmEmailChange"
 	log := logger.FromContext(c).With(
 		slog.String("op", op),
 		slog.String("request_id", requestid.Get(c)),
 	)
-	var user model.User
-	if err := c.ShouldBind(&user); err != nil {
-		log.Error("cannot bind request", sl.Err(err))
+
+	var req model.EmailChangeConfirm
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
 		return
 	}
-	userID := c.GetInt64("user_id")
-	err := r.s.UpdateUser(userID, user)
+
+	if err := r.s.ConfirmEmailChange(c.GetInt64("user_id"), req.Code); err != nil {
+		log.Error("cannot confirm email change", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrEmailChangeNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+		case errors.Is(err, service.ErrInvalidCode), errors.Is(err, service.ErrCodeExpired):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, service.ErrTooManyCodeAttempts):
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error(err.Error()))
+		case errors.Is(err, service.ErrEmailExists):
+			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("email changed"))
+}
+
+// RevertEmailChange
+// @Summary     Revert email change
+// @Description Restore the previous email with the link sent to it. All sessions are revoked.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     409 {object} response.Response
+// @Param request body model.EmailChangeRevert true "request"
+// @Success     200 {object} response.Response
+// @Router      /user/email/change/revert [post]
+func (r *userRoutes) RevertEmailChange(c *gin.Context) {
+	const op = "handlers.user.RevertEmailChange"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	var req model.EmailChangeRevert
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		return
+	}
+
+	if err := r.s.RevertEmailChange(req.Token); err != nil {
+		log.Error("cannot revert email change", sl.Err(err))
+		switch {
+		case errors.Is(err, service.ErrInvalidRevertToken):
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error(err.Error()))
+		case errors.Is(err, service.ErrEmailExists):
+			c.AbortWithStatusJSON(http.StatusConflict, response.Error(err.Error()))
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("email change reverted"))
+}
+
+// sessionMeta собирает сведения об устройстве клиента для сессии
+func sessionMeta(c *gin.Context) model.SessionMeta {
+	return model.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.GetString("real_ip"),
+	}
+}
+
+// GetSessions
+// @Summary     Get active sessions
+// @Description Get active sessions of the current user with device and location
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Success     200 {array} model.Session
+// @Router      /user/sessions [get]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) GetSessions(c *gin.Context) {
+	const op = "handlers.user.GetSessions"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	sessions, err := r.s.GetSessions(c.GetInt64("user_id"), c.GetString("session_id"))
 	if err != nil {
-		log.Error("cannot update user", sl.Err(err))
+		log.Error("cannot get sessions", sl.Err(err))
 		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, response.Success("user updated"))
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession
+// @Summary     Revoke session
+// @Description End a session of the current user. Its refresh tokens stop working immediately.
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     404 {object} response.Response
+// @Param id path string true "session id"
+// @Success     200 {object} response.Response
+// @Router      /user/sessions/{id} [delete]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) RevokeSession(c *gin.Context) {
+	const op = "handlers.user.RevokeSession"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	if err := r.s.RevokeSession(c.GetInt64("user_id"), c.Param("id")); err != nil {
+		log.Error("cannot revoke session", sl.Err(err))
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("session revoked"))
+}
+
+// GetUserSessions
+// @Summary     Get user sessions
+// @Description Get active sessions of any user (support)
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Failure     500 {object} response.Response
+// @Failure     400 {object} response.Response
+// @Failure     403 {object} response.Response
+// @Param id path int true "user id"
+// @Success     200 {array} model.Session
+// @Router      /user/{id}/sessions [get]
+// @Security OAuth2PasswordBearer
+func (r *userRoutes) GetUserSessions(c *gin.Context) {
+	const op = "handlers.user.GetUserSessions"
+	log := logger.FromContext(c).With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.Get(c)),
+	)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("cannot parse id"))
+		return
+	}
+
+	sessions, err := r.s.GetSessions(userID, "")
+	if err != nil {
+		log.Error("cannot get sessions", sl.Err(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
 }
@@ -0,0 +1,32 @@
+package wellknown
+
+import (
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type wellKnownRoutes struct {
+	jwtService service.JWTService
+}
+
+func NewWellKnownRoutes(h *gin.RouterGroup, jwtService service.JWTService) {
+	g := h.Group("/.well-known")
+
+	wr := wellKnownRoutes{jwtService: jwtService}
+
+	g.GET("/jwks.json", wr.JWKS)
+}
+
+// JWKS
+// @Summary     Get JSON Web Key Set
+// @Description Public keys for verifying issued JWT. Tokens reference a key by kid.
+// @Tags  	    auth
+// @Produce     json
+// @Success     200 {object} jwks.JSONWebKeySet
+// @Router      /.well-known/jwks.json [get]
+func (r *wellKnownRoutes) JWKS(c *gin.Context) {
+	// Ключи меняются только при перезапуске, клиенты могут кешировать ответ
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, r.jwtService.JWKS())
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"github.com/RCSE2025/backend-go/internal/service"
 	"github.com/RCSE2025/backend-go/pkg/api/response"
 	"github.com/golang-jwt/jwt/v5"
@@ -22,34 +23,53 @@ func ValidateJWT(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 		authHeader = strings.Replace(authHeader, "Bearer ", "", -1)
+		// Токен разбирается и проверяется один раз, данные берутся из его claims
 		token, err := jwtService.ValidateToken(authHeader)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error("invalid token"))
 			return
 		}
-		if !token.Valid {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok || !token.Valid {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error("unauthorized"))
 			return
 		}
-		userId, err := jwtService.GetUserIDByToken(authHeader)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(err.Error()))
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error("user_id not found"))
 			return
 		}
+		userRole, _ := claims["role"].(string)
 
-		userRole := jwtService.GetUserRole(authHeader)
 		ctx.Set("user_role", userRole)
-		ctx.Set("user_id", userId)
-
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			ctx.Set("claims", claims)
-		} else {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			ctx.Abort()
-			return
+		ctx.Set("user_id", int64(userID))
+		ctx.Set("claims", claims)
+		if sid, ok := claims["sid"].(string); ok {
+			ctx.Set("session_id", sid)
 		}
 		ctx.Set("token", authHeader)
 
 		ctx.Next()
 	}
 }
+
+// RequireVerifiedEmail пропускает только пользователей с подтвержденной почтой.
+// Должен стоять после ValidateJWT.
+func RequireVerifiedEmail(s *service.UserService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		verified, err := s.EmailVerified(ctx.GetInt64("user_id"))
+		if errors.Is(err, service.ErrUserNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.Error("unauthorized"))
+			return
+		}
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+			return
+		}
+		if !verified {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response.Error(service.ErrEmailNotVerified.Error()))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package permission
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermission пропускает запрос, только если текущая роль пользователя
+// содержит все перечисленные права. Должен стоять после auth.ValidateJWT.
+func RequirePermission(s service.PermissionChecker, perms ...model.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := s.HasPermission(c.Request.Context(), c.GetInt64("user_id"), perms...)
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error("unauthorized"))
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Error("permission denied"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type authorizeFunc func(ctx context.Context, userID int64, id int64, action service.Action) error
+
+// Product пропускает запрос, если пользователю разрешено действие над товаром из параметра param.
+// Должен стоять после auth.ValidateJWT.
+func Product(p *service.PolicyService, action service.Action, param string) gin.HandlerFunc {
+	return authorize(p.AuthorizeProduct, action, param)
+}
+
+// Business пропускает запрос, если пользователю разрешено действие над бизнесом из параметра param.
+// Должен стоять после auth.ValidateJWT.
+func Business(p *service.PolicyService, action service.Action, param string) gin.HandlerFunc {
+	return authorize(p.AuthorizeBusiness, action, param)
+}
+
+func authorize(fn authorizeFunc, action service.Action, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param(param), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error("invalid "+param))
+			return
+		}
+
+		err = fn(c.Request.Context(), c.GetInt64("user_id"), id, action)
+		if err != nil {
+			Abort(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Abort отвечает клиенту по ошибке PolicyService
+func Abort(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrBusinessNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, response.Error(err.Error()))
+	case errors.Is(err, service.ErrForbidden), errors.Is(err, service.ErrBusinessNotVerified):
+		c.AbortWithStatusJSON(http.StatusForbidden, response.Error(err.Error()))
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(err.Error()))
+	}
+}
@@ -0,0 +1,298 @@
+package policy_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/RCSE2025/backend-go/internal/http/handlers/business"
+	"github.com/RCSE2025/backend-go/internal/http/handlers/product"
+	mwLogger "github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	businessID = 10
+	productID  = 20
+)
+
+type actor struct {
+	name       string
+	id         int64
+	memberRole model.MemberRole
+	role       model.UserRoleType
+}
+
+var actors = []actor{
+	{name: "owner", id: 1, memberRole: model.MemberOwner, role: model.BusinessRole},
+	{name: "manager", id: 2, memberRole: model.MemberManager, role: model.UserRole},
+	{name: "content_editor", id: 3, memberRole: model.MemberContentEditor, role: model.UserRole},
+	{name: "accountant", id: 4, memberRole: model.MemberAccountant, role: model.UserRole},
+	{name: "outsider", id: 5, role: model.BusinessRole},
+	{name: "admin", id: 6, role: model.AdminRole},
+	{name: "support", id: 7, role: model.SupportRole},
+}
+
+type fakeProducts struct{}
+
+func (fakeProducts) GetProductByID(ctx context.Context, id int64) (*model.Product, error) {
+	if id != productID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.Product{ID: productID, BusinessID: businessID}, nil
+}
+
+type fakeBusinesses struct{}
+
+func (fakeBusinesses) GetBusinessByID(id int64) (model.Business, error) {
+	if id != businessID {
+		return model.Business{}, gorm.ErrRecordNotFound
+	}
+	return model.Business{ID: businessID, VerificationStatus: model.BusinessVerified}, nil
+}
+
+func (fakeBusinesses) GetMemberRole(userID int64, id int64) (model.MemberRole, bool, error) {
+	for _, a := range actors {
+		if a.id == userID && id == businessID && a.memberRole != "" {
+			return a.memberRole, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// fakePermissions выдает права ролей по умолчанию
+type fakePermissions struct{}
+
+func (fakePermissions) HasPermission(ctx context.Context, userID int64, perms ...model.Permission) (bool, error) {
+	for _, a := range actors {
+		if a.id != userID {
+			continue
+		}
+		for _, p := range perms {
+			if !slices.Contains(model.DefaultRolePermissions[a.role], p) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// fakeJWT принимает в качестве токена id пользователя
+type fakeJWT struct {
+	service.JWTService
+}
+
+func (fakeJWT) ValidateToken(token string) (*jwt.Token, error) {
+	id, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &jwt.Token{Valid: true, Claims: jwt.MapClaims{"user_id": float64(id)}}, nil
+}
+
+var (
+	readers    = []string{"owner", "manager", "content_editor", "accountant", "admin", "support"}
+	writers    = []string{"owner", "manager", "admin"}
+	owners     = []string{"owner", "admin"}
+	finance    = []string{"owner", "manager", "accountant", "admin"}
+	publishers = []string{"owner", "manager", "content_editor", "admin"}
+	staff      = []string{"admin", "support"}
+	verifiers  = []string{"admin"}
+)
+
+type access struct {
+	public  bool     // доступен без токена
+	allowed []string // nil - любой вошедший пользователь
+	query   string
+	body    string
+}
+
+// routes - ожидаемый доступ к каждому маршруту business и product.
+// Маршрут без записи здесь считается ошибкой теста.
+var routes = map[string]access{
+	"GET /business/get_business_info/:inn": {public: true},
+	"POST /business":                       {},
+	"GET /business/all":                    {allowed: staff},
+	"GET /business/:id":                    {},
+	"PUT /business/:id":                    {allowed: writers},
+	"DELETE /business/:id":                 {allowed: owners},
+	"GET /business/inn/:inn":               {},
+	"GET /business/ogrn/:ogrn":             {},
+	"GET /business/user":                   {},
+	"GET /business/:id/users":              {allowed: readers},
+
+	"GET /business/:id/members":                       {allowed: readers},
+	"PUT /business/:id/members/:user_id":              {allowed: writers},
+	"DELETE /business/:id/members/:user_id":           {allowed: readers},
+	"POST /business/:id/ownership":                    {allowed: owners},
+	"GET /business/:id/invitations":                   {allowed: writers},
+	"POST /business/:id/invitations":                  {allowed: writers},
+	"DELETE /business/:id/invitations/:invitation_id": {allowed: writers},
+	"POST /business/invitations/accept":               {},
+	"POST /business/invitations/decline":              {},
+	"PUT /business/:id/storefront":                    {allowed: writers},
+	"DELETE /business/:id/storefront/:media":          {allowed: writers},
+	"GET /business/:id/analytics":                     {allowed: finance},
+	"GET /business/:id/balance":                       {allowed: finance},
+	"GET /business/:id/payouts":                       {allowed: finance},
+	"GET /business/:id/statements/:month":             {allowed: finance},
+	"GET /store/:slug":                                {public: true},
+	"GET /store/:slug/products":                       {public: true},
+	"GET /business/verifications":                     {allowed: verifiers},
+	"GET /business/:id/verification":                  {allowed: readers},
+	"POST /business/:id/verification":                 {allowed: writers},
+	"POST /business/:id/verification/approve":         {allowed: verifiers},
+	"POST /business/:id/verification/reject":          {allowed: verifiers},
+	"GET /business/:id/documents":                     {allowed: readers},
+	"POST /business/:id/documents":                    {allowed: writers},
+	"GET /business/:id/documents/:doc_id":             {allowed: readers},
+	"DELETE /business/:id/documents/:doc_id":          {allowed: writers},
+	"GET /product/categories":                         {public: true},
+	"GET /product/filter":                             {public: true},
+	"GET /product/:id":                                {public: true},
+	"GET /product/:id/reviews":                        {public: true},
+	"POST /product/:id/reviews":                       {},
+	"GET /product/:id/images":                         {public: true},
+	"GET /product":                                    {},
+	"PUT /product/:id":                                {allowed: publishers},
+	"DELETE /product/:id":                             {allowed: publishers},
+	"PUT /product/:id/status":                         {allowed: staff},
+	"POST /product/:id/images/upload":                 {},
+	"PUT /product/:id/images":                         {allowed: publishers},
+	"DELETE /product/:id/images/:image_id":            {allowed: publishers},
+	"PUT /product/:id/images/:image_id/primary":       {allowed: publishers},
+	"POST /product/images/upload":                     {allowed: publishers, query: "?product_id=20"},
+	"POST /product": {
+		allowed: publishers,
+		body:    `{"business_id":10,"price":1,"title":"t","description":"d","quantity":1,"category":"c"}`,
+	},
+}
+
+// newRouter собирает настоящие маршруты business и product. Сервисы, кроме
+// проверки доступа, не заданы: обработчик, до которого дошел запрос, падает,
+// и восстановление отвечает 500.
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(mwLogger.New(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	p := service.NewPolicyService(fakeProducts{}, fakeBusinesses{}, fakePermissions{})
+	h := r.Group("")
+	business.NewBusinessRoutes(h, nil, nil, nil, nil, nil, nil, nil, fakeJWT{}, p, fakePermissions{}, nil, nil)
+	product.NewProductRoutes(h, fakeJWT{}, nil, nil, p, fakePermissions{}, nil)
+	return r
+}
+
+// fillPath подставляет в шаблон маршрута id ресурса и значения остальных параметров
+func fillPath(path string) string {
+	id := int64(businessID)
+	if strings.HasPrefix(path, "/product/") {
+		id = productID
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		switch {
+		case part == ":id":
+			parts[i] = strconv.FormatInt(id, 10)
+		case part == ":month":
+			parts[i] = "2026-01"
+		case strings.HasPrefix(part, ":"):
+			parts[i] = "1"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// serve выполняет запрос от имени userID; 0 - без токена
+func serve(r *gin.Engine, method, target, body string, userID int64) int {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if userID != 0 {
+		req.Header.Set("Authorization", "Bearer "+strconv.FormatInt(userID, 10))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func denied(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+func TestRoutesByRole(t *testing.T) {
+	r := newRouter()
+
+	seen := make(map[string]bool)
+	for _, rt := range r.Routes() {
+		key := rt.Method + " " + rt.Path
+		exp, ok := routes[key]
+		if !ok {
+			t.Errorf("%s: no access expectation in routes", key)
+			continue
+		}
+		seen[key] = true
+		target := fillPath(rt.Path) + exp.query
+
+		t.Run(key+"/anonymous", func(t *testing.T) {
+			if got := serve(r, rt.Method, target, exp.body, 0); denied(got) == exp.public {
+				t.Errorf("status = %d, public = %v", got, exp.public)
+			}
+		})
+		for _, a := range actors {
+			t.Run(fmt.Sprintf("%s/%s", key, a.name), func(t *testing.T) {
+				want := exp.allowed == nil || slices.Contains(exp.allowed, a.name)
+				if got := serve(r, rt.Method, target, exp.body, a.id); denied(got) == want {
+					t.Errorf("status = %d, allowed = %v", got, want)
+				}
+			})
+		}
+	}
+
+	for key := range routes {
+		if !seen[key] {
+			t.Errorf("%s: route is not registered", key)
+		}
+	}
+}
+
+func TestUnknownResource(t *testing.T) {
+	r := newRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{name: "unknown business", method: http.MethodPut, target: "/business/999", want: http.StatusNotFound},
+		{name: "unknown product", method: http.MethodPut, target: "/product/999", want: http.StatusNotFound},
+		{name: "invalid business id", method: http.MethodPut, target: "/business/abc", want: http.StatusBadRequest},
+		{name: "invalid product id", method: http.MethodDelete, target: "/product/abc", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, a := range actors {
+				if got := serve(r, tt.method, tt.target, "", a.id); got != tt.want {
+					t.Errorf("%s: status = %d, want %d", a.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/http/middleware/logger"
+	rl "github.com/RCSE2025/backend-go/internal/ratelimit"
+	"github.com/RCSE2025/backend-go/pkg/api/response"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyFunc определяет, чью корзину расходует запрос
+type KeyFunc func(c *gin.Context) string
+
+// ByIP ограничивает запросы по IP клиента. Должен стоять после RealIPMiddleware.
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.GetString("real_ip")
+}
+
+// ByUser ограничивает запросы по пользователю, а для анонимных - по IP.
+// Для учета пользователя должен стоять после auth.ValidateJWT.
+func ByUser(c *gin.Context) string {
+	if id := c.GetInt64("user_id"); id != 0 {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return ByIP(c)
+}
+
+// Policies - именованные политики ограничения. Лимиты по умолчанию задаются
+// при подключении к маршруту и могут быть переопределены конфигурацией.
+type Policies struct {
+	limiter   rl.Limiter
+	overrides map[string]rl.Rate
+}
+
+// NewPolicies создает набор политик; если limiter равен nil, ограничение выключено
+func NewPolicies(limiter rl.Limiter, overrides map[string]string) (*Policies, error) {
+	p := &Policies{limiter: limiter, overrides: make(map[string]rl.Rate, len(overrides))}
+	for name, value := range overrides {
+		rate, err := rl.ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit policy %s: %w", name, err)
+		}
+		p.overrides[name] = rate
+	}
+	return p, nil
+}
+
+// Limit возвращает middleware политики name с лимитом rate ("10/1m") по умолчанию
+func (p *Policies) Limit(name string, rate string, key KeyFunc) gin.HandlerFunc {
+	if p == nil || p.limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	r := rl.MustParseRate(rate)
+	if override, ok := p.overrides[name]; ok {
+		r = override
+	}
+	policy := fmt.Sprintf("%d;w=%d", r.Limit, int(math.Ceil(r.Period.Seconds())))
+
+	return func(c *gin.Context) {
+		res, err := p.limiter.Allow(c.Request.Context(), name+":"+key(c), r)
+		if err != nil {
+			// Недоступность хранилища лимитов не должна останавливать сервис
+			logger.FromContext(c).Error("rate limiter failed", slog.String("policy", name), sl.Err(err))
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", seconds(res.ResetAfter))
+
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error("rate limit exceeded"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
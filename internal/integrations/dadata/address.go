@@ -0,0 +1,55 @@
+package dadata
+
+import "context"
+
+// Address - поля адреса в ответах suggest и clean
+type Address struct {
+	PostalCode         string `json:"postal_code"`
+	RegionWithType     string `json:"region_with_type"`
+	CityWithType       string `json:"city_with_type"`
+	SettlementWithType string `json:"settlement_with_type"`
+	StreetWithType     string `json:"street_with_type"`
+	HouseType          string `json:"house_type"`
+	House              string `json:"house"`
+	BlockType          string `json:"block_type"`
+	Block              string `json:"block"`
+	Flat               string `json:"flat"`
+	FiasID             string `json:"fias_id"`
+	KladrID            string `json:"kladr_id"`
+	GeoLat             string `json:"geo_lat"`
+	GeoLon             string `json:"geo_lon"`
+}
+
+type AddressSuggestion struct {
+	Value string  `json:"value"`
+	Data  Address `json:"data"`
+}
+
+type CleanAddress struct {
+	Address
+	Result string `json:"result"`
+	// QC - код качества разбора: 0 - уверенно, 1 - остались лишние части,
+	// 2 - пустой или мусорный адрес, 3 - несколько вариантов
+	QC int `json:"qc"`
+}
+
+const QCGarbage = 2
+
+func (c *Client) SuggestAddress(ctx context.Context, query string, count int) ([]AddressSuggestion, error) {
+	var resp struct {
+		Suggestions []AddressSuggestion `json:"suggestions"`
+	}
+	if err := c.post(ctx, suggestAddressURL, map[string]any{"query": query, "count": count}, false, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Suggestions, nil
+}
+
+// CleanAddress стандартизирует адрес. Требует секретного ключа, см. CanClean.
+func (c *Client) CleanAddress(ctx context.Context, raw string) ([]CleanAddress, error) {
+	var results []CleanAddress
+	if err := c.post(ctx, cleanAddressURL, []string{raw}, true, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,60 @@
+package dadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PartyCache хранит ответы DaData по ИНН: сведения из ЕГРЮЛ меняются редко,
+// а каждый запрос расходует платную квоту
+type PartyCache interface {
+	GetParty(ctx context.Context, inn string) (Party, bool, error)
+	PutParty(ctx context.Context, p Party, ttl time.Duration) error
+}
+
+type PostgresCache struct {
+	db *gorm.DB
+}
+
+func NewPostgresCache(db *gorm.DB) *PostgresCache {
+	return &PostgresCache{db: db}
+}
+
+func (c *PostgresCache) GetParty(ctx context.Context, inn string) (Party, bool, error) {
+	var row model.DaDataParty
+	err := c.db.WithContext(ctx).Where("inn = ? AND expires_at > ?", inn, time.Now()).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Party{}, false, nil
+	}
+	if err != nil {
+		return Party{}, false, err
+	}
+
+	var p Party
+	if err := json.Unmarshal([]byte(row.Data), &p); err != nil {
+		return Party{}, false, err
+	}
+	return p, true, nil
+}
+
+func (c *PostgresCache) PutParty(ctx context.Context, p Party, ttl time.Duration) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	row := model.DaDataParty{INN: p.INN, Data: string(data), FetchedAt: now, ExpiresAt: now.Add(ttl)}
+	return c.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error
+}
+
+// Cleanup удаляет просроченные записи
+func (c *PostgresCache) Cleanup(ctx context.Context) error {
+	return c.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&model.DaDataParty{}).Error
+}
@@ -0,0 +1,130 @@
+// Package dadata is a client for the DaData suggestions and cleaner APIs.
+package dadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+var (
+	ErrNotConfigured = errors.New("dadata api key is not configured")
+	ErrInvalidINN    = errors.New("invalid inn")
+	ErrPartyNotFound = errors.New("party not found")
+)
+
+const (
+	suggestAddressURL = "https://suggestions.dadata.ru/suggestions/api/4_1/rs/suggest/address"
+	findPartyURL      = "https://suggestions.dadata.ru/suggestions/api/4_1/rs/findById/party"
+	cleanAddressURL   = "https://cleaner.dadata.ru/api/v1/clean/address"
+)
+
+// Client ходит в DaData с таймаутом и повторяет запрос при сетевых ошибках,
+// 429 и 5xx. Ответы по организациям кешируются по ИНН, если задан cache.
+type Client struct {
+	http       *http.Client
+	apiKey     string
+	secretKey  string
+	retries    int
+	retryDelay time.Duration
+
+	cache    PartyCache
+	cacheTTL time.Duration
+}
+
+func NewClient(cfg config.DaDataConfig, cache PartyCache) *Client {
+	return &Client{
+		http:       &http.Client{Timeout: cfg.Timeout},
+		apiKey:     cfg.APIKey,
+		secretKey:  cfg.SecretKey,
+		retries:    cfg.Retries,
+		retryDelay: cfg.RetryDelay,
+		cache:      cache,
+		cacheTTL:   cfg.PartyCacheTTL,
+	}
+}
+
+// Configured сообщает, задан ли API-ключ
+func (c *Client) Configured() bool {
+	return c.apiKey != ""
+}
+
+// CanClean сообщает, доступна ли стандартизация: она требует секретного ключа
+func (c *Client) CanClean() bool {
+	return c.apiKey != "" && c.secretKey != ""
+}
+
+// statusError - ответ DaData с неуспешным HTTP-статусом
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("dadata: unexpected status %d", e.code)
+}
+
+func (e *statusError) temporary() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= http.StatusInternalServerError
+}
+
+func (c *Client) post(ctx context.Context, url string, body any, secret bool, out any) error {
+	if !c.Configured() {
+		return ErrNotConfigured
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	delay := c.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := c.do(ctx, url, payload, secret, out)
+		if err == nil || !retry || attempt >= c.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// do выполняет один запрос. retry сообщает, имеет ли смысл его повторить:
+// ответы 4xx (кроме 429), ошибки разбора и отмену контекста повтор не исправит.
+func (c *Client) do(ctx context.Context, url string, payload []byte, secret bool, out any) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Token "+c.apiKey)
+	if secret {
+		req.Header.Set("X-Secret", c.secretKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("dadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		se := &statusError{code: resp.StatusCode}
+		return se.temporary(), se
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("dadata: decode response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,142 @@
+package dadata
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/requisites"
+)
+
+type PartyType string
+
+const (
+	PartyLegal      PartyType = "LEGAL"
+	PartyIndividual PartyType = "INDIVIDUAL"
+)
+
+// PartyStatus - состояние организации в ЕГРЮЛ/ЕГРИП
+type PartyStatus string
+
+const (
+	PartyActive       PartyStatus = "ACTIVE"
+	PartyLiquidating  PartyStatus = "LIQUIDATING"
+	PartyLiquidated   PartyStatus = "LIQUIDATED"
+	PartyBankrupt     PartyStatus = "BANKRUPT"
+	PartyReorganizing PartyStatus = "REORGANIZING"
+)
+
+// Party - сведения об организации или ИП из ЕГРЮЛ/ЕГРИП
+type Party struct {
+	INN       string      `json:"inn"`
+	KPP       string      `json:"kpp,omitempty"`
+	OGRN      string      `json:"ogrn,omitempty"`
+	OGRNDate  *time.Time  `json:"ogrn_date,omitempty"`
+	Type      PartyType   `json:"type"`
+	Status    PartyStatus `json:"status"`
+	ShortName string      `json:"short_name"`
+	FullName  string      `json:"full_name"`
+	// Manager - руководитель организации, для ИП - сам предприниматель
+	Manager     string `json:"manager,omitempty"`
+	ManagerPost string `json:"manager_post,omitempty"`
+	Address     string `json:"address,omitempty"`
+	OKVED       string `json:"okved,omitempty"`
+}
+
+type partyResponse struct {
+	Suggestions []struct {
+		Value string    `json:"value"`
+		Data  partyData `json:"data"`
+	} `json:"suggestions"`
+}
+
+type partyData struct {
+	INN      string `json:"inn"`
+	KPP      string `json:"kpp"`
+	OGRN     string `json:"ogrn"`
+	OGRNDate *int64 `json:"ogrn_date"` // миллисекунды unix
+	Type     string `json:"type"`
+	OKVED    string `json:"okved"`
+	State    struct {
+		Status string `json:"status"`
+	} `json:"state"`
+	Name struct {
+		FullWithOPF  string `json:"full_with_opf"`
+		ShortWithOPF string `json:"short_with_opf"`
+	} `json:"name"`
+	Management *struct {
+		Name string `json:"name"`
+		Post string `json:"post"`
+	} `json:"management"`
+	FIO *struct {
+		Surname    string `json:"surname"`
+		Name       string `json:"name"`
+		Patronymic string `json:"patronymic"`
+	} `json:"fio"`
+	Address *struct {
+		UnrestrictedValue string `json:"unrestricted_value"`
+	} `json:"address"`
+}
+
+// FindPartyByINN ищет головную организацию или ИП по ИНН. Сначала
+// проверяется кеш; промах кеша или ошибка кеша ведут к запросу в DaData.
+func (c *Client) FindPartyByINN(ctx context.Context, inn string) (Party, error) {
+	inn = strings.TrimSpace(inn)
+	if !requisites.ValidINN(inn) {
+		return Party{}, ErrInvalidINN
+	}
+
+	if c.cache != nil && c.cacheTTL > 0 {
+		if p, ok, err := c.cache.GetParty(ctx, inn); err == nil && ok {
+			return p, nil
+		}
+	}
+
+	var resp partyResponse
+	body := map[string]any{"query": inn, "branch_type": "MAIN", "count": 1}
+	if err := c.post(ctx, findPartyURL, body, false, &resp); err != nil {
+		return Party{}, err
+	}
+	if len(resp.Suggestions) == 0 {
+		return Party{}, ErrPartyNotFound
+	}
+
+	p := resp.Suggestions[0].Data.party(resp.Suggestions[0].Value)
+	if c.cache != nil && c.cacheTTL > 0 {
+		// Кеш - оптимизация: ошибка записи не должна ломать запрос
+		_ = c.cache.PutParty(ctx, p, c.cacheTTL)
+	}
+	return p, nil
+}
+
+func (d partyData) party(value string) Party {
+	p := Party{
+		INN:       d.INN,
+		KPP:       d.KPP,
+		OGRN:      d.OGRN,
+		Type:      PartyType(d.Type),
+		Status:    PartyStatus(d.State.Status),
+		ShortName: d.Name.ShortWithOPF,
+		FullName:  d.Name.FullWithOPF,
+		OKVED:     d.OKVED,
+	}
+	if p.ShortName == "" {
+		p.ShortName = value
+	}
+	if d.OGRNDate != nil {
+		t := time.UnixMilli(*d.OGRNDate).UTC()
+		p.OGRNDate = &t
+	}
+	if d.Address != nil {
+		p.Address = d.Address.UnrestrictedValue
+	}
+
+	switch {
+	case d.Management != nil && d.Management.Name != "":
+		p.Manager = d.Management.Name
+		p.ManagerPost = d.Management.Post
+	case d.FIO != nil:
+		p.Manager = strings.Join(strings.Fields(d.FIO.Surname+" "+d.FIO.Name+" "+d.FIO.Patronymic), " ")
+	}
+	return p
+}
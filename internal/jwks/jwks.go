@@ -0,0 +1,289 @@
+// Package jwks loads asymmetric keys used to sign JWT and publishes their
+// public parts as a JSON Web Key Set (RFC 7517), so that other services can
+// verify tokens without a shared secret.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSABits = 2048
+
+// Key - открытый ключ проверки подписи
+type Key struct {
+	// ID - отпечаток ключа по RFC 7638, передается в заголовке kid
+	ID     string
+	Method jwt.SigningMethod
+	Public crypto.PublicKey
+}
+
+// SigningKey - ключ, которым подписываются новые токены
+type SigningKey struct {
+	Key
+	Private crypto.PrivateKey
+}
+
+// Set содержит ключ подписи и все ключи, которыми принимаются токены.
+// Во время ротации старый ключ остается в списке проверки, пока не истекут
+// выданные им токены.
+type Set struct {
+	signing SigningKey
+	keys    map[string]Key
+	order   []string
+}
+
+// JSONWebKey - открытый ключ в формате JWK
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Load читает закрытый ключ подписи (RSA или Ed25519, PEM) и дополнительные
+// ключи проверки. Файлы проверки могут содержать открытый или закрытый ключ.
+func Load(signingPath string, verificationPaths []string) (*Set, error) {
+	if signingPath == "" {
+		return nil, errors.New("signing key file is not set")
+	}
+
+	data, err := os.ReadFile(signingPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read signing key: %w", err)
+	}
+	private, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse signing key %s: %w", signingPath, err)
+	}
+
+	s, err := newSet(private)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range verificationPaths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read verification key: %w", err)
+		}
+		public, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse verification key %s: %w", path, err)
+		}
+		if err := s.add(public); err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// Generate создает временный ключ Ed25519. Токены, подписанные им,
+// перестают приниматься после перезапуска, поэтому он подходит только для разработки.
+func Generate() (*Set, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return newSet(private)
+}
+
+func newSet(private crypto.PrivateKey) (*Set, error) {
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+
+	s := &Set{keys: make(map[string]Key)}
+	if err := s.add(signer.Public()); err != nil {
+		return nil, err
+	}
+	s.signing = SigningKey{Key: s.keys[s.order[0]], Private: private}
+	return s, nil
+}
+
+func (s *Set) add(public crypto.PublicKey) error {
+	key, err := newKey(public)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.keys[key.ID]; ok {
+		return nil
+	}
+	s.keys[key.ID] = key
+	s.order = append(s.order, key.ID)
+	return nil
+}
+
+// Signing возвращает текущий ключ подписи
+func (s *Set) Signing() SigningKey {
+	return s.signing
+}
+
+// Lookup ищет ключ проверки по kid
+func (s *Set) Lookup(kid string) (Key, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// Methods возвращает алгоритмы всех ключей проверки
+func (s *Set) Methods() []string {
+	methods := make([]string, 0, 2)
+	seen := make(map[string]bool, 2)
+	for _, kid := range s.order {
+		alg := s.keys[kid].Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS возвращает открытые части всех ключей проверки
+func (s *Set) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(s.order))}
+	for _, kid := range s.order {
+		jwk, _ := toJWK(s.keys[kid].Public)
+		jwk.Kid = kid
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func newKey(public crypto.PublicKey) (Key, error) {
+	var method jwt.SigningMethod
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("rsa key must be at least %d bits", minRSABits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+
+	kid, err := thumbprint(public)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: kid, Method: method, Public: public}, nil
+}
+
+func toJWK(public crypto.PublicKey) (JSONWebKey, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return JSONWebKey{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JSONWebKey{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, nil
+	}
+	return JSONWebKey{}, fmt.Errorf("unsupported key type %T", public)
+}
+
+// thumbprint вычисляет отпечаток JWK по RFC 7638: SHA-256 от обязательных
+// полей ключа в лексикографическом порядке
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(public)
+	if err != nil {
+		return "", err
+	}
+
+	var fields any
+	if jwk.Kty == "RSA" {
+		fields = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		fields = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	private, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+	return signer.Public(), nil
+}
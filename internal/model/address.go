@@ -0,0 +1,87 @@
+package model
+
+import "strings"
+
+// AddressFields - структурированный адрес доставки. FIAS и KLADR заполняются,
+// если адрес выбран из подсказок.
+type AddressFields struct {
+	Region         string   `json:"region" gorm:"size:128"`
+	City           string   `json:"city" gorm:"size:128"`
+	Street         string   `json:"street" gorm:"size:255"`
+	House          string   `json:"house" gorm:"size:32"`
+	Apartment      string   `json:"apartment,omitempty" gorm:"size:32"`
+	Postcode       string   `json:"postcode,omitempty" gorm:"size:6"`
+	FiasID         string   `json:"fias_id,omitempty" gorm:"size:36"`
+	KladrID        string   `json:"kladr_id,omitempty" gorm:"size:19"`
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	RecipientName  string   `json:"recipient_name" gorm:"size:255"`
+	RecipientPhone string   `json:"recipient_phone" gorm:"size:16"` // E.164
+}
+
+// String возвращает адрес одной строкой: "390000, Рязанская обл, г Рязань, ул Ленина, д. 1, кв. 2"
+func (a AddressFields) String() string {
+	parts := make([]string, 0, 6)
+	for _, p := range []string{a.Postcode, a.Region, a.City, a.Street} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if a.House != "" {
+		parts = append(parts, "д. "+a.House)
+	}
+	if a.Apartment != "" {
+		parts = append(parts, "кв. "+a.Apartment)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UserAddress - сохраненный адрес доставки пользователя
+type UserAddress struct {
+	BaseModel
+	ID            int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        int64  `json:"-" gorm:"not null;index;uniqueIndex:idx_user_addresses_default,where:is_default"`
+	Label         string `json:"label,omitempty" gorm:"size:64"` // "Дом", "Работа"
+	AddressFields `gorm:"embedded"`
+	IsDefault     bool `json:"is_default" gorm:"not null;default:false"`
+}
+
+func (UserAddress) TableName() string {
+	return "user_addresses"
+}
+
+type UserAddressInput struct {
+	Label          string   `json:"label" binding:"max=64"`
+	Region         string   `json:"region" binding:"required,max=128"`
+	City           string   `json:"city" binding:"required,max=128"`
+	Street         string   `json:"street" binding:"max=255"`
+	House          string   `json:"house" binding:"required,max=32"`
+	Apartment      string   `json:"apartment" binding:"max=32"`
+	Postcode       string   `json:"postcode" binding:"omitempty,numeric,len=6"`
+	FiasID         string   `json:"fias_id" binding:"omitempty,uuid"`
+	KladrID        string   `json:"kladr_id" binding:"omitempty,numeric,max=19"`
+	Latitude       *float64 `json:"latitude" binding:"omitempty,latitude"`
+	Longitude      *float64 `json:"longitude" binding:"omitempty,longitude"`
+	RecipientName  string   `json:"recipient_name" binding:"required,max=255"`
+	RecipientPhone string   `json:"recipient_phone" binding:"required"`
+	IsDefault      bool     `json:"is_default"`
+}
+
+// AddressSuggestion - адрес из подсказок или после стандартизации
+type AddressSuggestion struct {
+	Value     string   `json:"value"` // адрес одной строкой
+	Region    string   `json:"region"`
+	City      string   `json:"city"`
+	Street    string   `json:"street"`
+	House     string   `json:"house"`
+	Apartment string   `json:"apartment,omitempty"`
+	Postcode  string   `json:"postcode,omitempty"`
+	FiasID    string   `json:"fias_id,omitempty"`
+	KladrID   string   `json:"kladr_id,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+type AddressNormalizeRequest struct {
+	Address string `json:"address" binding:"required,max=500"`
+}
@@ -0,0 +1,133 @@
+package model
+
+import "time"
+
+// ProductEventKind - действие покупателя с товаром для воронки продаж
+type ProductEventKind string
+
+const (
+	ProductEventView ProductEventKind = "view"
+	ProductEventCart ProductEventKind = "cart"
+)
+
+// ProductEvent - просмотр товара или добавление в корзину
+type ProductEvent struct {
+	ID         int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID  int64            `json:"product_id" gorm:"not null;index"`
+	BusinessID int64            `json:"business_id" gorm:"not null;index"`
+	Kind       ProductEventKind `json:"kind" gorm:"size:10;not null" swaggertype:"primitive,string"`
+	UserID     *int64           `json:"user_id,omitempty"`
+	CreatedAt  time.Time        `json:"created_at" gorm:"not null;index"`
+}
+
+// ProductDailyStats - дневной агрегат по товару. Заполняется задачей свертки
+// из заказов, возвратов и событий товаров.
+type ProductDailyStats struct {
+	ProductID  int64    `gorm:"primaryKey;autoIncrement:false"`
+	Day        DateOnly `gorm:"primaryKey;type:date"`
+	BusinessID int64    `gorm:"not null;index"`
+	Revenue    float64  `gorm:"not null;default:0"`
+	Units      int64    `gorm:"not null;default:0"`
+	Orders     int64    `gorm:"not null;default:0"`
+	Refunds    float64  `gorm:"not null;default:0"`
+	Views      int64    `gorm:"not null;default:0"`
+	CartAdds   int64    `gorm:"not null;default:0"`
+	UpdatedAt  time.Time
+}
+
+func (ProductDailyStats) TableName() string {
+	return "product_daily_stats"
+}
+
+// BusinessDailyStats - дневной агрегат по бизнесу. Заказ с товарами нескольких
+// продавцов учитывается у каждого из них, выручка - только по своим товарам.
+type BusinessDailyStats struct {
+	BusinessID     int64    `gorm:"primaryKey;autoIncrement:false"`
+	Day            DateOnly `gorm:"primaryKey;type:date"`
+	Revenue        float64  `gorm:"not null;default:0"`
+	Orders         int64    `gorm:"not null;default:0"`
+	Units          int64    `gorm:"not null;default:0"`
+	Refunds        float64  `gorm:"not null;default:0"`
+	RefundedOrders int64    `gorm:"not null;default:0"`
+	Views          int64    `gorm:"not null;default:0"`
+	CartAdds       int64    `gorm:"not null;default:0"`
+	UpdatedAt      time.Time
+}
+
+func (BusinessDailyStats) TableName() string {
+	return "business_daily_stats"
+}
+
+// AnalyticsInterval - шаг группировки ряда
+type AnalyticsInterval string
+
+const (
+	AnalyticsDay   AnalyticsInterval = "day"
+	AnalyticsWeek  AnalyticsInterval = "week"
+	AnalyticsMonth AnalyticsInterval = "month"
+)
+
+// AnalyticsQuery - параметры GET /business/{id}/analytics. Даты включительно,
+// по умолчанию - последние 30 дней.
+type AnalyticsQuery struct {
+	From     string            `form:"from" example:"2026-01-01"`
+	To       string            `form:"to" example:"2026-01-31"`
+	Interval AnalyticsInterval `form:"interval,default=day" binding:"omitempty,oneof=day week month" swaggertype:"primitive,string"`
+	Top      int               `form:"top,default=10" binding:"omitempty,min=1,max=50"`
+}
+
+type AnalyticsMetrics struct {
+	Revenue           float64 `json:"revenue"`
+	Orders            int64   `json:"orders"`
+	AverageOrderValue float64 `json:"average_order_value"`
+	Units             int64   `json:"units"`
+	Refunds           float64 `json:"refunds"`
+	RefundedOrders    int64   `json:"refunded_orders"`
+}
+
+type AnalyticsBucket struct {
+	Start DateOnly `json:"start" swaggertype:"string" format:"date"`
+	AnalyticsMetrics
+}
+
+type TopProduct struct {
+	ProductID int64   `json:"product_id"`
+	Title     string  `json:"title"`
+	Revenue   float64 `json:"revenue"`
+	Units     int64   `json:"units"`
+	Orders    int64   `json:"orders"`
+}
+
+// AnalyticsFunnel - переход от просмотров к корзине и заказу. Конверсии - доли от 0 до 1.
+type AnalyticsFunnel struct {
+	Views       int64   `json:"views"`
+	CartAdds    int64   `json:"cart_adds"`
+	Orders      int64   `json:"orders"`
+	ViewToCart  float64 `json:"view_to_cart"`
+	CartToOrder float64 `json:"cart_to_order"`
+	ViewToOrder float64 `json:"view_to_order"`
+}
+
+// StockForecast - прогноз окончания остатка по среднему темпу продаж.
+// Без продаж за окно прогноза DaysLeft и StockOutDate не заполняются.
+type StockForecast struct {
+	ProductID    int64     `json:"product_id"`
+	Title        string    `json:"title"`
+	Quantity     int       `json:"quantity"`
+	DailyUnits   float64   `json:"daily_units"`
+	DaysLeft     *float64  `json:"days_left,omitempty"`
+	StockOutDate *DateOnly `json:"stock_out_date,omitempty" swaggertype:"string" format:"date"`
+}
+
+type BusinessAnalytics struct {
+	From          DateOnly          `json:"from" swaggertype:"string" format:"date"`
+	To            DateOnly          `json:"to" swaggertype:"string" format:"date"`
+	Interval      AnalyticsInterval `json:"interval" swaggertype:"primitive,string"`
+	Totals        AnalyticsMetrics  `json:"totals"`
+	Series        []AnalyticsBucket `json:"series"`
+	TopProducts   []TopProduct      `json:"top_products"`
+	Funnel        AnalyticsFunnel   `json:"funnel"`
+	StockForecast []StockForecast   `json:"stock_forecast"`
+	// UpdatedAt - время последней свертки; более свежие продажи еще не учтены
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
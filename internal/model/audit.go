@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// LoginAttempt - счетчик неудачных входов по ключу (аккаунт или IP)
+type LoginAttempt struct {
+	Key           string    `gorm:"primaryKey;size:320"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"not null;index"`
+}
+
+type AuthEventType string
+
+const (
+	AuthEventLoginFailed     AuthEventType = "login_failed"
+	AuthEventAccountLocked   AuthEventType = "account_locked"
+	AuthEventAccountUnlocked AuthEventType = "account_unlocked"
+)
+
+// AuthEvent - запись журнала аудита аутентификации
+type AuthEvent struct {
+	ID        int64         `json:"id" gorm:"primaryKey;autoIncrement"`
+	Type      AuthEventType `json:"type" gorm:"size:32;not null;index"`
+	UserID    *int64        `json:"user_id,omitempty" gorm:"index"`
+	Email     string        `json:"email" gorm:"size:320"`
+	IP        string        `json:"ip" gorm:"size:64"`
+	UserAgent string        `json:"user_agent"`
+	Reason    string        `json:"reason,omitempty" gorm:"size:64"`
+	CreatedAt time.Time     `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+type UnlockAccount struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
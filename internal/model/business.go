@@ -1,16 +1,92 @@
 package model
 
+import "time"
+
+// BusinessVerificationStatus - состояние проверки бизнеса. Публиковать товары
+// и получать выплаты может только бизнес в статусе verified.
+type BusinessVerificationStatus string
+
+const (
+	BusinessUnverified BusinessVerificationStatus = "unverified"
+	BusinessPending    BusinessVerificationStatus = "pending"
+	BusinessVerified   BusinessVerificationStatus = "verified"
+	BusinessRejected   BusinessVerificationStatus = "rejected"
+)
+
 type Business struct {
 	BaseModel
 	ID        int64   `json:"id" gorm:"primaryKey;autoIncrement"`
 	INN       int64   `json:"inn" gorm:"unique;not null"`
 	OGRN      *int64  `json:"ogrn,omitempty" gorm:"unique"`
-	Owner     *string `json:"owner,omitempty" gorm:"size:100"`
-	ShortName *string `json:"short_name,omitempty" gorm:"size:100"`
-	FullName  *string `json:"full_name,omitempty" gorm:"size:100"`
+	Owner     *string `json:"owner,omitempty" gorm:"size:255"`
+	ShortName *string `json:"short_name,omitempty" gorm:"size:255"`
+	FullName  *string `json:"full_name,omitempty" gorm:"size:1000"`
 	Address   *string `json:"address,omitempty" gorm:"size:10000"`
+
+	// Меняется только через проверку, значения из запроса игнорируются
+	VerificationStatus BusinessVerificationStatus `json:"verification_status" gorm:"size:20;not null;default:unverified;index" swaggertype:"primitive,string"`
+	VerifiedAt         *time.Time                 `json:"verified_at,omitempty"`
+
+	// Витрина меняется через /business/{id}/storefront и загрузки, значения из запроса игнорируются
+	Slug        *string `json:"slug,omitempty" gorm:"size:63;uniqueIndex"`
+	Description *string `json:"description,omitempty" gorm:"size:5000"`
+	Logo        *string `json:"logo,omitempty" gorm:"size:255"`
+	Banner      *string `json:"banner,omitempty" gorm:"size:255"`
 }
 
 func (b *Business) TableName() string {
 	return "businesses"
 }
+
+// RegistryUnavailable - сверку с ЕГРЮЛ выполнить не удалось, проверяющий сверяет вручную
+const RegistryUnavailable = "unavailable"
+
+// BusinessVerification - заявка на проверку бизнеса. Сверка с ЕГРЮЛ выполняется
+// при подаче, решение по документам принимает администратор.
+type BusinessVerification struct {
+	ID         int64                      `json:"id" gorm:"primaryKey;autoIncrement"`
+	BusinessID int64                      `json:"business_id" gorm:"not null;index"`
+	Status     BusinessVerificationStatus `json:"status" gorm:"size:20;not null;index" swaggertype:"primitive,string"`
+	// Состояние организации в ЕГРЮЛ/ЕГРИП на момент подачи или RegistryUnavailable
+	RegistryStatus string     `json:"registry_status" gorm:"size:20;not null"`
+	RegistryName   string     `json:"registry_name,omitempty" gorm:"size:1000"`
+	SubmittedBy    int64      `json:"submitted_by" gorm:"not null"`
+	SubmittedAt    time.Time  `json:"submitted_at" gorm:"not null"`
+	ReviewedBy     *int64     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	Comment        string     `json:"comment,omitempty" gorm:"size:1000"`
+}
+
+// BusinessVerificationReview - решение администратора по заявке
+type BusinessVerificationReview struct {
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+// BusinessVerificationReject - отказ в проверке или отзыв подтверждения
+type BusinessVerificationReject struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type BusinessDocumentKind string
+
+const (
+	// BusinessDocumentRegistration - лист записи ЕГРЮЛ/ЕГРИП или свидетельство о регистрации
+	BusinessDocumentRegistration BusinessDocumentKind = "registration"
+	BusinessDocumentCharter      BusinessDocumentKind = "charter"
+	// BusinessDocumentAuthority - приказ о назначении руководителя или доверенность
+	BusinessDocumentAuthority BusinessDocumentKind = "authority"
+	BusinessDocumentOther     BusinessDocumentKind = "other"
+)
+
+// BusinessDocument - документ, загруженный для проверки бизнеса
+type BusinessDocument struct {
+	ID          int64                `json:"id" gorm:"primaryKey;autoIncrement"`
+	BusinessID  int64                `json:"business_id" gorm:"not null;index"`
+	Kind        BusinessDocumentKind `json:"kind" gorm:"size:20;not null" swaggertype:"primitive,string"`
+	FileName    string               `json:"file_name" gorm:"size:255;not null"`
+	ContentType string               `json:"content_type" gorm:"size:100;not null"`
+	Size        int64                `json:"size" gorm:"not null"`
+	ObjectKey   string               `json:"-" gorm:"size:255;not null"`
+	UploadedBy  int64                `json:"uploaded_by" gorm:"not null"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// MemberRole - роль участника бизнеса
+type MemberRole string
+
+const (
+	// MemberOwner может все, в том числе удалить бизнес и передать владение
+	MemberOwner MemberRole = "owner"
+	// MemberManager ведет бизнес, товары и участников, кроме владельцев
+	MemberManager MemberRole = "manager"
+	// MemberContentEditor работает только с товарами
+	MemberContentEditor MemberRole = "content_editor"
+	// MemberAccountant видит выручку и выплаты
+	MemberAccountant MemberRole = "accountant"
+)
+
+// BusinessMember - участник бизнеса с ролью
+type BusinessMember struct {
+	UserID  int64      `json:"user_id"`
+	Name    string     `json:"name"`
+	Surname string     `json:"surname"`
+	Email   string     `json:"email"`
+	Role    MemberRole `json:"role" swaggertype:"primitive,string"`
+}
+
+type MemberRoleUpdate struct {
+	Role MemberRole `json:"role" binding:"required,oneof=owner manager content_editor accountant" swaggertype:"primitive,string"`
+}
+
+type OwnershipTransfer struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+type BusinessInvitationStatus string
+
+const (
+	InvitationPending  BusinessInvitationStatus = "pending"
+	InvitationAccepted BusinessInvitationStatus = "accepted"
+	InvitationDeclined BusinessInvitationStatus = "declined"
+	InvitationRevoked  BusinessInvitationStatus = "revoked"
+)
+
+// BusinessInvitation - приглашение в бизнес по почте. Принять его может только
+// пользователь с этой подтвержденной почтой по ссылке из письма.
+type BusinessInvitation struct {
+	ID          int64                    `json:"id" gorm:"primaryKey;autoIncrement"`
+	BusinessID  int64                    `json:"business_id" gorm:"not null;index"`
+	Email       string                   `json:"email" gorm:"size:320;not null;index"`
+	Role        MemberRole               `json:"role" gorm:"size:20;not null" swaggertype:"primitive,string"`
+	TokenHash   string                   `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	InvitedBy   int64                    `json:"invited_by" gorm:"not null"`
+	Status      BusinessInvitationStatus `json:"status" gorm:"size:20;not null" swaggertype:"primitive,string"`
+	ExpiresAt   time.Time                `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time                `json:"created_at"`
+	RespondedAt *time.Time               `json:"responded_at,omitempty"`
+}
+
+type BusinessInvitationCreate struct {
+	Email string     `json:"email" binding:"required,email,max=320"`
+	Role  MemberRole `json:"role" binding:"required,oneof=owner manager content_editor accountant" swaggertype:"primitive,string"`
+}
+
+type BusinessInvitationToken struct {
+	Token string `json:"token" binding:"required"`
+}
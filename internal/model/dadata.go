@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// DaDataParty - закешированный ответ DaData по организации или ИП.
+// Data хранит dadata.Party в JSON, чтобы кеш не зависел от схемы таблицы.
+type DaDataParty struct {
+	INN       string    `gorm:"primaryKey;size:12"`
+	Data      string    `gorm:"type:jsonb;not null"`
+	FetchedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+func (DaDataParty) TableName() string {
+	return "dadata_parties"
+}
@@ -0,0 +1,138 @@
+package model
+
+import "time"
+
+// LedgerAccount - счет двойной записи. Счета продавца ведутся отдельно по
+// каждому бизнесу, у счетов площадки BusinessID проводки указывает, к чьей
+// операции она относится.
+type LedgerAccount string
+
+const (
+	// LedgerCash - деньги площадки на счете ЮKassa
+	LedgerCash LedgerAccount = "cash"
+	// LedgerSellerPayable - долг площадки перед продавцом
+	LedgerSellerPayable LedgerAccount = "seller_payable"
+	// LedgerCommission - доход площадки от комиссий
+	LedgerCommission LedgerAccount = "commission"
+	// LedgerPayoutsInTransit - выплаты, отправленные в банк и еще не подтвержденные
+	LedgerPayoutsInTransit LedgerAccount = "payouts_in_transit"
+)
+
+type LedgerTransactionKind string
+
+const (
+	LedgerCapture          LedgerTransactionKind = "capture"
+	LedgerRefund           LedgerTransactionKind = "refund"
+	LedgerCommissionCharge LedgerTransactionKind = "commission"
+	LedgerCommissionRefund LedgerTransactionKind = "commission_refund"
+	LedgerPayout           LedgerTransactionKind = "payout"
+	LedgerPayoutSettled    LedgerTransactionKind = "payout_settled"
+	LedgerPayoutFailed     LedgerTransactionKind = "payout_failed"
+)
+
+// LedgerTransaction - хозяйственная операция по одному бизнесу. Сумма ее
+// проводок всегда равна нулю. Транзакции не меняются и не удаляются:
+// ошибка исправляется обратной транзакцией.
+type LedgerTransaction struct {
+	ID   int64                 `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind LedgerTransactionKind `json:"kind" gorm:"size:30;not null" swaggertype:"primitive,string"`
+	// Key - ключ идемпотентности: событие проводится один раз, сколько бы
+	// уведомлений о нем ни пришло
+	Key         string        `json:"-" gorm:"size:100;not null;uniqueIndex"`
+	BusinessID  int64         `json:"business_id" gorm:"not null;index"`
+	OrderID     *int64        `json:"order_id,omitempty" gorm:"index"`
+	RefundID    *string       `json:"refund_id,omitempty" gorm:"size:64"`
+	PayoutID    *int64        `json:"payout_id,omitempty" gorm:"index"`
+	Description string        `json:"description" gorm:"size:255;not null"`
+	CreatedAt   time.Time     `json:"created_at" gorm:"not null;index"`
+	Entries     []LedgerEntry `json:"entries" gorm:"foreignKey:TransactionID"`
+}
+
+// LedgerEntry - проводка. Amount в копейках: дебет положительный, кредит отрицательный.
+type LedgerEntry struct {
+	ID            int64         `json:"id" gorm:"primaryKey;autoIncrement"`
+	TransactionID int64         `json:"transaction_id" gorm:"not null;index"`
+	Account       LedgerAccount `json:"account" gorm:"size:30;not null;index:idx_ledger_entries_account" swaggertype:"primitive,string"`
+	BusinessID    int64         `json:"business_id" gorm:"not null;index:idx_ledger_entries_account"`
+	Amount        int64         `json:"amount" gorm:"not null"`
+	CreatedAt     time.Time     `json:"created_at" gorm:"not null;index"`
+}
+
+// NewLedgerTransfer - транзакция из двух проводок: amount копеек списывается
+// с кредитуемого счета credit на дебетуемый счет debit
+func NewLedgerTransfer(kind LedgerTransactionKind, key string, businessID int64, debit, credit LedgerAccount, amount int64) LedgerTransaction {
+	return LedgerTransaction{
+		Kind:       kind,
+		Key:        key,
+		BusinessID: businessID,
+		Entries: []LedgerEntry{
+			{Account: debit, BusinessID: businessID, Amount: amount},
+			{Account: credit, BusinessID: businessID, Amount: -amount},
+		},
+	}
+}
+
+// CommissionRule - комиссия площадки в процентах. Правило с бизнесом и
+// категорией важнее правила только для бизнеса, оно важнее правила только
+// для категории. Без подходящего правила действует ставка из конфигурации.
+type CommissionRule struct {
+	BaseModel
+	ID         int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	BusinessID *int64           `json:"business_id,omitempty" gorm:"index"`
+	Category   *ProductCategory `json:"category,omitempty" gorm:"size:50" swaggertype:"string"`
+	Rate       float64          `json:"rate" gorm:"not null"`
+}
+
+// CommissionRuleCreate - новое правило; правило для той же пары бизнес-категория заменяется
+type CommissionRuleCreate struct {
+	BusinessID *int64           `json:"business_id"`
+	Category   *ProductCategory `json:"category" swaggertype:"string"`
+	Rate       *float64         `json:"rate" binding:"required,gte=0,lte=100"`
+}
+
+// SellerBalance - расчеты площадки с продавцом, суммы в копейках
+type SellerBalance struct {
+	BusinessID int64 `json:"business_id"`
+	// Balance - сколько площадка должна продавцу
+	Balance int64 `json:"balance"`
+	// Available - часть Balance, которая попадет в ближайшую выплату:
+	// поступления удерживаются на срок возврата
+	Available int64 `json:"available"`
+	// InTransit - отправлено в банк и ждет подтверждения
+	InTransit int64  `json:"in_transit"`
+	Currency  string `json:"currency"`
+}
+
+// PlatformBalance - остатки счетов площадки в копейках для сверки с ЮKassa
+type PlatformBalance struct {
+	Cash          int64  `json:"cash"`
+	SellerPayable int64  `json:"seller_payable"`
+	Commission    int64  `json:"commission"`
+	InTransit     int64  `json:"in_transit"`
+	Currency      string `json:"currency"`
+}
+
+// StatementLine - операция выписки. Amount в копейках: плюс увеличивает долг
+// площадки перед продавцом, минус уменьшает.
+type StatementLine struct {
+	TransactionID int64                 `json:"transaction_id"`
+	Date          time.Time             `json:"date"`
+	Kind          LedgerTransactionKind `json:"kind" swaggertype:"primitive,string"`
+	OrderID       *int64                `json:"order_id,omitempty"`
+	PayoutID      *int64                `json:"payout_id,omitempty"`
+	Description   string                `json:"description"`
+	Amount        int64                 `json:"amount"`
+}
+
+// Statement - месячная выписка по расчетам с продавцом, суммы в копейках
+type Statement struct {
+	BusinessID     int64           `json:"business_id"`
+	Month          string          `json:"month" example:"2026-01"`
+	OpeningBalance int64           `json:"opening_balance"`
+	Sales          int64           `json:"sales"`
+	Refunds        int64           `json:"refunds"`
+	Commission     int64           `json:"commission"`
+	Payouts        int64           `json:"payouts"`
+	ClosingBalance int64           `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+}
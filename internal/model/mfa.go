@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// UserMFA - настройки TOTP пользователя. Секрет хранится в зашифрованном виде.
+type UserMFA struct {
+	UserID         int64      `json:"user_id" gorm:"primaryKey"`
+	SecretEnc      string     `json:"-" gorm:"not null"`
+	Enabled        bool       `json:"enabled" gorm:"not null;default:false"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep   int64      `json:"-" gorm:"not null;default:0"`
+	FailedAttempts int        `json:"-" gorm:"not null;default:0"`
+	LockedUntil    *time.Time `json:"-"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// RecoveryCode - одноразовый код восстановления доступа при потере устройства
+type RecoveryCode struct {
+	ID       int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID   int64      `json:"user_id" gorm:"not null;index"`
+	CodeHash string     `json:"-" gorm:"size:64;not null"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAEnrollment - данные для добавления аккаунта в приложение-аутентификатор
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCodePNG       []byte `json:"qr_png" swaggertype:"string" format:"base64"`
+}
+
+type MFACode struct {
+	Code string `json:"code" form:"code" binding:"required"`
+}
+
+// MFATokenRequest - mfa_token, выданный /user/token, когда подключение 2FA обязательно
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" form:"mfa_token" binding:"required"`
+}
+
+type MFALogin struct {
+	MFAToken string `json:"mfa_token" form:"mfa_token" binding:"required"`
+	// Code - код из приложения или код восстановления
+	Code string `json:"code" form:"code" binding:"required"`
+}
+
+// MFAConfirmed - результат подтверждения 2FA. Token заполнен, если подключение было частью входа.
+type MFAConfirmed struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	Token         *Token   `json:"token,omitempty"`
+}
+
+type MFAStatus struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
@@ -19,6 +19,34 @@ func RunMigrations(db *gorm.DB) error {
 		Order{},
 		OrderItem{},
 		UserToBusiness{},
+		Upload{},
+		RolePermission{},
+		KnownPermission{},
+		RefreshToken{},
+		Session{},
+		UserMFA{},
+		RecoveryCode{},
+		LoginAttempt{},
+		AuthEvent{},
+		EmailChange{},
+		PhoneCode{},
+		UserIdentity{},
+		OAuthState{},
+		DataExport{},
+		UserAddress{},
+		DaDataParty{},
+		BusinessVerification{},
+		BusinessDocument{},
+		BusinessInvitation{},
+		OrderRefund{},
+		ProductEvent{},
+		ProductDailyStats{},
+		BusinessDailyStats{},
+		LedgerTransaction{},
+		LedgerEntry{},
+		CommissionRule{},
+		PayoutBatch{},
+		Payout{},
 	}
 
 	for _, m := range models {
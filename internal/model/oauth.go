@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// UserIdentity - учетная запись у внешнего провайдера, привязанная к пользователю
+type UserIdentity struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      int64      `json:"-" gorm:"not null;uniqueIndex:idx_user_identities_user_provider"`
+	Provider    string     `json:"provider" gorm:"size:32;not null;uniqueIndex:idx_user_identities_user_provider;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject     string     `json:"-" gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OAuthState - одноразовый state запроса авторизации с code_verifier для PKCE.
+// UserID задан, если пользователь привязывает провайдера из профиля.
+type OAuthState struct {
+	StateHash    string    `gorm:"primaryKey;size:64"`
+	Provider     string    `gorm:"size:32;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	UserID       *int64    `gorm:"index"`
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
+
+type OAuthAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OAuthCallback struct {
+	Code  string `json:"code" form:"code" binding:"required"`
+	State string `json:"state" form:"state" binding:"required"`
+	// DeviceID - параметр device_id из redirect_uri VK ID
+	DeviceID string `json:"device_id,omitempty" form:"device_id"`
+}
+
+type OAuthProviders struct {
+	Providers []string `json:"providers"`
+}
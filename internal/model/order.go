@@ -1,12 +1,21 @@
 package model
 
+import "time"
+
 type Order struct {
 	BaseModel
 	ID             int64           `json:"id" gorm:"primaryKey;autoIncrement"`
 	UserID         int64           `json:"user_id" gorm:"not null"`
 	Status         OrderStatusType `json:"status" gorm:"not null;default:created" swaggertype:"primitive,string"`
 	PaymentConfirm bool            `json:"payment_confirm" gorm:"not null;default:false"`
-	Address        string          `json:"address" gorm:"not null"`
+	// PaymentID - id платежа ЮKassa, подтвердившего оплату. Заказы, оплаченные
+	// без ЮKassa, в расчеты с продавцами не попадают.
+	PaymentID *string `json:"payment_id,omitempty" gorm:"size:64;uniqueIndex"`
+	Address   string  `json:"address" gorm:"not null"` // адрес одной строкой
+	// AddressID и Delivery задаются при оформлении по сохраненному адресу. Delivery -
+	// копия адреса на момент заказа, правка адреса в профиле заказ не меняет.
+	AddressID *int64        `json:"address_id,omitempty"`
+	Delivery  AddressFields `json:"delivery" gorm:"embedded;embeddedPrefix:delivery_"`
 }
 
 type OrderStatusType string
@@ -37,3 +46,16 @@ type OrderItemResponse struct {
 	Order
 	OrderItems []ExtendedOrderItem `json:"order_items"`
 }
+
+// OrderRefund - возврат по заказу, подтвержденный ЮKassa. Возврат может быть
+// частичным; между продавцами заказа он делится пропорционально их позициям.
+type OrderRefund struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:64"` // id возврата в ЮKassa
+	OrderID   int64     `json:"order_id" gorm:"not null;index"`
+	Amount    float64   `json:"amount" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+func (OrderRefund) TableName() string {
+	return "order_refunds"
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+type PayoutStatus string
+
+const (
+	PayoutPending PayoutStatus = "pending"
+	PayoutPaid    PayoutStatus = "paid"
+	PayoutFailed  PayoutStatus = "failed"
+)
+
+// PayoutBatch - реестр выплат, который бухгалтерия отправляет в банк
+type PayoutBatch struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Count     int       `json:"count" gorm:"not null"`
+	Total     int64     `json:"total" gorm:"not null"` // копейки
+	CreatedAt time.Time `json:"created_at" gorm:"not null;index"`
+	Payouts   []Payout  `json:"payouts,omitempty" gorm:"foreignKey:BatchID"`
+}
+
+// Payout - выплата продавцу. При создании сумма переходит с баланса продавца
+// в выплаты в пути, после ответа банка - в списание с расчетного счета или
+// обратно на баланс.
+type Payout struct {
+	BaseModel
+	ID            int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	BatchID       int64        `json:"batch_id" gorm:"not null;index"`
+	BusinessID    int64        `json:"business_id" gorm:"not null;index"`
+	Amount        int64        `json:"amount" gorm:"not null"` // копейки
+	Status        PayoutStatus `json:"status" gorm:"size:20;not null;default:pending;index" swaggertype:"primitive,string"`
+	FailureReason string       `json:"failure_reason,omitempty" gorm:"size:1000"`
+	SettledAt     *time.Time   `json:"settled_at,omitempty"`
+}
+
+// PayoutFailure - банк не провел выплату
+type PayoutFailure struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
@@ -0,0 +1,87 @@
+package model
+
+// Permission - именованное право на действие в системе
+type Permission string
+
+const (
+	PermUserRead        Permission = "user.read"
+	PermUserDelete      Permission = "user.delete"
+	PermRoleManage      Permission = "role.manage"
+	PermProductModerate Permission = "product.moderate"
+	PermOrderRead       Permission = "order.read"
+	PermOrderRefund     Permission = "order.refund"
+	PermBusinessRead    Permission = "business.read"
+	PermBusinessManage  Permission = "business.manage"
+	PermBusinessVerify  Permission = "business.verify"
+	// PermFinanceManage - комиссии, реестры выплат и сверка расчетов с продавцами
+	PermFinanceManage Permission = "finance.manage"
+)
+
+// AllPermissions - все права, известные приложению
+var AllPermissions = []Permission{
+	PermUserRead,
+	PermUserDelete,
+	PermRoleManage,
+	PermProductModerate,
+	PermOrderRead,
+	PermOrderRefund,
+	PermBusinessRead,
+	PermBusinessManage,
+	PermBusinessVerify,
+	PermFinanceManage,
+}
+
+// AllRoles - роли пользователей, для которых можно назначать права
+var AllRoles = []UserRoleType{
+	UserRole,
+	SelfEmployedRole,
+	BusinessRole,
+	AdminRole,
+	SupportRole,
+}
+
+// DefaultRolePermissions - права ролей, которые создаются при первом запуске
+var DefaultRolePermissions = map[UserRoleType][]Permission{
+	AdminRole: AllPermissions,
+	SupportRole: {
+		PermUserRead,
+		PermOrderRead,
+		PermBusinessRead,
+		PermProductModerate,
+	},
+}
+
+// RolePermission связывает роль с правом
+type RolePermission struct {
+	Role       UserRoleType `json:"role" gorm:"primaryKey;size:50" swaggertype:"primitive,string"`
+	Permission Permission   `json:"permission" gorm:"primaryKey;size:100" swaggertype:"primitive,string"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// KnownPermission - право, которое уже раздавалось ролям по умолчанию.
+// Новое право из AllPermissions выдается при запуске один раз, поэтому
+// право, снятое с роли вручную, после перезапуска не возвращается.
+type KnownPermission struct {
+	Permission Permission `gorm:"primaryKey;size:100"`
+}
+
+func (KnownPermission) TableName() string {
+	return "known_permissions"
+}
+
+// RolePermissions - роль и все ее права
+type RolePermissions struct {
+	Role        UserRoleType `json:"role" swaggertype:"primitive,string"`
+	Permissions []Permission `json:"permissions" swaggertype:"array,string"`
+}
+
+type RolePermissionsUpdate struct {
+	Permissions []Permission `json:"permissions" binding:"required" swaggertype:"array,string"`
+}
+
+type UserRoleUpdate struct {
+	Role UserRoleType `json:"role" binding:"required" swaggertype:"primitive,string"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type PhoneCodePurpose string
+
+const (
+	PhoneCodeVerify PhoneCodePurpose = "verify"
+	PhoneCodeLogin  PhoneCodePurpose = "login"
+)
+
+// PhoneCode - одноразовый код из SMS. Для подтверждения номера хранит
+// пользователя, к которому номер будет привязан.
+type PhoneCode struct {
+	ID        int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Phone     string           `json:"phone" gorm:"size:16;not null;index:idx_phone_codes_phone_purpose"`
+	Purpose   PhoneCodePurpose `json:"purpose" gorm:"size:16;not null;index:idx_phone_codes_phone_purpose"`
+	UserID    *int64           `json:"user_id,omitempty" gorm:"index"`
+	CodeHash  string           `json:"-" gorm:"size:64;not null"`
+	Attempts  int              `json:"-" gorm:"not null;default:0"`
+	SentAt    time.Time        `json:"sent_at" gorm:"autoCreateTime"`
+	ExpiresAt time.Time        `json:"expires_at" gorm:"index"`
+}
+
+func (PhoneCode) TableName() string {
+	return "phone_codes"
+}
+
+type PhoneRequest struct {
+	Phone string `json:"phone" form:"phone" binding:"required" example:"+79991234567"`
+}
+
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" form:"phone" binding:"required" example:"+79991234567"`
+	Code  string `json:"code" form:"code" binding:"required"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportReady      DataExportStatus = "ready"
+	DataExportFailed     DataExportStatus = "failed"
+)
+
+// DataExport - выгрузка персональных данных пользователя в ZIP-архив
+type DataExport struct {
+	ID          int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      int64            `json:"-" gorm:"not null;index"`
+	Status      DataExportStatus `json:"status" gorm:"size:16;not null;index" swaggertype:"primitive,string"`
+	ObjectKey   string           `json:"-"`
+	Size        int64            `json:"size,omitempty"`
+	Error       string           `json:"-"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	StartedAt   *time.Time       `json:"-"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	// ExpiresAt - после этого архив удаляется из хранилища
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+type AccountDeletion struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// Имя, которое остается у обезличенного пользователя и в его отзывах
+const (
+	AnonymizedName    = "Удаленный"
+	AnonymizedSurname = "пользователь"
+)
@@ -61,6 +61,12 @@ func (ProductReview) TableName() string {
 	return "product_reviews"
 }
 
+// ProductReviewRequest - отзыв от покупателя. Автор берется из токена.
+type ProductReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"required"`
+}
+
 type ReviewImages struct {
 	BaseModel
 	ID        int64  `json:"id" gorm:"primaryKey;autoIncrement"`
@@ -80,6 +86,12 @@ type ProductImage struct {
 	FileUUID  string `json:"file_uuid" gorm:"not null"`
 	URL       string `json:"url" gorm:"null"` // Не хранится в базе данных напрямую
 	IsPrimary bool   `json:"is_primary" gorm:"default:false"`
+	Position  int    `json:"position" gorm:"not null;default:0"`
+}
+
+// ProductImagesReorderRequest задает новый порядок изображений продукта
+type ProductImagesReorderRequest struct {
+	ImageIDs []int64 `json:"image_ids" binding:"required,min=1"`
 }
 
 func (ProductImage) TableName() string {
@@ -105,6 +117,11 @@ const StatusConsideration ProductStatus = "consideration"
 const StatusReject ProductStatus = "reject"
 const StatusApprove ProductStatus = "approve"
 
+// ProductStatusUpdate - запрос модератора на смену статуса товара
+type ProductStatusUpdate struct {
+	Status ProductStatus `json:"status" binding:"required,oneof=consideration reject approve" swaggertype:"primitive,string"`
+}
+
 // Product представляет товар
 type Product struct {
 	BaseModel
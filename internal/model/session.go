@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Session - вход пользователя с конкретного устройства.
+// ID совпадает с FamilyID refresh-токенов этого входа.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`
+	DeviceHash string     `json:"-" gorm:"size:64;not null;index"`
+	UserAgent  string     `json:"user_agent" gorm:"size:512"`
+	IP         string     `json:"ip" gorm:"size:45"`
+	Location   string     `json:"location"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+	Current    bool       `json:"current" gorm:"-"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// SessionMeta - сведения о клиенте, полученные из запроса
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// StorefrontMedia - изображение оформления витрины
+type StorefrontMedia string
+
+const (
+	StorefrontLogo   StorefrontMedia = "logo"
+	StorefrontBanner StorefrontMedia = "banner"
+)
+
+// Storefront - публичная страница продавца
+type Storefront struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Logo        string `json:"logo,omitempty"`
+	Banner      string `json:"banner,omitempty"`
+	// Rating - средняя оценка по отзывам на опубликованные товары
+	Rating       float64         `json:"rating"`
+	ReviewCount  int64           `json:"review_count"`
+	ProductCount int64           `json:"product_count"`
+	Legal        StorefrontLegal `json:"legal"`
+	VerifiedAt   *time.Time      `json:"verified_at,omitempty"`
+}
+
+// StorefrontLegal - сведения о продавце, которые по правилам дистанционной
+// торговли должны быть доступны покупателю: наименование (ФИО для ИП), адрес,
+// ИНН и ОГРН. Остальные данные бизнеса на витрину не попадают.
+type StorefrontLegal struct {
+	Name    string `json:"name"`
+	INN     string `json:"inn"`
+	OGRN    string `json:"ogrn,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// StorefrontUpdate - изменение витрины. Пустая строка очищает поле.
+type StorefrontUpdate struct {
+	Slug        *string `json:"slug" binding:"omitempty,max=63"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+}
+
+// ProductPage - страница списка товаров
+type ProductPage struct {
+	Items   []Product `json:"items"`
+	Total   int64     `json:"total"`
+	Page    int       `json:"page"`
+	PerPage int       `json:"per_page"`
+}
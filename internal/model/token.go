@@ -1,7 +1,32 @@
 package model
 
+import "time"
+
 type Token struct {
-	RefreshToken string `json:"refresh_token"`
-	AccessToken  string `json:"access_token"`
-	TokenType    string `json:"token_type" example:"bearer"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty" example:"bearer"`
+	// MFARequired - пароль верный, но нужен код 2FA: его отправляют в /user/token/mfa вместе с MFAToken
+	MFARequired bool `json:"mfa_required,omitempty"`
+	// MFAEnrollmentRequired - роль обязана использовать 2FA: вход завершается подключением 2FA с MFAToken
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
+}
+
+// RefreshToken - выданный refresh-токен. Хранится только хеш самого токена.
+// Токены одного входа образуют семейство: при обновлении старый токен помечается
+// использованным и выдается новый с тем же FamilyID.
+type RefreshToken struct {
+	BaseModel
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID    int64      `json:"user_id" gorm:"not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
 }
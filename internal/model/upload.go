@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+type UploadTarget string
+
+const (
+	UploadTargetProduct UploadTarget = "product"
+	UploadTargetReview  UploadTarget = "review"
+	// Логотип и баннер витрины бизнеса хранятся в бакете товаров
+	UploadTargetBusinessLogo   UploadTarget = "business_logo"
+	UploadTargetBusinessBanner UploadTarget = "business_banner"
+)
+
+type UploadStatus string
+
+const (
+	UploadStatusPending UploadStatus = "pending"
+	// UploadStatusProcessing - файл проверяется и обрабатывается, повторное подтверждение отклоняется
+	UploadStatusProcessing UploadStatus = "processing"
+	UploadStatusConfirmed  UploadStatus = "confirmed"
+)
+
+// Upload - загрузка файла напрямую в хранилище по presigned-ссылке
+type Upload struct {
+	BaseModel
+	ID          string       `json:"id" gorm:"primaryKey;size:36"`
+	UserID      int64        `json:"user_id" gorm:"not null;index"`
+	Target      UploadTarget `json:"target" gorm:"size:20;not null" swaggertype:"primitive,string"`
+	Key         string       `json:"key" gorm:"not null"`
+	ContentType string       `json:"content_type" gorm:"size:100;not null"`
+	MaxSize     int64        `json:"max_size" gorm:"not null"`
+	Status      UploadStatus `json:"status" gorm:"size:20;not null;default:pending;index" swaggertype:"primitive,string"`
+	ExpiresAt   time.Time    `json:"expires_at" gorm:"not null;index"`
+}
+
+func (Upload) TableName() string {
+	return "uploads"
+}
+
+type UploadCreate struct {
+	Target      UploadTarget `json:"target" binding:"required,oneof=product review business_logo business_banner" swaggertype:"primitive,string"`
+	ContentType string       `json:"content_type" binding:"required"`
+	Size        int64        `json:"size" binding:"required,gt=0"`
+}
+
+type UploadTicket struct {
+	UploadID  string            `json:"upload_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method" example:"PUT"`
+	Headers   map[string]string `json:"headers"`
+	MaxSize   int64             `json:"max_size"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type UploadConfirm struct {
+	ProductID  int64 `json:"product_id"`
+	ReviewID   int64 `json:"review_id"`
+	BusinessID int64 `json:"business_id"`
+	IsPrimary  bool  `json:"is_primary"`
+}
+
+type UploadConfirmed struct {
+	Upload       Upload        `json:"upload"`
+	ProductImage *ProductImage `json:"product_image,omitempty"`
+	ReviewImage  *ReviewImages `json:"review_image,omitempty"`
+	Business     *Business     `json:"business,omitempty"`
+}
@@ -13,9 +13,14 @@ type User struct {
 	PasswordHash      string       `json:"-" gorm:"not null"` // "-" исключает поле из JSON
 	DateOfBirth       time.Time    `json:"date_of_birth" gorm:"null"`
 	IsEmailVerified   bool         `json:"is_email_verified" gorm:"default:false"`
+	Phone             *string      `json:"phone,omitempty" gorm:"size:16;unique"` // E.164, только подтвержденный
+	IsPhoneVerified   bool         `json:"is_phone_verified" gorm:"default:false"`
 	Role              UserRoleType `json:"role" gorm:"default:user" swaggertype:"primitive,string"`
 	IsPasportVerified bool         `json:"is_pasport_verified" gorm:"default:false"`
 	INN               *int64       `json:"inn,omitempty" gorm:"unique,null"`
+	// DeletionScheduledAt - когда аккаунт будет обезличен; до этого удаление можно отменить
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" gorm:"index"`
+	AnonymizedAt        *time.Time `json:"anonymized_at,omitempty"`
 }
 
 type UserRoleType string
@@ -26,9 +31,12 @@ const BusinessRole UserRoleType = "business"
 const AdminRole UserRoleType = "admin"
 const SupportRole UserRoleType = "support"
 
+// UserToBusiness - участие пользователя в бизнесе. Участия, созданные до
+// появления ролей, получают роль owner, чтобы никто не потерял доступ.
 type UserToBusiness struct {
-	UserID     int64 `json:"user_id" gorm:"not null"`
-	BusinessID int64 `json:"business_id" gorm:"not null"`
+	UserID     int64      `json:"user_id" gorm:"not null"`
+	BusinessID int64      `json:"business_id" gorm:"not null"`
+	Role       MemberRole `json:"role" gorm:"size:20;not null;default:owner" swaggertype:"primitive,string"`
 }
 
 // TableName переопределяет название таблицы (если нужно)
@@ -42,7 +50,55 @@ type UserCreate struct {
 	Surname           string    `json:"surname" `
 	Email             string    `json:"email" `
 	Password          string    `json:"password" `
+	Phone             string    `json:"phone,omitempty" ` // на номер придет код для POST /user/phone/verify
 	DateOfBirth       time.Time `json:"date_of_birth" `
 	IsPasportVerified bool      `json:"is_pasport_verified" `
 	INN               *int64    `json:"inn,omitempty" `
 }
+
+// UserUpdate - поля профиля, которые пользователь может менять сам.
+// Почта меняется отдельным запросом, роль и статусы проверок - только администрацией.
+type UserUpdate struct {
+	Name        *string    `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Patronymic  *string    `json:"patronymic,omitempty" binding:"omitempty,max=100"`
+	Surname     *string    `json:"surname,omitempty" binding:"omitempty,min=1,max=100"`
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
+	// INN можно указать, пока паспорт не подтвержден
+	INN *int64 `json:"inn,omitempty"`
+}
+
+// EmailChangeRequest - запрос на смену почты. Password обязателен, если он
+// задан; пользователь без пароля должен перед сменой заново войти.
+type EmailChangeRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password"`
+}
+
+type EmailChangeConfirm struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type EmailChangeRevert struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EmailChange - запрос и история смены почты. Почта меняется только после
+// ввода кода, отправленного на новый адрес (ConfirmedAt). По ссылке из письма
+// на старый адрес смену можно отменить в течение RevertExpiresAt.
+type EmailChange struct {
+	ID               int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID           int64     `json:"user_id" gorm:"not null;index"`
+	OldEmail         string    `json:"old_email" gorm:"not null"`
+	NewEmail         string    `json:"new_email" gorm:"not null"`
+	OldEmailVerified bool      `json:"-" gorm:"not null"`
+	Code             string    `json:"-" gorm:"size:10"`
+	CodeExpiresAt    time.Time `json:"-"`
+	// CodeAttempts - число неверных попыток ввода кода
+	CodeAttempts int        `json:"-" gorm:"not null;default:0"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	// RevertTokenHash заменяется при подтверждении, тогда же отправляется ссылка отмены
+	RevertTokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	RevertExpiresAt time.Time  `json:"revert_expires_at" gorm:"not null"`
+	RevertedAt      *time.Time `json:"reverted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
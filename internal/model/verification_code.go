@@ -11,6 +11,8 @@ type VerificationCode struct {
 	Code      string    `json:"code" gorm:"size:10;not null"`
 	SentAt    time.Time `json:"sent_at" gorm:"autoCreateTime"`
 	ExpiredAt time.Time `json:"expired_at"`
+	// Attempts - число неверных попыток ввода; после предела код удаляется
+	Attempts int `json:"-" gorm:"not null;default:0"`
 }
 
 // TableName - .
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ClientConfig - параметры приложения, зарегистрированного у провайдера
+type ClientConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	// Ошибка по RFC 6749 или в формате провайдера
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func authCodeURL(c ClientConfig, state string, codeChallenge string, extra url.Values) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {c.RedirectURL},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	if len(c.Scopes) > 0 {
+		q.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	for k, v := range extra {
+		q[k] = v
+	}
+
+	sep := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.AuthURL + sep + q.Encode()
+}
+
+// exchange обменивает код авторизации на токен доступа
+func exchange(ctx context.Context, client *http.Client, c ClientConfig, code string, codeVerifier string, extra url.Values) (tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.RedirectURL},
+		"client_id":     {c.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	for k, v := range extra {
+		form[k] = v
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	status, err := doJSON(client, req, &token)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("%w: %s", ErrExchangeFailed, err)
+	}
+	if token.Error != "" {
+		return tokenResponse{}, fmt.Errorf("%w: %s %s", ErrExchangeFailed, token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK || token.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("%w: unexpected status %d", ErrExchangeFailed, status)
+	}
+	return token, nil
+}
+
+// doJSON выполняет запрос и разбирает JSON-ответ независимо от статуса:
+// провайдеры возвращают описание ошибки в теле
+func doJSON(client *http.Client, req *http.Request, v any) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return resp.StatusCode, fmt.Errorf("status %d: decode response: %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
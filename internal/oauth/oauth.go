@@ -0,0 +1,128 @@
+// Package oauth implements OAuth2 authorization code flow with PKCE against
+// external identity providers (VK ID, Yandex ID, generic OpenID Connect).
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown oauth provider")
+	ErrExchangeFailed  = errors.New("oauth code exchange failed")
+)
+
+const (
+	ProviderVKID   = "vkid"
+	ProviderYandex = "yandex"
+)
+
+// Identity - учетная запись пользователя у провайдера
+type Identity struct {
+	Provider string
+	// Subject - постоянный идентификатор пользователя у провайдера
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Surname       string
+}
+
+// Callback - параметры, с которыми провайдер вернул пользователя на redirect_uri
+type Callback struct {
+	Code  string
+	State string
+	// DeviceID передает только VK ID, он нужен для обмена кода
+	DeviceID string
+}
+
+// Provider выполняет вход через внешний сервис
+type Provider interface {
+	Name() string
+	// AuthCodeURL возвращает адрес страницы входа у провайдера
+	AuthCodeURL(state string, codeChallenge string) string
+	// Identity обменивает код на токен и получает данные пользователя
+	Identity(ctx context.Context, cb Callback, codeVerifier string) (Identity, error)
+}
+
+// Registry - включенные провайдеры по имени
+type Registry struct {
+	providers map[string]Provider
+}
+
+// New создает провайдеров, для которых задан client_id.
+// Для OIDC при запуске загружается документ discovery издателя.
+func New(ctx context.Context, cfg config.OAuthConfig, frontendURL string) (*Registry, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	redirectBase := strings.TrimSuffix(cfg.RedirectURL, "/")
+	if redirectBase == "" {
+		redirectBase = strings.TrimSuffix(frontendURL, "/") + "/oauth/callback"
+	}
+	redirect := func(name string) string { return redirectBase + "/" + name }
+
+	r := &Registry{providers: make(map[string]Provider)}
+	if cfg.VKIDClientID != "" {
+		r.providers[ProviderVKID] = NewVKID(client, cfg.VKIDClientID, cfg.VKIDClientSecret, redirect(ProviderVKID))
+	}
+	if cfg.YandexClientID != "" {
+		r.providers[ProviderYandex] = NewYandex(client, cfg.YandexClientID, cfg.YandexClientSecret, redirect(ProviderYandex))
+	}
+	if cfg.OIDCIssuer != "" {
+		if _, ok := r.providers[cfg.OIDCName]; ok {
+			return nil, fmt.Errorf("oauth provider %q is already configured", cfg.OIDCName)
+		}
+		p, err := DiscoverOIDC(ctx, client, cfg.OIDCName, cfg.OIDCIssuer, ClientConfig{
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  redirect(cfg.OIDCName),
+			Scopes:       cfg.OIDCScopes,
+		}, cfg.OIDCTrustEmail)
+		if err != nil {
+			return nil, err
+		}
+		r.providers[cfg.OIDCName] = p
+	}
+	return r, nil
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// Names возвращает имена включенных провайдеров по алфавиту
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCodeVerifier создает code_verifier для PKCE (RFC 7636)
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge вычисляет code_challenge методом S256
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
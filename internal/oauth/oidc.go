@@ -0,0 +1,118 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OIDC - провайдер OpenID Connect, настроенный по документу discovery.
+// Данные пользователя берутся из userinfo, поэтому подпись id_token не проверяется.
+type OIDC struct {
+	name       string
+	client     *http.Client
+	cfg        ClientConfig
+	trustEmail bool
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// DiscoverOIDC загружает <issuer>/.well-known/openid-configuration
+func DiscoverOIDC(ctx context.Context, client *http.Client, name string, issuer string, cfg ClientConfig, trustEmail bool) (*OIDC, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc oidcDiscovery
+	status, err := doJSON(client, req, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery %s: %w", issuer, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery %s: unexpected status %d", issuer, status)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery %s: issuer mismatch %q", issuer, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery %s: missing endpoints", issuer)
+	}
+
+	cfg.AuthURL = doc.AuthorizationEndpoint
+	cfg.TokenURL = doc.TokenEndpoint
+	cfg.UserInfoURL = doc.UserinfoEndpoint
+	return &OIDC{name: name, client: client, cfg: cfg, trustEmail: trustEmail}, nil
+}
+
+func (p *OIDC) Name() string {
+	return p.name
+}
+
+func (p *OIDC) AuthCodeURL(state string, codeChallenge string) string {
+	return authCodeURL(p.cfg, state, codeChallenge, nil)
+}
+
+// flexBool принимает true и "true": некоторые провайдеры отдают email_verified строкой
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	}
+	return nil
+}
+
+type oidcUserInfo struct {
+	Subject       string   `json:"sub"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+func (p *OIDC) Identity(ctx context.Context, cb Callback, codeVerifier string) (Identity, error) {
+	token, err := exchange(ctx, p.client, p.cfg, cb.Code, codeVerifier, nil)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoURL, nil)
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	var info oidcUserInfo
+	status, err := doJSON(p.client, req, &info)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s user info: %w", p.name, err)
+	}
+	if status != http.StatusOK || info.Subject == "" {
+		return Identity{}, fmt.Errorf("%s user info: unexpected status %d", p.name, status)
+	}
+
+	return Identity{
+		Provider:      p.name,
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.Email != "" && (bool(info.EmailVerified) || p.trustEmail),
+		Name:          info.GivenName,
+		Surname:       info.FamilyName,
+	}, nil
+}
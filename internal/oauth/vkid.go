@@ -0,0 +1,85 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VKID - вход через VK ID (id.vk.com). VK возвращает на redirect_uri
+// дополнительный device_id, без которого код не обменять.
+type VKID struct {
+	client *http.Client
+	cfg    ClientConfig
+}
+
+func NewVKID(client *http.Client, clientID string, clientSecret string, redirectURL string) *VKID {
+	return &VKID{client: client, cfg: ClientConfig{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"vkid.personal_info", "email"},
+		AuthURL:      "https://id.vk.com/authorize",
+		TokenURL:     "https://id.vk.com/oauth2/auth",
+		UserInfoURL:  "https://id.vk.com/oauth2/user_info",
+	}}
+}
+
+func (p *VKID) Name() string {
+	return ProviderVKID
+}
+
+func (p *VKID) AuthCodeURL(state string, codeChallenge string) string {
+	return authCodeURL(p.cfg, state, codeChallenge, nil)
+}
+
+type vkidUserInfo struct {
+	User struct {
+		UserID    string `json:"user_id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	} `json:"user"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (p *VKID) Identity(ctx context.Context, cb Callback, codeVerifier string) (Identity, error) {
+	if cb.DeviceID == "" {
+		return Identity{}, fmt.Errorf("%w: device_id is required", ErrExchangeFailed)
+	}
+	token, err := exchange(ctx, p.client, p.cfg, cb.Code, codeVerifier, url.Values{
+		"device_id": {cb.DeviceID},
+		"state":     {cb.State},
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+
+	form := url.Values{"client_id": {p.cfg.ClientID}, "access_token": {token.AccessToken}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.UserInfoURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var info vkidUserInfo
+	if _, err := doJSON(p.client, req, &info); err != nil {
+		return Identity{}, fmt.Errorf("vkid user info: %w", err)
+	}
+	if info.Error != "" || info.User.UserID == "" {
+		return Identity{}, fmt.Errorf("vkid user info: %s %s", info.Error, info.ErrorDescription)
+	}
+
+	// VK ID отдает только подтвержденную почту аккаунта
+	return Identity{
+		Provider:      ProviderVKID,
+		Subject:       info.User.UserID,
+		Email:         info.User.Email,
+		EmailVerified: info.User.Email != "",
+		Name:          info.User.FirstName,
+		Surname:       info.User.LastName,
+	}, nil
+}
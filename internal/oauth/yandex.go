@@ -0,0 +1,72 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Yandex - вход через Яндекс ID (oauth.yandex.ru)
+type Yandex struct {
+	client *http.Client
+	cfg    ClientConfig
+}
+
+func NewYandex(client *http.Client, clientID string, clientSecret string, redirectURL string) *Yandex {
+	return &Yandex{client: client, cfg: ClientConfig{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"login:email", "login:info"},
+		AuthURL:      "https://oauth.yandex.ru/authorize",
+		TokenURL:     "https://oauth.yandex.ru/token",
+		UserInfoURL:  "https://login.yandex.ru/info?format=json",
+	}}
+}
+
+func (p *Yandex) Name() string {
+	return ProviderYandex
+}
+
+func (p *Yandex) AuthCodeURL(state string, codeChallenge string) string {
+	return authCodeURL(p.cfg, state, codeChallenge, nil)
+}
+
+type yandexUserInfo struct {
+	ID           string `json:"id"`
+	DefaultEmail string `json:"default_email"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+func (p *Yandex) Identity(ctx context.Context, cb Callback, codeVerifier string) (Identity, error) {
+	token, err := exchange(ctx, p.client, p.cfg, cb.Code, codeVerifier, nil)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoURL, nil)
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Authorization", "OAuth "+token.AccessToken)
+
+	var info yandexUserInfo
+	status, err := doJSON(p.client, req, &info)
+	if err != nil {
+		return Identity{}, fmt.Errorf("yandex user info: %w", err)
+	}
+	if status != http.StatusOK || info.ID == "" {
+		return Identity{}, fmt.Errorf("yandex user info: unexpected status %d", status)
+	}
+
+	// Адреса в Яндекс ID подтверждаются при добавлении
+	return Identity{
+		Provider:      ProviderYandex,
+		Subject:       info.ID,
+		Email:         info.DefaultEmail,
+		EmailVerified: info.DefaultEmail != "",
+		Name:          info.FirstName,
+		Surname:       info.LastName,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// common.txt - самые распространенные пароли из публичных утечек,
+// по одному в строке, в нижнем регистре
+//
+//go:embed common.txt
+var commonList string
+
+var common = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonList, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+}()
+
+func isCommon(lower string) bool {
+	_, ok := common[lower]
+	return ok
+}
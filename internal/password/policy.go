@@ -0,0 +1,174 @@
+// Package password checks new passwords against the configured policy and
+// hashes them with argon2id, keeping legacy bcrypt hashes verifiable.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+var ErrWeakPassword = errors.New("password does not meet the policy")
+
+// Коды нарушений политики; по ним фронтенд показывает подсказки
+const (
+	CodeTooShort         = "too_short"
+	CodeTooLong          = "too_long"
+	CodeMissingLower     = "missing_lower"
+	CodeMissingUpper     = "missing_upper"
+	CodeMissingDigit     = "missing_digit"
+	CodeMissingSymbol    = "missing_symbol"
+	CodeCommon           = "common"
+	CodeContainsPersonal = "contains_personal"
+)
+
+// personalMinLength - более короткие части имени и почты не проверяются,
+// иначе под запрет попадут случайные совпадения вроде "ан" или "ив"
+const personalMinLength = 3
+
+type Violation struct {
+	Code    string `json:"code" example:"too_short"`
+	Message string `json:"message" example:"password must be at least 8 characters long"`
+}
+
+// ValidationError перечисляет все нарушения политики сразу,
+// чтобы пользователь исправил пароль за одну попытку
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrWeakPassword, strings.Join(messages, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// Policy - требования к новому паролю
+type Policy struct {
+	MinLength      int  `json:"min_length"`
+	MaxLength      int  `json:"max_length"`
+	RequireLower   bool `json:"require_lower"`
+	RequireUpper   bool `json:"require_upper"`
+	RequireDigit   bool `json:"require_digit"`
+	RequireSymbol  bool `json:"require_symbol"`
+	RejectCommon   bool `json:"reject_common"`
+	RejectPersonal bool `json:"reject_personal"`
+}
+
+func NewPolicy(cfg config.PasswordConfig) *Policy {
+	return &Policy{
+		MinLength:      cfg.MinLength,
+		MaxLength:      cfg.MaxLength,
+		RequireLower:   cfg.RequireLower,
+		RequireUpper:   cfg.RequireUpper,
+		RequireDigit:   cfg.RequireDigit,
+		RequireSymbol:  cfg.RequireSymbol,
+		RejectCommon:   cfg.RejectCommon,
+		RejectPersonal: cfg.RejectPersonal,
+	}
+}
+
+// Validate проверяет пароль. personal - почта, имя и другие данные владельца,
+// которые не должны входить в пароль. Возвращает *ValidationError.
+func (p *Policy) Validate(password string, personal ...string) error {
+	var violations []Violation
+	add := func(code, format string, args ...any) {
+		violations = append(violations, Violation{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		add(CodeTooShort, "password must be at least %d characters long", p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(CodeTooLong, "password must be at most %d characters long", p.MaxLength)
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireLower && !hasLower {
+		add(CodeMissingLower, "password must contain a lowercase letter")
+	}
+	if p.RequireUpper && !hasUpper {
+		add(CodeMissingUpper, "password must contain an uppercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(CodeMissingDigit, "password must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(CodeMissingSymbol, "password must contain a special character")
+	}
+
+	lower := strings.ToLower(password)
+	if p.RejectCommon && isCommon(lower) {
+		add(CodeCommon, "password is too common")
+	}
+	if p.RejectPersonal && containsPersonal(lower, personal) {
+		add(CodeContainsPersonal, "password must not contain your name or email")
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func containsPersonal(lower string, personal []string) bool {
+	for _, value := range personal {
+		for _, part := range personalParts(value) {
+			if strings.Contains(lower, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// personalParts разбивает значение на слова: для почты проверяется и адрес
+// целиком, и части локальной части (ivan.petrov@mail.ru -> ivan, petrov)
+func personalParts(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil
+	}
+
+	parts := []string{value}
+	local, _, isEmail := strings.Cut(value, "@")
+	if isEmail {
+		parts = append(parts, local)
+		value = local
+	}
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	parts = append(parts, words...)
+
+	result := parts[:0]
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= personalMinLength {
+			result = append(result, part)
+		}
+	}
+	return result
+}
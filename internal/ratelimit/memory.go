@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepEvery = time.Minute
+
+// MemoryLimiter хранит корзины в памяти процесса. Подходит для одного
+// экземпляра приложения и для тестов.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{tats: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, rate Rate) (Result, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	res, tat := gcra(now, m.tats[key], rate)
+	if res.Allowed {
+		m.tats[key] = tat
+	}
+	return res, nil
+}
+
+// sweep удаляет полностью восполненные корзины, чтобы карта не росла бесконечно
+func (m *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepEvery {
+		return
+	}
+	for key, tat := range m.tats {
+		if tat.Before(now) {
+			delete(m.tats, key)
+		}
+	}
+	m.lastSweep = now
+}
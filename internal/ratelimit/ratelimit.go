@@ -0,0 +1,116 @@
+// Package ratelimit implements token bucket rate limiting (as GCRA, which
+// needs a single timestamp per key) with in-memory and Redis backends.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+// Rate - емкость корзины и время ее полного восполнения.
+// Например, 10/1m разрешает 10 запросов подряд и затем по одному каждые 6 секунд.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate разбирает строку вида "10/1m"
+func ParseRate(s string) (Rate, error) {
+	limit, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q, expected limit/period", s)
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate limit %q", limit)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate period %q", period)
+	}
+	return Rate{Limit: n, Period: d}, nil
+}
+
+// MustParseRate - ParseRate для значений, заданных в коде
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+// interval - время восполнения одного токена
+func (r Rate) interval() time.Duration {
+	return r.Period / time.Duration(r.Limit)
+}
+
+// Result - решение по запросу
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter - через сколько корзина восполнится полностью
+	ResetAfter time.Duration
+	// RetryAfter - через сколько появится токен, если запрос отклонен
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Allow забирает токен из корзины key, если он есть
+	Allow(ctx context.Context, key string, rate Rate) (Result, error)
+}
+
+// gcra вычисляет решение по сохраненному теоретическому времени прихода (tat).
+// Возвращает новое tat, если запрос разрешен.
+func gcra(now, tat time.Time, rate Rate) (Result, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	interval := rate.interval()
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-rate.Period)
+
+	if now.Before(allowAt) {
+		return Result{
+			Limit:      rate.Limit,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, tat
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      rate.Limit,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTat.Sub(now),
+	}, newTat
+}
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// New создает ограничитель в соответствии с конфигурацией
+func New(cfg config.RateLimitConfig) (Limiter, error) {
+	switch cfg.Backend {
+	case BackendMemory, "":
+		return NewMemoryLimiter(), nil
+	case BackendRedis:
+		return NewRedisLimiter(cfg.RedisURL)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+}
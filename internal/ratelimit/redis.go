@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// gcraScript - тот же алгоритм, что и gcra, выполняемый атомарно в Redis.
+// Время берется из Redis, чтобы экземпляры с расходящимися часами считали одинаково.
+// Все значения - в микросекундах.
+var gcraScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - period
+if now < allow_at then
+	return {0, allow_at - now, tat - now}
+end
+
+-- %.0f: без форматирования Lua округлит большое число до 14 значащих цифр
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, now - allow_at, new_tat - now}
+`)
+
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(url string) (*RedisLimiter, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	return &RedisLimiter{client: redis.NewClient(opts)}, nil
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string, rate Rate) (Result, error) {
+	interval := rate.interval()
+
+	res, err := gcraScript.Run(ctx, r.client, []string{redisKeyPrefix + key},
+		interval.Microseconds(), rate.Period.Microseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, slack, reset := res[0] == 1, time.Duration(res[1])*time.Microsecond, time.Duration(res[2])*time.Microsecond
+	if !allowed {
+		return Result{Limit: rate.Limit, ResetAfter: reset, RetryAfter: slack}, nil
+	}
+	return Result{
+		Allowed:    true,
+		Limit:      rate.Limit,
+		Remaining:  int(slack / interval),
+		ResetAfter: reset,
+	}, nil
+}
+
+func (r *RedisLimiter) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+)
+
+type AddressRepo struct {
+	db *gorm.DB
+}
+
+func NewAddressRepo(db *gorm.DB) *AddressRepo {
+	return &AddressRepo{db: db}
+}
+
+func (r *AddressRepo) GetUserAddresses(userID int64) ([]model.UserAddress, error) {
+	addresses := make([]model.UserAddress, 0)
+	return addresses, r.db.Where("user_id = ?", userID).
+		Order("is_default DESC, updated_at DESC").
+		Find(&addresses).Error
+}
+
+func (r *AddressRepo) GetUserAddress(userID, id int64) (model.UserAddress, error) {
+	var address model.UserAddress
+	return address, r.db.Where("user_id = ? AND id = ?", userID, id).First(&address).Error
+}
+
+func (r *AddressRepo) CountUserAddresses(userID int64) (int64, error) {
+	var count int64
+	return count, r.db.Model(&model.UserAddress{}).Where("user_id = ?", userID).Count(&count).Error
+}
+
+// CreateAddress сохраняет адрес. Первый адрес пользователя становится основным.
+func (r *AddressRepo) CreateAddress(address model.UserAddress) (model.UserAddress, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.UserAddress{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			address.IsDefault = true
+		}
+		if address.IsDefault {
+			if err := unsetDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&address).Error
+	})
+	return address, err
+}
+
+// UpdateAddress заменяет поля адреса. Снять отметку основного можно,
+// только назначив основным другой адрес.
+func (r *AddressRepo) UpdateAddress(address model.UserAddress) (model.UserAddress, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.UserAddress
+		if err := tx.Where("user_id = ? AND id = ?", address.UserID, address.ID).First(&current).Error; err != nil {
+			return err
+		}
+		address.CreatedAt = current.CreatedAt
+		if current.IsDefault {
+			address.IsDefault = true
+		} else if address.IsDefault {
+			if err := unsetDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&address).Error
+	})
+	return address, err
+}
+
+func (r *AddressRepo) SetDefaultAddress(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var address model.UserAddress
+		if err := tx.Where("user_id = ? AND id = ?", userID, id).First(&address).Error; err != nil {
+			return err
+		}
+		if err := unsetDefaultAddress(tx, userID); err != nil {
+			return err
+		}
+		return tx.Model(&address).Update("is_default", true).Error
+	})
+}
+
+// DeleteAddress удаляет адрес; если он был основным, основным становится
+// последний измененный из оставшихся
+func (r *AddressRepo) DeleteAddress(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var address model.UserAddress
+		if err := tx.Where("user_id = ? AND id = ?", userID, id).First(&address).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&address).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next model.UserAddress
+		err := tx.Where("user_id = ?", userID).Order("updated_at DESC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+func unsetDefaultAddress(tx *gorm.DB, userID int64) error {
+	return tx.Model(&model.UserAddress{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+}
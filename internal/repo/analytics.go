@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AnalyticsRepo struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepo(db *gorm.DB) *AnalyticsRepo {
+	return &AnalyticsRepo{db: db}
+}
+
+func (r *AnalyticsRepo) RecordEvent(event model.ProductEvent) error {
+	return r.db.Create(&event).Error
+}
+
+func (r *AnalyticsRepo) DeleteEventsBefore(before time.Time) (int64, error) {
+	res := r.db.Where("created_at < ?", before).Delete(&model.ProductEvent{})
+	return res.RowsAffected, res.Error
+}
+
+// LastRollup возвращает время последней свертки; nil, если свертки еще не было
+func (r *AnalyticsRepo) LastRollup() (*time.Time, error) {
+	var at *time.Time
+	return at, r.db.Model(&model.BusinessDailyStats{}).Select("MAX(updated_at)").Scan(&at).Error
+}
+
+// FirstActivity возвращает время самого раннего заказа или события; nil, если их нет
+func (r *AnalyticsRepo) FirstActivity() (*time.Time, error) {
+	var at *time.Time
+	err := r.db.Raw(`SELECT LEAST(
+		(SELECT MIN(created_at) FROM orders WHERE payment_confirm),
+		(SELECT MIN(created_at) FROM product_events))`).Scan(&at).Error
+	return at, err
+}
+
+// rollupProductsSQL собирает дневные агрегаты по товарам из оплаченных заказов,
+// возвратов и событий. Возврат делится между позициями заказа пропорционально
+// их сумме. @since берется с запасом на часовой пояс, лишние дни отсекает @from.
+const rollupProductsSQL = `
+INSERT INTO product_daily_stats (product_id, day, business_id, revenue, units, orders, refunds, views, cart_adds, updated_at)
+SELECT product_id, day, business_id, SUM(revenue), SUM(units), COUNT(DISTINCT order_id), SUM(refunds), SUM(views), SUM(cart_adds), CAST(@now AS timestamptz)
+FROM (
+	SELECT oi.product_id, p.business_id, (o.created_at AT TIME ZONE @tz)::date AS day,
+		oi.price * oi.quantity AS revenue, oi.quantity AS units, o.id AS order_id,
+		0 AS refunds, 0 AS views, 0 AS cart_adds
+	FROM order_items oi
+	JOIN orders o ON o.id = oi.order_id
+	JOIN products p ON p.id = oi.product_id
+	WHERE o.payment_confirm AND o.created_at >= @since
+
+	UNION ALL
+
+	SELECT oi.product_id, p.business_id, (rf.created_at AT TIME ZONE @tz)::date,
+		0, 0, NULL, rf.amount * oi.price * oi.quantity / t.total, 0, 0
+	FROM order_refunds rf
+	JOIN order_items oi ON oi.order_id = rf.order_id
+	JOIN products p ON p.id = oi.product_id
+	JOIN (
+		SELECT order_id, SUM(price * quantity) AS total
+		FROM order_items
+		WHERE order_id IN (SELECT order_id FROM order_refunds WHERE created_at >= @since)
+		GROUP BY order_id
+	) t ON t.order_id = rf.order_id
+	WHERE rf.created_at >= @since AND t.total > 0
+
+	UNION ALL
+
+	SELECT e.product_id, e.business_id, (e.created_at AT TIME ZONE @tz)::date,
+		0, 0, NULL, 0,
+		CASE WHEN e.kind = 'view' THEN 1 ELSE 0 END,
+		CASE WHEN e.kind = 'cart' THEN 1 ELSE 0 END
+	FROM product_events e
+	WHERE e.created_at >= @since
+) x
+WHERE day >= @from
+GROUP BY product_id, day, business_id`
+
+// rollupBusinessesSQL суммирует агрегаты товаров по бизнесам. Заказы считаются
+// отдельно: заказ с несколькими товарами продавца - один заказ.
+const rollupBusinessesSQL = `
+INSERT INTO business_daily_stats (business_id, day, revenue, orders, units, refunds, refunded_orders, views, cart_adds, updated_at)
+SELECT s.business_id, s.day, s.revenue, COALESCE(o.orders, 0), s.units, s.refunds, COALESCE(rf.orders, 0), s.views, s.cart_adds, CAST(@now AS timestamptz)
+FROM (
+	SELECT business_id, day, SUM(revenue) AS revenue, SUM(units) AS units, SUM(refunds) AS refunds,
+		SUM(views) AS views, SUM(cart_adds) AS cart_adds
+	FROM product_daily_stats
+	WHERE day >= @from
+	GROUP BY business_id, day
+) s
+LEFT JOIN (
+	SELECT p.business_id, (o.created_at AT TIME ZONE @tz)::date AS day, COUNT(DISTINCT o.id) AS orders
+	FROM orders o
+	JOIN order_items oi ON oi.order_id = o.id
+	JOIN products p ON p.id = oi.product_id
+	WHERE o.payment_confirm AND o.created_at >= @since
+	GROUP BY 1, 2
+) o ON o.business_id = s.business_id AND o.day = s.day
+LEFT JOIN (
+	SELECT p.business_id, (rf.created_at AT TIME ZONE @tz)::date AS day, COUNT(DISTINCT rf.order_id) AS orders
+	FROM order_refunds rf
+	JOIN order_items oi ON oi.order_id = rf.order_id
+	JOIN products p ON p.id = oi.product_id
+	WHERE rf.created_at >= @since
+	GROUP BY 1, 2
+) rf ON rf.business_id = s.business_id AND rf.day = s.day`
+
+// Rollup пересчитывает дневные агрегаты начиная с дня from. Старые строки
+// удаляются и собираются заново в одной транзакции, чтобы API не видел
+// частично пересчитанные дни.
+func (r *AnalyticsRepo) Rollup(from model.DateOnly, tz string, now time.Time) error {
+	args := map[string]any{
+		"from":  from,
+		"since": from.AddDate(0, 0, -1),
+		"tz":    tz,
+		"now":   now,
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day >= ?", from).Delete(&model.ProductDailyStats{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("day >= ?", from).Delete(&model.BusinessDailyStats{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(rollupProductsSQL, args).Error; err != nil {
+			return err
+		}
+		return tx.Exec(rollupBusinessesSQL, args).Error
+	})
+}
+
+// businessMetrics - выборка метрик бизнеса за период [from, to]
+func (r *AnalyticsRepo) businessMetrics(businessID int64, from, to model.DateOnly) *gorm.DB {
+	return r.db.Model(&model.BusinessDailyStats{}).
+		Where("business_id = ? AND day BETWEEN ? AND ?", businessID, from, to)
+}
+
+const metricsColumns = "COALESCE(SUM(revenue), 0) AS revenue, COALESCE(SUM(orders), 0) AS orders, " +
+	"COALESCE(SUM(units), 0) AS units, COALESCE(SUM(refunds), 0) AS refunds, " +
+	"COALESCE(SUM(refunded_orders), 0) AS refunded_orders"
+
+func (r *AnalyticsRepo) GetTotals(businessID int64, from, to model.DateOnly) (model.AnalyticsMetrics, error) {
+	var m model.AnalyticsMetrics
+	return m, r.businessMetrics(businessID, from, to).Select(metricsColumns).Scan(&m).Error
+}
+
+// GetSeries возвращает метрики по интервалам; интервалы без продаж пропускаются
+func (r *AnalyticsRepo) GetSeries(businessID int64, from, to model.DateOnly, interval model.AnalyticsInterval) ([]model.AnalyticsBucket, error) {
+	var buckets []model.AnalyticsBucket
+	err := r.businessMetrics(businessID, from, to).
+		Select("date_trunc(?, day)::date AS start, "+metricsColumns, string(interval)).
+		Group("start").Order("start").
+		Scan(&buckets).Error
+	return buckets, err
+}
+
+func (r *AnalyticsRepo) GetTopProducts(businessID int64, from, to model.DateOnly, limit int) ([]model.TopProduct, error) {
+	var top []model.TopProduct
+	err := r.db.Table("product_daily_stats AS s").
+		Select("s.product_id, p.title, SUM(s.revenue) AS revenue, SUM(s.units) AS units, SUM(s.orders) AS orders").
+		Joins("JOIN products p ON p.id = s.product_id").
+		Where("s.business_id = ? AND s.day BETWEEN ? AND ?", businessID, from, to).
+		Group("s.product_id, p.title").
+		Having("SUM(s.units) > 0").
+		Order("revenue DESC, units DESC").
+		Limit(limit).
+		Scan(&top).Error
+	return top, err
+}
+
+// GetFunnel возвращает просмотры, добавления в корзину и заказы товаров бизнеса.
+// Заказ с несколькими товарами учитывается по каждому из них.
+func (r *AnalyticsRepo) GetFunnel(businessID int64, from, to model.DateOnly) (model.AnalyticsFunnel, error) {
+	var f model.AnalyticsFunnel
+	err := r.db.Model(&model.ProductDailyStats{}).
+		Select("COALESCE(SUM(views), 0) AS views, COALESCE(SUM(cart_adds), 0) AS cart_adds, COALESCE(SUM(orders), 0) AS orders").
+		Where("business_id = ? AND day BETWEEN ? AND ?", businessID, from, to).
+		Scan(&f).Error
+	return f, err
+}
+
+// ProductSales - остаток товара и число проданных единиц за окно прогноза
+type ProductSales struct {
+	ProductID int64
+	Title     string
+	Quantity  int
+	Units     int64
+}
+
+// GetProductSales возвращает товары бизнеса с продажами начиная с дня since
+func (r *AnalyticsRepo) GetProductSales(businessID int64, since model.DateOnly) ([]ProductSales, error) {
+	var sales []ProductSales
+	err := r.db.Table("products AS p").
+		Select("p.id AS product_id, p.title, p.quantity, COALESCE(SUM(s.units), 0) AS units").
+		Joins("LEFT JOIN product_daily_stats s ON s.product_id = p.id AND s.day >= ?", since).
+		Where("p.business_id = ?", businessID).
+		Group("p.id, p.title, p.quantity").
+		Scan(&sales).Error
+	return sales, err
+}
@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func (r *AuditRepo) CreateAuthEvent(event model.AuthEvent) error {
+	return r.db.Create(&event).Error
+}
+
+func (r *AuditRepo) DeleteAuthEventsBefore(before time.Time) error {
+	return r.db.Where("created_at < ?", before).Delete(&model.AuthEvent{}).Error
+}
@@ -13,23 +13,24 @@ func NewBusinessRepo(db *gorm.DB) *BusinessRepo {
 	return &BusinessRepo{db: db}
 }
 
-func (br *BusinessRepo) CreateBusiness(user_id int64, business model.Business) error {
+func (br *BusinessRepo) CreateBusiness(user_id int64, business model.Business) (model.Business, error) {
 	err := br.db.Create(&business).Error
 	if err != nil {
-		return err
+		return model.Business{}, err
 	}
 
 	m := model.UserToBusiness{
 		UserID:     user_id,
 		BusinessID: business.ID,
+		Role:       model.MemberOwner,
 	}
 
 	err = br.db.Create(&m).Error
 	if err != nil {
-		return err
+		return model.Business{}, err
 	}
 
-	return nil
+	return business, nil
 }
 
 func (br *BusinessRepo) GetAllBusinesses() ([]model.Business, error) {
@@ -92,15 +93,12 @@ func (br *BusinessRepo) GetBusinessesUsers(userID int64) ([]model.User, error) {
 	return users, nil
 }
 
-func (br *BusinessRepo) AddUserToBusiness(userID int64, businessID int64) error {
-	m := model.UserToBusiness{
-		UserID:     userID,
-		BusinessID: businessID,
+// GetMemberRole возвращает роль пользователя в бизнесе; false, если он не участник
+func (br *BusinessRepo) GetMemberRole(userID int64, businessID int64) (model.MemberRole, bool, error) {
+	var members []model.UserToBusiness
+	err := br.db.Where("user_id = ? AND business_id = ?", userID, businessID).Limit(1).Find(&members).Error
+	if err != nil || len(members) == 0 {
+		return "", false, err
 	}
-
-	return br.db.Create(&m).Error
-}
-
-func (br *BusinessRepo) RemoveUserFromBusiness(userID int64, businessID int64) error {
-	return br.db.Where("user_id = ? AND business_id = ?", userID, businessID).Delete(&model.UserToBusiness{}).Error
+	return members[0].Role, true, nil
 }
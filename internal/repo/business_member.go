@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type BusinessMemberRepo struct {
+	db *gorm.DB
+}
+
+func NewBusinessMemberRepo(db *gorm.DB) *BusinessMemberRepo {
+	return &BusinessMemberRepo{db: db}
+}
+
+func (r *BusinessMemberRepo) GetMembers(businessID int64) ([]model.BusinessMember, error) {
+	var members []model.BusinessMember
+	err := r.db.Table("user_to_businesses").
+		Select("users.id AS user_id, users.name, users.surname, users.email, user_to_businesses.role").
+		Joins("JOIN users ON users.id = user_to_businesses.user_id").
+		Where("user_to_businesses.business_id = ?", businessID).
+		Order("users.id").
+		Scan(&members).Error
+	return members, err
+}
+
+func (r *BusinessMemberRepo) GetMember(businessID, userID int64) (model.UserToBusiness, error) {
+	var m model.UserToBusiness
+	return m, r.db.Where("business_id = ? AND user_id = ?", businessID, userID).First(&m).Error
+}
+
+// UpdateMemberRole меняет роль участника. Возвращает false, если после
+// изменения у бизнеса не останется владельцев.
+func (r *BusinessMemberRepo) UpdateMemberRole(businessID, userID int64, role model.MemberRole) (bool, error) {
+	ok := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keep, err := keepsOwner(tx, businessID, userID, role == model.MemberOwner)
+		if err != nil || !keep {
+			return err
+		}
+		ok = true
+		return tx.Model(&model.UserToBusiness{}).
+			Where("business_id = ? AND user_id = ?", businessID, userID).
+			Update("role", role).Error
+	})
+	return ok && err == nil, err
+}
+
+// RemoveMember исключает участника из бизнеса. Возвращает false, если это
+// последний владелец.
+func (r *BusinessMemberRepo) RemoveMember(businessID, userID int64) (bool, error) {
+	ok := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keep, err := keepsOwner(tx, businessID, userID, false)
+		if err != nil || !keep {
+			return err
+		}
+		ok = true
+		return tx.Where("business_id = ? AND user_id = ?", businessID, userID).
+			Delete(&model.UserToBusiness{}).Error
+	})
+	return ok && err == nil, err
+}
+
+// keepsOwner блокирует строку бизнеса, чтобы параллельные изменения участников
+// выполнялись по очереди, и проверяет, что после изменения участника userID у
+// бизнеса останется хотя бы один владелец. stillOwner - останется ли он владельцем.
+func keepsOwner(tx *gorm.DB, businessID, userID int64, stillOwner bool) (bool, error) {
+	var business model.Business
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ?", businessID).First(&business).Error
+	if err != nil {
+		return false, err
+	}
+
+	var member model.UserToBusiness
+	err = tx.Where("business_id = ? AND user_id = ?", businessID, userID).First(&member).Error
+	if err != nil {
+		return false, err
+	}
+	if member.Role != model.MemberOwner || stillOwner {
+		return true, nil
+	}
+
+	var owners int64
+	err = tx.Model(&model.UserToBusiness{}).
+		Where("business_id = ? AND role = ?", businessID, model.MemberOwner).
+		Count(&owners).Error
+	return owners > 1, err
+}
+
+// TransferOwnership делает участника toID владельцем, а владельца fromID -
+// менеджером. Если fromID не владелец (передачу выполняет администратор),
+// меняется только роль toID.
+func (r *BusinessMemberRepo) TransferOwnership(businessID, fromID, toID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var business model.Business
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ?", businessID).First(&business).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&model.UserToBusiness{}).
+			Where("business_id = ? AND user_id = ?", businessID, toID).
+			Update("role", model.MemberOwner)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&model.UserToBusiness{}).
+			Where("business_id = ? AND user_id = ? AND role = ?", businessID, fromID, model.MemberOwner).
+			Update("role", model.MemberManager).Error
+	})
+}
+
+// CreateInvitation создает приглашение и отзывает прежние неотвеченные
+// приглашения того же адреса в этот бизнес: действует только последняя ссылка.
+func (r *BusinessMemberRepo) CreateInvitation(inv model.BusinessInvitation) (model.BusinessInvitation, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.BusinessInvitation{}).
+			Where("business_id = ? AND email = ? AND status = ?", inv.BusinessID, inv.Email, model.InvitationPending).
+			Updates(map[string]any{"status": model.InvitationRevoked, "responded_at": inv.CreatedAt}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&inv).Error
+	})
+	return inv, err
+}
+
+func (r *BusinessMemberRepo) GetPendingInvitations(businessID int64, now time.Time) ([]model.BusinessInvitation, error) {
+	var invs []model.BusinessInvitation
+	return invs, r.db.Where("business_id = ? AND status = ? AND expires_at > ?", businessID, model.InvitationPending, now).
+		Order("id").Find(&invs).Error
+}
+
+func (r *BusinessMemberRepo) GetInvitationByHash(hash string) (model.BusinessInvitation, error) {
+	var inv model.BusinessInvitation
+	return inv, r.db.Where("token_hash = ?", hash).First(&inv).Error
+}
+
+func (r *BusinessMemberRepo) GetInvitation(businessID, id int64) (model.BusinessInvitation, error) {
+	var inv model.BusinessInvitation
+	return inv, r.db.Where("id = ? AND business_id = ?", id, businessID).First(&inv).Error
+}
+
+func (r *BusinessMemberRepo) RevokeInvitation(businessID, id int64, now time.Time) error {
+	res := r.db.Model(&model.BusinessInvitation{}).
+		Where("id = ? AND business_id = ? AND status = ?", id, businessID, model.InvitationPending).
+		Updates(map[string]any{"status": model.InvitationRevoked, "responded_at": now})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation отмечает приглашение принятым и добавляет пользователя в
+// бизнес с ролью из приглашения. Возвращает false, если приглашение уже
+// использовано, отозвано или истекло.
+func (r *BusinessMemberRepo) AcceptInvitation(inv model.BusinessInvitation, userID int64, now time.Time) (bool, error) {
+	ok := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.BusinessInvitation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", inv.ID, model.InvitationPending, now).
+			Updates(map[string]any{"status": model.InvitationAccepted, "responded_at": now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		ok = true
+
+		var n int64
+		err := tx.Model(&model.UserToBusiness{}).
+			Where("business_id = ? AND user_id = ?", inv.BusinessID, userID).Count(&n).Error
+		if err != nil || n > 0 {
+			return err
+		}
+		return tx.Create(&model.UserToBusiness{UserID: userID, BusinessID: inv.BusinessID, Role: inv.Role}).Error
+	})
+	return ok && err == nil, err
+}
+
+// DeclineInvitation отмечает приглашение отклоненным. Возвращает false, если
+// на приглашение уже ответили или оно истекло.
+func (r *BusinessMemberRepo) DeclineInvitation(id int64, now time.Time) (bool, error) {
+	res := r.db.Model(&model.BusinessInvitation{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, model.InvitationPending, now).
+		Updates(map[string]any{"status": model.InvitationDeclined, "responded_at": now})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *BusinessMemberRepo) DeleteExpiredInvitations(before time.Time) (int64, error) {
+	res := r.db.Where("expires_at < ?", before).Delete(&model.BusinessInvitation{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type BusinessVerificationRepo struct {
+	db *gorm.DB
+}
+
+func NewBusinessVerificationRepo(db *gorm.DB) *BusinessVerificationRepo {
+	return &BusinessVerificationRepo{db: db}
+}
+
+func (r *BusinessVerificationRepo) CreateDocument(doc model.BusinessDocument) (model.BusinessDocument, error) {
+	return doc, r.db.Create(&doc).Error
+}
+
+func (r *BusinessVerificationRepo) GetDocuments(businessID int64) ([]model.BusinessDocument, error) {
+	var docs []model.BusinessDocument
+	return docs, r.db.Where("business_id = ?", businessID).Order("id").Find(&docs).Error
+}
+
+func (r *BusinessVerificationRepo) GetDocument(businessID, id int64) (model.BusinessDocument, error) {
+	var doc model.BusinessDocument
+	return doc, r.db.Where("id = ? AND business_id = ?", id, businessID).First(&doc).Error
+}
+
+func (r *BusinessVerificationRepo) CountDocuments(businessID int64) (int64, error) {
+	var n int64
+	return n, r.db.Model(&model.BusinessDocument{}).Where("business_id = ?", businessID).Count(&n).Error
+}
+
+func (r *BusinessVerificationRepo) DeleteDocument(businessID, id int64) error {
+	res := r.db.Where("id = ? AND business_id = ?", id, businessID).Delete(&model.BusinessDocument{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BusinessVerificationRepo) GetLatestVerification(businessID int64) (model.BusinessVerification, error) {
+	var v model.BusinessVerification
+	return v, r.db.Where("business_id = ?", businessID).Order("id DESC").First(&v).Error
+}
+
+func (r *BusinessVerificationRepo) GetVerificationsByStatus(status model.BusinessVerificationStatus) ([]model.BusinessVerification, error) {
+	var vs []model.BusinessVerification
+	return vs, r.db.Where("status = ?", status).Order("submitted_at").Find(&vs).Error
+}
+
+// Submit создает заявку и переводит бизнес в pending. Возвращает false, если
+// бизнес уже на проверке или подтвержден: статус проверяется в том же UPDATE,
+// чтобы две параллельные заявки не прошли обе.
+func (r *BusinessVerificationRepo) Submit(v model.BusinessVerification) (model.BusinessVerification, bool, error) {
+	ok := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Business{}).
+			Where("id = ? AND verification_status IN ?", v.BusinessID,
+				[]model.BusinessVerificationStatus{model.BusinessUnverified, model.BusinessRejected}).
+			Update("verification_status", model.BusinessPending)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		ok = true
+		return tx.Create(&v).Error
+	})
+	return v, ok && err == nil, err
+}
+
+// Review переводит бизнес из одного из статусов from в to и записывает решение
+// в последнюю заявку. При отказе снимает с публикации одобренные товары бизнеса:
+// публиковать их может только подтвержденный бизнес.
+func (r *BusinessVerificationRepo) Review(
+	businessID int64,
+	from []model.BusinessVerificationStatus,
+	to model.BusinessVerificationStatus,
+	reviewerID int64,
+	comment string,
+	now time.Time,
+) (bool, error) {
+	var verifiedAt *time.Time
+	if to == model.BusinessVerified {
+		verifiedAt = &now
+	}
+
+	ok := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Business{}).
+			Where("id = ? AND verification_status IN ?", businessID, from).
+			Updates(map[string]any{"verification_status": to, "verified_at": verifiedAt})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		ok = true
+
+		err := tx.Model(&model.BusinessVerification{}).
+			Where("id = (?)", tx.Model(&model.BusinessVerification{}).Select("id").
+				Where("business_id = ?", businessID).Order("id DESC").Limit(1)).
+			Updates(map[string]any{"status": to, "reviewed_by": reviewerID, "reviewed_at": now, "comment": comment}).Error
+		if err != nil {
+			return err
+		}
+
+		if to == model.BusinessVerified {
+			return nil
+		}
+		return tx.Model(&model.Product{}).
+			Where("business_id = ? AND status = ?", businessID, model.StatusApprove).
+			Update("status", model.StatusConsideration).Error
+	})
+	return ok && err == nil, err
+}
@@ -0,0 +1,200 @@
+package repo
+
+import (
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type LedgerRepo struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepo(db *gorm.DB) *LedgerRepo {
+	return &LedgerRepo{db: db}
+}
+
+// postLedger сохраняет транзакцию с проводками. Транзакция с уже проведенным
+// ключом пропускается целиком.
+func postLedger(tx *gorm.DB, t model.LedgerTransaction) error {
+	entries := t.Entries
+	t.Entries = nil
+	res := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&t)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return res.Error
+	}
+
+	for i := range entries {
+		entries[i].TransactionID = t.ID
+		entries[i].CreatedAt = t.CreatedAt
+	}
+	return tx.Create(&entries).Error
+}
+
+// Post проводит транзакции атомарно
+func (r *LedgerRepo) Post(txs ...model.LedgerTransaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range txs {
+			if err := postLedger(tx, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LedgerOrderItem - позиция заказа для расчетов с продавцом, сумма в копейках
+type LedgerOrderItem struct {
+	BusinessID int64
+	Category   model.ProductCategory
+	Amount     int64
+}
+
+// GetOrderItems возвращает позиции заказа, упорядоченные по бизнесу
+func (r *LedgerRepo) GetOrderItems(orderID int64) ([]LedgerOrderItem, error) {
+	var items []LedgerOrderItem
+	err := r.db.Table("order_items AS oi").
+		Select("p.business_id, p.category, ROUND(oi.price * oi.quantity * 100)::bigint AS amount").
+		Joins("JOIN products p ON p.id = oi.product_id").
+		Where("oi.order_id = ?", orderID).
+		Order("p.business_id, oi.product_id").
+		Scan(&items).Error
+	return items, err
+}
+
+// GetOrderCommission возвращает комиссию, удержанную с бизнеса по заказу, в копейках
+func (r *LedgerRepo) GetOrderCommission(orderID, businessID int64) (int64, error) {
+	var commission int64
+	err := r.db.Table("ledger_entries AS e").
+		Select("COALESCE(-SUM(e.amount), 0)").
+		Joins("JOIN ledger_transactions t ON t.id = e.transaction_id").
+		Where("t.order_id = ? AND t.business_id = ? AND t.kind = ? AND e.account = ?",
+			orderID, businessID, model.LedgerCommissionCharge, model.LedgerCommission).
+		Scan(&commission).Error
+	return commission, err
+}
+
+// GetUncapturedOrders возвращает оплаченные через ЮKassa заказы с позициями,
+// поступление по которым еще не проведено
+func (r *LedgerRepo) GetUncapturedOrders(limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&model.Order{}).
+		Where("payment_confirm AND payment_id IS NOT NULL AND EXISTS (?) AND NOT EXISTS (?)",
+			r.db.Model(&model.OrderItem{}).Select("1").Where("order_items.order_id = orders.id"),
+			r.db.Model(&model.LedgerTransaction{}).Select("1").
+				Where("ledger_transactions.order_id = orders.id AND ledger_transactions.kind = ?", model.LedgerCapture)).
+		Order("id").Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *LedgerRepo) GetCommissionRules() ([]model.CommissionRule, error) {
+	var rules []model.CommissionRule
+	return rules, r.db.Order("id").Find(&rules).Error
+}
+
+// SaveCommissionRule создает правило или меняет ставку правила для той же пары бизнес-категория
+func (r *LedgerRepo) SaveCommissionRule(rule model.CommissionRule) (model.CommissionRule, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.CommissionRule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("business_id IS NOT DISTINCT FROM ? AND category IS NOT DISTINCT FROM ?", rule.BusinessID, rule.Category).
+			First(&existing).Error
+		if err == nil {
+			existing.Rate = rule.Rate
+			rule = existing
+			return tx.Save(&rule).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(&rule).Error
+	})
+	return rule, err
+}
+
+func (r *LedgerRepo) DeleteCommissionRule(id int64) (bool, error) {
+	res := r.db.Where("id = ?", id).Delete(&model.CommissionRule{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// availableExpr - баланс продавца без поступлений моложе срока удержания.
+// Списания (возвраты, комиссии, выплаты) учитываются сразу.
+const availableExpr = "COALESCE(-SUM(amount) FILTER (WHERE account = 'seller_payable' AND (amount > 0 OR created_at <= @cutoff)), 0)"
+
+func (r *LedgerRepo) GetSellerBalance(businessID int64, cutoff time.Time) (model.SellerBalance, error) {
+	balance := model.SellerBalance{BusinessID: businessID}
+	err := r.db.Raw(`SELECT
+		COALESCE(-SUM(amount) FILTER (WHERE account = 'seller_payable'), 0) AS balance,
+		`+availableExpr+` AS available,
+		COALESCE(-SUM(amount) FILTER (WHERE account = 'payouts_in_transit'), 0) AS in_transit
+		FROM ledger_entries WHERE business_id = @business`,
+		map[string]any{"business": businessID, "cutoff": cutoff}).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// BusinessAmount - сумма в копейках по бизнесу
+type BusinessAmount struct {
+	BusinessID int64
+	Amount     int64
+}
+
+// GetAvailableBalances возвращает бизнесы с положительным доступным к выплате балансом
+func (r *LedgerRepo) GetAvailableBalances(cutoff time.Time) ([]BusinessAmount, error) {
+	var balances []BusinessAmount
+	err := r.db.Raw(`SELECT business_id, `+availableExpr+` AS amount
+		FROM ledger_entries
+		GROUP BY business_id
+		HAVING `+availableExpr+` > 0
+		ORDER BY business_id`,
+		map[string]any{"cutoff": cutoff}).
+		Scan(&balances).Error
+	return balances, err
+}
+
+// availableBalance - доступный к выплате баланс бизнеса внутри транзакции tx
+func availableBalance(tx *gorm.DB, businessID int64, cutoff time.Time) (int64, error) {
+	var amount int64
+	err := tx.Raw(`SELECT `+availableExpr+` FROM ledger_entries WHERE business_id = @business`,
+		map[string]any{"business": businessID, "cutoff": cutoff}).
+		Scan(&amount).Error
+	return amount, err
+}
+
+func (r *LedgerRepo) GetPlatformBalance() (model.PlatformBalance, error) {
+	var balance model.PlatformBalance
+	err := r.db.Raw(`SELECT
+		COALESCE(SUM(amount) FILTER (WHERE account = 'cash'), 0) AS cash,
+		COALESCE(-SUM(amount) FILTER (WHERE account = 'seller_payable'), 0) AS seller_payable,
+		COALESCE(-SUM(amount) FILTER (WHERE account = 'commission'), 0) AS commission,
+		COALESCE(-SUM(amount) FILTER (WHERE account = 'payouts_in_transit'), 0) AS in_transit
+		FROM ledger_entries`).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// GetSellerBalanceAt возвращает долг площадки перед продавцом на момент before
+func (r *LedgerRepo) GetSellerBalanceAt(businessID int64, before time.Time) (int64, error) {
+	var balance int64
+	err := r.db.Model(&model.LedgerEntry{}).
+		Select("COALESCE(-SUM(amount), 0)").
+		Where("business_id = ? AND account = ? AND created_at < ?", businessID, model.LedgerSellerPayable, before).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// GetStatementLines возвращает операции по балансу продавца за период [from, to)
+func (r *LedgerRepo) GetStatementLines(businessID int64, from, to time.Time) ([]model.StatementLine, error) {
+	var lines []model.StatementLine
+	err := r.db.Table("ledger_entries AS e").
+		Select("t.id AS transaction_id, t.created_at AS date, t.kind, t.order_id, t.payout_id, t.description, -e.amount AS amount").
+		Joins("JOIN ledger_transactions t ON t.id = e.transaction_id").
+		Where("e.business_id = ? AND e.account = ? AND e.created_at >= ? AND e.created_at < ?",
+			businessID, model.LedgerSellerPayable, from, to).
+		Order("t.created_at, t.id").
+		Scan(&lines).Error
+	return lines, err
+}
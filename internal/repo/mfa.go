@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type MFARepo struct {
+	db *gorm.DB
+}
+
+func NewMFARepo(db *gorm.DB) *MFARepo {
+	return &MFARepo{db: db}
+}
+
+func (r *MFARepo) GetMFA(userID int64) (model.UserMFA, error) {
+	var mfa model.UserMFA
+	return mfa, r.db.Where("user_id = ?", userID).First(&mfa).Error
+}
+
+// SaveMFA создает или полностью заменяет настройки 2FA пользователя
+func (r *MFARepo) SaveMFA(mfa model.UserMFA) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&mfa).Error
+}
+
+// UseStep фиксирует использованный временной шаг TOTP. Возвращает false,
+// если этот или более поздний шаг уже использован (повтор кода).
+func (r *MFARepo) UseStep(userID int64, step int64) (bool, error) {
+	res := r.db.Model(&model.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]any{"last_used_step": step, "failed_attempts": 0, "locked_until": nil})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *MFARepo) EnableMFA(userID int64, at time.Time) error {
+	return r.db.Model(&model.UserMFA{}).Where("user_id = ?", userID).
+		Updates(map[string]any{"enabled": true, "confirmed_at": at}).Error
+}
+
+func (r *MFARepo) ResetFailures(userID int64) error {
+	return r.db.Model(&model.UserMFA{}).Where("user_id = ?", userID).
+		Updates(map[string]any{"failed_attempts": 0, "locked_until": nil}).Error
+}
+
+// RegisterFailure увеличивает счетчик неудачных попыток и блокирует проверку
+// до lockUntil, если счетчик достиг maxAttempts
+func (r *MFARepo) RegisterFailure(userID int64, maxAttempts int, lockUntil time.Time) error {
+	return r.db.Model(&model.UserMFA{}).Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"failed_attempts": gorm.Expr("failed_attempts + 1"),
+			"locked_until":    gorm.Expr("CASE WHEN failed_attempts + 1 >= ? THEN ?::timestamptz ELSE locked_until END", maxAttempts, lockUntil),
+		}).Error
+}
+
+func (r *MFARepo) DeleteMFA(userID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserMFA{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes удаляет старые коды восстановления и сохраняет новые хеши
+func (r *MFARepo) ReplaceRecoveryCodes(userID int64, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode помечает неиспользованный код с хешем hash использованным
+func (r *MFARepo) UseRecoveryCode(userID int64, hash string, at time.Time) (bool, error) {
+	res := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *MFARepo) CountRecoveryCodes(userID int64) (int64, error) {
+	var count int64
+	return count, r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type OAuthRepo struct {
+	db *gorm.DB
+}
+
+func NewOAuthRepo(db *gorm.DB) *OAuthRepo {
+	return &OAuthRepo{db: db}
+}
+
+func (r *OAuthRepo) CreateState(state model.OAuthState) error {
+	return r.db.Create(&state).Error
+}
+
+// TakeState удаляет state и возвращает его, чтобы один state нельзя было использовать дважды
+func (r *OAuthRepo) TakeState(stateHash string) (model.OAuthState, error) {
+	var states []model.OAuthState
+	err := r.db.Clauses(clause.Returning{}).Where("state_hash = ?", stateHash).Delete(&states).Error
+	if err != nil {
+		return model.OAuthState{}, err
+	}
+	if len(states) == 0 {
+		return model.OAuthState{}, gorm.ErrRecordNotFound
+	}
+	return states[0], nil
+}
+
+func (r *OAuthRepo) DeleteExpiredStates(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.OAuthState{}).Error
+}
+
+func (r *OAuthRepo) GetIdentity(provider string, subject string) (model.UserIdentity, error) {
+	var identity model.UserIdentity
+	return identity, r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+}
+
+func (r *OAuthRepo) GetUserIdentities(userID int64) ([]model.UserIdentity, error) {
+	identities := make([]model.UserIdentity, 0)
+	return identities, r.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+}
+
+func (r *OAuthRepo) CreateIdentity(identity model.UserIdentity) (model.UserIdentity, error) {
+	return identity, r.db.Create(&identity).Error
+}
+
+// CreateUserWithIdentity регистрирует пользователя, впервые вошедшего через провайдера
+func (r *OAuthRepo) CreateUserWithIdentity(user model.User, identity model.UserIdentity) (model.User, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(&identity).Error
+	})
+	return user, err
+}
+
+func (r *OAuthRepo) TouchIdentity(id int64, now time.Time) error {
+	return r.db.Model(&model.UserIdentity{}).Where("id = ?", id).Update("last_login_at", now).Error
+}
+
+// DeleteIdentity отвязывает провайдера; возвращает false, если привязки не было
+func (r *OAuthRepo) DeleteIdentity(userID int64, provider string) (bool, error) {
+	res := r.db.Where("user_id = ? AND provider = ?", userID, provider).Delete(&model.UserIdentity{})
+	return res.RowsAffected > 0, res.Error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepo struct {
@@ -57,6 +58,39 @@ func (or *OrderRepo) GetUserOrders(userID int64) ([]model.OrderItemResponse, err
 	return userOrders, nil
 }
 
+func (or *OrderRepo) GetOrderByID(orderID int64) (model.Order, error) {
+	var order model.Order
+	return order, or.db.Where("id = ?", orderID).First(&order).Error
+}
+
+func (or *OrderRepo) GetOrderItems(orderID int64) ([]model.ExtendedOrderItem, error) {
+	var orderItems = make([]model.OrderItem, 0)
+	if err := or.db.Where("order_id = ?", orderID).Find(&orderItems).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ExtendedOrderItem, 0, len(orderItems))
+	for _, orderItem := range orderItems {
+		product, err := or.prodRepo.GetProductByID(context.Background(), orderItem.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, model.ExtendedOrderItem{OrderItem: orderItem, Product: *product})
+	}
+	return items, nil
+}
+
 func (or *OrderRepo) ConfirmOrderPayment(orderID int64) error {
 	return or.db.Model(&model.Order{}).Where("id = ?", orderID).Update("payment_confirm", true).Error
 }
+
+// ConfirmYookassaPayment отмечает заказ оплаченным платежом ЮKassa paymentID
+func (or *OrderRepo) ConfirmYookassaPayment(orderID int64, paymentID string) error {
+	return or.db.Model(&model.Order{}).Where("id = ?", orderID).
+		Updates(map[string]any{"payment_confirm": true, "payment_id": paymentID}).Error
+}
+
+// CreateRefund сохраняет возврат; повторное уведомление о нем ничего не меняет
+func (or *OrderRepo) CreateRefund(refund model.OrderRefund) error {
+	return or.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&refund).Error
+}
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type PayoutRepo struct {
+	db *gorm.DB
+}
+
+func NewPayoutRepo(db *gorm.DB) *PayoutRepo {
+	return &PayoutRepo{db: db}
+}
+
+// CreateBatch создает реестр из выплат payouts и переводит их суммы с баланса
+// продавцов в выплаты в пути. Суммы перепроверяются под блокировкой, поэтому
+// параллельный запуск не выплатит один остаток дважды. Если выплачивать
+// нечего, реестр не создается и возвращается с нулевым ID.
+func (r *PayoutRepo) CreateBatch(payouts []model.Payout, cutoff, now time.Time) (model.PayoutBatch, error) {
+	batch := model.PayoutBatch{CreatedAt: now}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('payout_batches'))").Error; err != nil {
+			return err
+		}
+
+		for _, p := range payouts {
+			available, err := availableBalance(tx, p.BusinessID, cutoff)
+			if err != nil {
+				return err
+			}
+			if p.Amount = min(p.Amount, available); p.Amount > 0 {
+				batch.Payouts = append(batch.Payouts, p)
+				batch.Total += p.Amount
+			}
+		}
+		batch.Count = len(batch.Payouts)
+		if batch.Count == 0 {
+			return nil
+		}
+
+		created := batch.Payouts
+		batch.Payouts = nil
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+		for i := range created {
+			created[i].BatchID = batch.ID
+			created[i].Status = model.PayoutPending
+			if err := tx.Create(&created[i]).Error; err != nil {
+				return err
+			}
+
+			t := model.NewLedgerTransfer(model.LedgerPayout, fmt.Sprintf("payout:%d", created[i].ID),
+				created[i].BusinessID, model.LedgerSellerPayable, model.LedgerPayoutsInTransit, created[i].Amount)
+			t.PayoutID = &created[i].ID
+			t.Description = fmt.Sprintf("Выплата №%d", created[i].ID)
+			t.CreatedAt = now
+			if err := postLedger(tx, t); err != nil {
+				return err
+			}
+		}
+		batch.Payouts = created
+		return nil
+	})
+	return batch, err
+}
+
+// SettlePayout фиксирует ответ банка по выплате. Проведенная выплата
+// списывается с расчетного счета, непроведенная возвращается на баланс
+// продавца. Возвращает false, если выплата уже не ожидает ответа.
+func (r *PayoutRepo) SettlePayout(id int64, status model.PayoutStatus, reason string, now time.Time) (bool, error) {
+	settled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var payout model.Payout
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&payout).Error
+		if err != nil {
+			return err
+		}
+		if payout.Status != model.PayoutPending {
+			return nil
+		}
+
+		err = tx.Model(&payout).Updates(map[string]any{
+			"status":         status,
+			"failure_reason": reason,
+			"settled_at":     now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		t := model.NewLedgerTransfer(model.LedgerPayoutSettled, fmt.Sprintf("payout_settled:%d", id),
+			payout.BusinessID, model.LedgerPayoutsInTransit, model.LedgerCash, payout.Amount)
+		t.Description = fmt.Sprintf("Выплата №%d проведена банком", id)
+		if status == model.PayoutFailed {
+			t = model.NewLedgerTransfer(model.LedgerPayoutFailed, fmt.Sprintf("payout_failed:%d", id),
+				payout.BusinessID, model.LedgerPayoutsInTransit, model.LedgerSellerPayable, payout.Amount)
+			t.Description = fmt.Sprintf("Выплата №%d не проведена банком", id)
+		}
+		t.PayoutID = &payout.ID
+		t.CreatedAt = now
+		if err := postLedger(tx, t); err != nil {
+			return err
+		}
+		settled = true
+		return nil
+	})
+	return settled, err
+}
+
+func (r *PayoutRepo) GetPayoutByID(id int64) (model.Payout, error) {
+	var payout model.Payout
+	return payout, r.db.Where("id = ?", id).First(&payout).Error
+}
+
+func (r *PayoutRepo) GetBatches(limit int) ([]model.PayoutBatch, error) {
+	var batches []model.PayoutBatch
+	return batches, r.db.Order("id DESC").Limit(limit).Find(&batches).Error
+}
+
+func (r *PayoutRepo) GetBatch(id int64) (model.PayoutBatch, error) {
+	var batch model.PayoutBatch
+	err := r.db.Preload("Payouts", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id = ?", id).First(&batch).Error
+	return batch, err
+}
+
+func (r *PayoutRepo) GetBusinessPayouts(businessID int64, limit int) ([]model.Payout, error) {
+	var payouts []model.Payout
+	return payouts, r.db.Where("business_id = ?", businessID).Order("id DESC").Limit(limit).Find(&payouts).Error
+}
+
+// PayoutExportRow - строка реестра выплат для банка
+type PayoutExportRow struct {
+	PayoutID   int64
+	BusinessID int64
+	INN        int64
+	Name       string
+	Amount     int64
+}
+
+// GetExportRows возвращает ожидающие выплаты реестра с реквизитами получателей
+func (r *PayoutRepo) GetExportRows(batchID int64) ([]PayoutExportRow, error) {
+	var rows []PayoutExportRow
+	err := r.db.Table("payouts AS p").
+		Select("p.id AS payout_id, p.business_id, b.inn, COALESCE(b.full_name, b.short_name, '') AS name, p.amount").
+		Joins("JOIN businesses b ON b.id = p.business_id").
+		Where("p.batch_id = ? AND p.status = ?", batchID, model.PayoutPending).
+		Order("p.id").
+		Scan(&rows).Error
+	return rows, err
+}
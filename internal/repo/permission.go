@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionRepo struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepo(db *gorm.DB) *PermissionRepo {
+	return &PermissionRepo{db: db}
+}
+
+func (r *PermissionRepo) GetAllRolePermissions() ([]model.RolePermission, error) {
+	var perms []model.RolePermission
+	return perms, r.db.Order("role, permission").Find(&perms).Error
+}
+
+func (r *PermissionRepo) GetRolePermissions(role model.UserRoleType) ([]model.Permission, error) {
+	var perms []model.Permission
+	return perms, r.db.Model(&model.RolePermission{}).
+		Where("role = ?", role).
+		Order("permission").
+		Pluck("permission", &perms).Error
+}
+
+// SetRolePermissions заменяет все права роли
+func (r *PermissionRepo) SetRolePermissions(role model.UserRoleType, perms []model.Permission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(perms) == 0 {
+			return nil
+		}
+
+		rows := make([]model.RolePermission, 0, len(perms))
+		for _, p := range perms {
+			rows = append(rows, model.RolePermission{Role: role, Permission: p})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *PermissionRepo) CountRolePermissions() (int64, error) {
+	var count int64
+	return count, r.db.Model(&model.RolePermission{}).Count(&count).Error
+}
+
+func (r *PermissionRepo) GetKnownPermissions() ([]model.Permission, error) {
+	var perms []model.Permission
+	return perms, r.db.Model(&model.KnownPermission{}).Pluck("permission", &perms).Error
+}
+
+// GetGrantedPermissions возвращает права, выданные хотя бы одной роли
+func (r *PermissionRepo) GetGrantedPermissions() ([]model.Permission, error) {
+	var perms []model.Permission
+	return perms, r.db.Model(&model.RolePermission{}).Distinct().Pluck("permission", &perms).Error
+}
+
+// GrantNewPermissions добавляет ролям права rows, не трогая остальные, и
+// отмечает perms известными
+func (r *PermissionRepo) GrantNewPermissions(rows []model.RolePermission, perms []model.Permission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		known := make([]model.KnownPermission, 0, len(perms))
+		for _, p := range perms {
+			known = append(known, model.KnownPermission{Permission: p})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&known).Error
+	})
+}
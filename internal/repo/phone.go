@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PhoneCodeRepo struct {
+	db *gorm.DB
+}
+
+func NewPhoneCodeRepo(db *gorm.DB) *PhoneCodeRepo {
+	return &PhoneCodeRepo{db: db}
+}
+
+// ReplacePhoneCode сохраняет новый код, удаляя прежние коды номера с той же целью
+func (r *PhoneCodeRepo) ReplacePhoneCode(code model.PhoneCode) (model.PhoneCode, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("phone = ? AND purpose = ?", code.Phone, code.Purpose).Delete(&model.PhoneCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&code).Error
+	})
+	return code, err
+}
+
+func (r *PhoneCodeRepo) GetLatestPhoneCode(phone string, purpose model.PhoneCodePurpose) (model.PhoneCode, error) {
+	var code model.PhoneCode
+	return code, r.db.Where("phone = ? AND purpose = ?", phone, purpose).Order("sent_at DESC").First(&code).Error
+}
+
+// IncrementPhoneCodeAttempts увеличивает счетчик неверных попыток и возвращает новое значение
+func (r *PhoneCodeRepo) IncrementPhoneCodeAttempts(id int64) (int, error) {
+	var attempts int
+	return attempts, r.db.Raw("UPDATE phone_codes SET attempts = attempts + 1 WHERE id = ? RETURNING attempts", id).
+		Scan(&attempts).Error
+}
+
+func (r *PhoneCodeRepo) DeletePhoneCodes(phone string, purpose model.PhoneCodePurpose) error {
+	return r.db.Where("phone = ? AND purpose = ?", phone, purpose).Delete(&model.PhoneCode{}).Error
+}
+
+func (r *PhoneCodeRepo) DeleteExpiredPhoneCodes(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.PhoneCode{}).Error
+}
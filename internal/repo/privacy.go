@@ -0,0 +1,216 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PrivacyRepo struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepo(db *gorm.DB) *PrivacyRepo {
+	return &PrivacyRepo{db: db}
+}
+
+func (r *PrivacyRepo) CreateExport(export model.DataExport) (model.DataExport, error) {
+	return export, r.db.Create(&export).Error
+}
+
+func (r *PrivacyRepo) GetLatestExport(userID int64) (model.DataExport, error) {
+	var export model.DataExport
+	return export, r.db.Where("user_id = ?", userID).Order("id DESC").First(&export).Error
+}
+
+func (r *PrivacyRepo) GetUserExports(userID int64) ([]model.DataExport, error) {
+	exports := make([]model.DataExport, 0)
+	return exports, r.db.Where("user_id = ?", userID).Find(&exports).Error
+}
+
+// ClaimExport забирает в работу одну ожидающую выгрузку. Выгрузки, начатые
+// раньше staleBefore, считаются зависшими и тоже забираются.
+func (r *PrivacyRepo) ClaimExport(now, staleBefore time.Time) (model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.db.Raw(`
+		UPDATE data_exports SET status = ?, started_at = ?
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = ? OR (status = ? AND started_at < ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.DataExportProcessing, now,
+		model.DataExportPending, model.DataExportProcessing, staleBefore,
+	).Scan(&exports).Error
+	if err != nil {
+		return model.DataExport{}, err
+	}
+	if len(exports) == 0 {
+		return model.DataExport{}, gorm.ErrRecordNotFound
+	}
+	return exports[0], nil
+}
+
+func (r *PrivacyRepo) FinishExport(id int64, objectKey string, size int64, at, expiresAt time.Time) error {
+	return r.db.Model(&model.DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       model.DataExportReady,
+		"object_key":   objectKey,
+		"size":         size,
+		"completed_at": at,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (r *PrivacyRepo) FailExport(id int64, reason string, at, expiresAt time.Time) error {
+	return r.db.Model(&model.DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       model.DataExportFailed,
+		"error":        reason,
+		"completed_at": at,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (r *PrivacyRepo) GetExpiredExports(now time.Time) ([]model.DataExport, error) {
+	exports := make([]model.DataExport, 0)
+	return exports, r.db.Where("expires_at <= ?", now).Find(&exports).Error
+}
+
+func (r *PrivacyRepo) DeleteExport(id int64) error {
+	return r.db.Where("id = ?", id).Delete(&model.DataExport{}).Error
+}
+
+func (r *PrivacyRepo) GetUserReviews(userID int64) ([]model.ProductReview, error) {
+	reviews := make([]model.ProductReview, 0)
+	return reviews, r.db.Where("user_id = ?", userID).Order("date").Find(&reviews).Error
+}
+
+// GetUserSessions возвращает все сессии пользователя, включая завершенные
+func (r *PrivacyRepo) GetUserSessions(userID int64) ([]model.Session, error) {
+	sessions := make([]model.Session, 0)
+	return sessions, r.db.Where("user_id = ?", userID).Order("created_at").Find(&sessions).Error
+}
+
+// ScheduleDeletion назначает удаление; возвращает false, если оно уже назначено
+func (r *PrivacyRepo) ScheduleDeletion(userID int64, at time.Time) (bool, error) {
+	res := r.db.Model(&model.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NULL AND anonymized_at IS NULL", userID).
+		Update("deletion_scheduled_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// CancelDeletion отменяет удаление; возвращает false, если оно не было назначено
+func (r *PrivacyRepo) CancelDeletion(userID int64) (bool, error) {
+	res := r.db.Model(&model.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL AND anonymized_at IS NULL", userID).
+		Update("deletion_scheduled_at", nil)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *PrivacyRepo) GetUsersDueForErasure(now time.Time) ([]int64, error) {
+	ids := make([]int64, 0)
+	return ids, r.db.Model(&model.User{}).
+		Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", now).
+		Order("deletion_scheduled_at").
+		Pluck("id", &ids).Error
+}
+
+// GetSoleOwnedBusinesses возвращает бизнесы, у которых пользователь - единственный владелец
+func (r *PrivacyRepo) GetSoleOwnedBusinesses(userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Table("user_to_businesses AS m").
+		Joins("JOIN businesses b ON b.id = m.business_id").
+		Where("m.user_id = ? AND m.role = ?", userID, model.MemberOwner).
+		Where("NOT EXISTS (SELECT 1 FROM user_to_businesses o WHERE o.business_id = m.business_id AND o.role = ? AND o.user_id <> m.user_id)", model.MemberOwner).
+		Order("m.business_id").
+		Pluck("m.business_id", &ids).Error
+	return ids, err
+}
+
+// AnonymizeUser обезличивает пользователя. Строка users остается, чтобы заказы
+// и отзывы сохранили ссылку на покупателя; персональные данные, сессии, способы
+// входа и членство в бизнесах удаляются.
+func (r *PrivacyRepo) AnonymizeUser(userID int64, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Приглашения в бизнесы хранят адрес почты, удаляются до его замены
+		err := tx.Where("email = (?)", tx.Model(&model.User{}).Select("LOWER(email)").Where("id = ?", userID)).
+			Delete(&model.BusinessInvitation{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]any{
+			"name":                  model.AnonymizedName,
+			"surname":               model.AnonymizedSurname,
+			"patronymic":            "",
+			"email":                 fmt.Sprintf("deleted-%d@deleted.invalid", userID),
+			"password_hash":         "",
+			"date_of_birth":         time.Time{},
+			"is_email_verified":     false,
+			"phone":                 nil,
+			"is_phone_verified":     false,
+			"role":                  model.UserRole,
+			"is_pasport_verified":   false,
+			"inn":                   nil,
+			"deletion_scheduled_at": nil,
+			"anonymized_at":         now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// Заказы остаются для бухгалтерского учета. Из адреса доставки остаются
+		// регион, город и индекс: по ним считаются налоги и статистика продаж.
+		err = tx.Model(&model.Order{}).Where("user_id = ?", userID).Updates(map[string]any{
+			"address":                  "",
+			"delivery_street":          "",
+			"delivery_house":           "",
+			"delivery_apartment":       "",
+			"delivery_fias_id":         "",
+			"delivery_kladr_id":        "",
+			"delivery_latitude":        nil,
+			"delivery_longitude":       nil,
+			"delivery_recipient_name":  "",
+			"delivery_recipient_phone": "",
+		}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.ProductReview{}).Where("user_id = ?", userID).
+			Update("user_name", model.AnonymizedName+" "+model.AnonymizedSurname).Error
+		if err != nil {
+			return err
+		}
+		// Просмотры и корзины нужны аналитике продавцов, связь с пользователем - нет
+		err = tx.Model(&model.ProductEvent{}).Where("user_id = ?", userID).Update("user_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		owned := []any{
+			&model.CartItem{},
+			&model.RefreshToken{},
+			&model.Session{},
+			&model.UserMFA{},
+			&model.RecoveryCode{},
+			&model.VerificationCode{},
+			&model.EmailChange{},
+			&model.PhoneCode{},
+			&model.UserIdentity{},
+			&model.OAuthState{},
+			&model.UserToBusiness{},
+			&model.AuthEvent{},
+			&model.DataExport{},
+			&model.UserAddress{},
+		}
+		for _, m := range owned {
+			if err := tx.Where("user_id = ?", userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 )
 
+// productImageOrder - порядок вывода изображений продукта
+const productImageOrder = "position ASC, id ASC"
+
 // ProductRepo представляет репозиторий для работы с продуктами
 type ProductRepo struct {
 	db *gorm.DB
@@ -29,7 +32,7 @@ func (r *ProductRepo) GetProductByID(ctx context.Context, id int64) (*model.Prod
 
 	// Загружаем изображения
 	var images []model.ProductImage
-	if err := r.db.Where("product_id = ?", id).Find(&images).Error; err != nil {
+	if err := r.db.Where("product_id = ?", id).Order(productImageOrder).Find(&images).Error; err != nil {
 		return nil, err
 	}
 	product.Images = images
@@ -53,14 +56,14 @@ func (r *ProductRepo) GetProductByBusinessID(id int64) ([]model.Product, error)
 	for i := range products {
 		// Загружаем изображения
 		var images []model.ProductImage
-		if err := r.db.Where("product_id = ?", id).Find(&images).Error; err != nil {
+		if err := r.db.Where("product_id = ?", products[i].ID).Order(productImageOrder).Find(&images).Error; err != nil {
 			return nil, err
 		}
 		products[i].Images = images
 
 		// Загружаем характеристики
 		var specifications []model.ProductSpecification
-		if err := r.db.Where("product_id = ?", id).Find(&specifications).Error; err != nil {
+		if err := r.db.Where("product_id = ?", products[i].ID).Find(&specifications).Error; err != nil {
 			return nil, err
 		}
 		products[i].Specifications = specifications
@@ -88,6 +91,13 @@ func (r *ProductRepo) GetProductReviews(ctx context.Context, productID int64) ([
 	return reviews, nil
 }
 
+// GetUserName возвращает имя автора отзыва
+func (r *ProductRepo) GetUserName(userID int64) (string, error) {
+	var user model.User
+	err := r.db.Select("name").First(&user, userID).Error
+	return user.Name, err
+}
+
 // AddProductReview добавляет новый отзыв на продукт
 func (r *ProductRepo) AddProductReview(ctx context.Context, review model.ProductReview) (*model.ProductReview, error) {
 	if err := r.db.Create(&review).Error; err != nil {
@@ -98,7 +108,7 @@ func (r *ProductRepo) AddProductReview(ctx context.Context, review model.Product
 	var avgRating float64
 	if err := r.db.Model(&model.ProductReview{}).
 		Select("AVG(rating)").
-		Where("product_id = ?", review.ProductID).
+		Where("product_id = ? AND rating BETWEEN 1 AND 5", review.ProductID).
 		Scan(&avgRating).Error; err != nil {
 		return nil, err
 	}
@@ -126,8 +136,47 @@ func (r *ProductRepo) AddProductReview(ctx context.Context, review model.Product
 
 // FilterProducts фильтрует продукты по заданным критериям
 func (r *ProductRepo) FilterProducts(ctx context.Context, filters model.ProductQueryParams) ([]model.Product, error) {
-	query := r.db.Model(&model.Product{})
+	query := filterProducts(r.db.Model(&model.Product{}), filters).Order(productSortOrder(filters.SortBy))
+
+	var products []model.Product
+	if err := query.Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.loadProductImages(products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// FilterBusinessProducts возвращает страницу опубликованных товаров бизнеса
+// и общее число товаров, подходящих под фильтры
+func (r *ProductRepo) FilterBusinessProducts(ctx context.Context, businessID int64, filters model.ProductQueryParams) ([]model.Product, int64, error) {
+	// Session позволяет выполнить по одному запросу и подсчет, и выборку страницы
+	query := filterProducts(r.db.Model(&model.Product{}), filters).
+		Where("business_id = ?", businessID).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
+	var products []model.Product
+	err := query.Order(productSortOrder(filters.SortBy)).
+		Offset((filters.Page - 1) * filters.PageSize).Limit(filters.PageSize).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadProductImages(products); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// filterProducts применяет фильтры каталога; в выборку попадают только одобренные товары
+func filterProducts(query *gorm.DB, filters model.ProductQueryParams) *gorm.DB {
 	if filters.SearchQuery != "" {
 		query = query.Where("title LIKE ? OR description LIKE ?", "%"+filters.SearchQuery+"%", "%"+filters.SearchQuery+"%")
 	}
@@ -159,37 +208,35 @@ func (r *ProductRepo) FilterProducts(ctx context.Context, filters model.ProductQ
 		query = query.Where("discount > 0")
 	}
 
-	query = query.Where("status = ?", "approve")
+	return query.Where("status = ?", "approve")
+}
 
-	// Сортировка
-	switch filters.SortBy {
+// productSortOrder - сортировка каталога по параметру sort_by
+func productSortOrder(sortBy string) string {
+	switch sortBy {
 	case "price-asc":
-		query = query.Order("price ASC")
+		return "price ASC"
 	case "price-desc":
-		query = query.Order("price DESC")
+		return "price DESC"
 	case "rating":
-		query = query.Order("rating DESC")
+		return "rating DESC"
 	case "newest":
-		query = query.Order("created_at DESC")
+		return "created_at DESC"
 	default:
-		query = query.Order("id DESC")
-	}
-
-	var products []model.Product
-	if err := query.Find(&products).Error; err != nil {
-		return nil, err
+		return "id DESC"
 	}
+}
 
-	// Загружаем изображения для каждого продукта
+// loadProductImages загружает изображения для каждого продукта
+func (r *ProductRepo) loadProductImages(products []model.Product) error {
 	for i := range products {
 		var images []model.ProductImage
-		if err := r.db.Where("product_id = ?", products[i].ID).Find(&images).Error; err != nil {
-			return nil, err
+		if err := r.db.Where("product_id = ?", products[i].ID).Order(productImageOrder).Find(&images).Error; err != nil {
+			return err
 		}
 		products[i].Images = images
 	}
-
-	return products, nil
+	return nil
 }
 
 // CreateProduct создает новый продукт
@@ -271,23 +318,7 @@ func (r *ProductRepo) UpdateProduct(ctx context.Context, product model.Product)
 		return nil, err
 	}
 
-	// Обновляем изображения, если они есть
-	if len(product.Images) > 0 {
-		// Удаляем старые изображения
-		if err := tx.Where("product_id = ?", product.ID).Delete(&model.ProductImage{}).Error; err != nil {
-			tx.Rollback()
-			return nil, err
-		}
-
-		// Добавляем новые изображения
-		for i := range product.Images {
-			product.Images[i].ProductID = product.ID
-			if err := tx.Create(&product.Images[i]).Error; err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
-	}
+	// Изображения изменяются только через отдельные методы (AddProductImage, DeleteProductImage и т.д.)
 
 	// Обновляем характеристики, если они есть
 	if len(product.Specifications) > 0 {
@@ -371,6 +402,18 @@ func (r *ProductRepo) AddProductImage(ctx context.Context, image model.ProductIm
 		}
 	}
 
+	// Новое изображение добавляется в конец списка
+	var maxPosition *int
+	if err := r.db.Model(&model.ProductImage{}).
+		Select("MAX(position)").
+		Where("product_id = ?", image.ProductID).
+		Scan(&maxPosition).Error; err != nil {
+		return nil, err
+	}
+	if maxPosition != nil {
+		image.Position = *maxPosition + 1
+	}
+
 	// Создаем изображение
 	if err := r.db.Create(&image).Error; err != nil {
 		return nil, err
@@ -395,7 +438,7 @@ func (r *ProductRepo) DeleteProductImage(ctx context.Context, imageID int64) err
 	// Если удаленное изображение было основным, назначаем новое основное изображение
 	if image.IsPrimary {
 		var newPrimaryImage model.ProductImage
-		if err := r.db.Where("product_id = ?", image.ProductID).First(&newPrimaryImage).Error; err == nil {
+		if err := r.db.Where("product_id = ?", image.ProductID).Order(productImageOrder).First(&newPrimaryImage).Error; err == nil {
 			// Если нашли другое изображение, делаем его основным
 			if err := r.db.Model(&newPrimaryImage).Update("is_primary", true).Error; err != nil {
 				return err
@@ -409,12 +452,52 @@ func (r *ProductRepo) DeleteProductImage(ctx context.Context, imageID int64) err
 // GetProductImages возвращает изображения продукта
 func (r *ProductRepo) GetProductImages(ctx context.Context, productID int64) ([]model.ProductImage, error) {
 	var images []model.ProductImage
-	if err := r.db.Where("product_id = ?", productID).Find(&images).Error; err != nil {
+	if err := r.db.Where("product_id = ?", productID).Order(productImageOrder).Find(&images).Error; err != nil {
 		return nil, err
 	}
 	return images, nil
 }
 
+// GetProductImage возвращает изображение продукта по ID
+func (r *ProductRepo) GetProductImage(ctx context.Context, imageID int64) (model.ProductImage, error) {
+	var image model.ProductImage
+	return image, r.db.Where("id = ?", imageID).First(&image).Error
+}
+
+// SetPrimaryProductImage делает изображение основным, снимая флаг с остальных
+func (r *ProductRepo) SetPrimaryProductImage(ctx context.Context, productID, imageID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ProductImage{}).
+			Where("product_id = ?", productID).
+			Update("is_primary", false).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ProductImage{}).
+			Where("product_id = ? AND id = ?", productID, imageID).
+			Update("is_primary", true).Error
+	})
+}
+
+// ReorderProductImages устанавливает позиции изображений в порядке imageIDs
+func (r *ProductRepo) ReorderProductImages(ctx context.Context, productID int64, imageIDs []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range imageIDs {
+			if err := tx.Model(&model.ProductImage{}).
+				Where("product_id = ? AND id = ?", productID, id).
+				Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ProductRepo) GetReviewByID(reviewID int64) (model.ProductReview, error) {
+	var review model.ProductReview
+	return review, r.db.Where("id = ?", reviewID).First(&review).Error
+}
+
 func (r *ProductRepo) GetReviewImages(reviewID int64) ([]model.ReviewImages, error) {
 	var images []model.ReviewImages
 	if err := r.db.Where("review_id = ?", reviewID).Find(&images).Error; err != nil {
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// storefrontMediaColumns - колонки businesses с ключами изображений витрины
+var storefrontMediaColumns = map[model.StorefrontMedia]string{
+	model.StorefrontLogo:   "logo",
+	model.StorefrontBanner: "banner",
+}
+
+type StorefrontRepo struct {
+	db *gorm.DB
+}
+
+func NewStorefrontRepo(db *gorm.DB) *StorefrontRepo {
+	return &StorefrontRepo{db: db}
+}
+
+func (r *StorefrontRepo) GetBusinessBySlug(slug string) (model.Business, error) {
+	var business model.Business
+	return business, r.db.Where("slug = ?", slug).First(&business).Error
+}
+
+// UpdateStorefront меняет slug и описание; nil в значении очищает поле
+func (r *StorefrontRepo) UpdateStorefront(businessID int64, fields map[string]any) error {
+	return r.db.Model(&model.Business{}).Where("id = ?", businessID).Updates(fields).Error
+}
+
+// SetMedia сохраняет ключ изображения витрины (nil удаляет его) и возвращает
+// прежний ключ, чтобы вызывающий удалил старый объект из хранилища
+func (r *StorefrontRepo) SetMedia(businessID int64, media model.StorefrontMedia, key *string) (*string, error) {
+	column, ok := storefrontMediaColumns[media]
+	if !ok {
+		return nil, gorm.ErrInvalidField
+	}
+
+	var old *string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var business model.Business
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", column).
+			Where("id = ?", businessID).First(&business).Error
+		if err != nil {
+			return err
+		}
+		old = business.Logo
+		if media == model.StorefrontBanner {
+			old = business.Banner
+		}
+
+		return tx.Model(&model.Business{}).Where("id = ?", businessID).Update(column, key).Error
+	})
+	return old, err
+}
+
+// GetRating возвращает среднюю оценку и число отзывов по одобренным товарам бизнеса
+func (r *StorefrontRepo) GetRating(businessID int64) (float64, int64, error) {
+	var res struct {
+		Rating float64
+		Count  int64
+	}
+	err := r.db.Model(&model.ProductReview{}).
+		Select("COALESCE(AVG(product_reviews.rating), 0) AS rating, COUNT(product_reviews.id) AS count").
+		Joins("JOIN products ON products.id = product_reviews.product_id").
+		Where("products.business_id = ? AND products.status = ?", businessID, model.StatusApprove).
+		Where("product_reviews.rating BETWEEN 1 AND 5").
+		Scan(&res).Error
+	return res.Rating, res.Count, err
+}
+
+func (r *StorefrontRepo) CountProducts(businessID int64) (int64, error) {
+	var n int64
+	return n, r.db.Model(&model.Product{}).
+		Where("business_id = ? AND status = ?", businessID, model.StatusApprove).
+		Count(&n).Error
+}
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TokenRepo struct {
+	db *gorm.DB
+}
+
+func NewTokenRepo(db *gorm.DB) *TokenRepo {
+	return &TokenRepo{db: db}
+}
+
+func (r *TokenRepo) CreateRefreshToken(token model.RefreshToken) (model.RefreshToken, error) {
+	return token, r.db.Create(&token).Error
+}
+
+func (r *TokenRepo) GetRefreshTokenByHash(hash string) (model.RefreshToken, error) {
+	var token model.RefreshToken
+	return token, r.db.Where("token_hash = ?", hash).First(&token).Error
+}
+
+// MarkRefreshTokenUsed помечает токен использованным. Возвращает false, если
+// токен уже был использован или отозван другим запросом.
+func (r *TokenRepo) MarkRefreshTokenUsed(id string, at time.Time) (bool, error) {
+	res := r.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *TokenRepo) DeleteExpiredRefreshTokens(before time.Time) (int64, error) {
+	res := r.db.Where("expires_at < ?", before).Delete(&model.RefreshToken{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *TokenRepo) CreateSession(session model.Session) (model.Session, error) {
+	return session, r.db.Create(&session).Error
+}
+
+func (r *TokenRepo) GetSession(id string) (model.Session, error) {
+	var session model.Session
+	return session, r.db.Where("id = ?", id).First(&session).Error
+}
+
+// TouchSession обновляет время последней активности, адрес и срок сессии при обновлении токена
+func (r *TokenRepo) TouchSession(id string, ip, location string, at, expiresAt time.Time) error {
+	return r.db.Model(&model.Session{}).Where("id = ?", id).Updates(map[string]any{
+		"ip":           ip,
+		"location":     location,
+		"last_seen_at": at,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (r *TokenRepo) GetActiveSessions(userID int64, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	return sessions, r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+}
+
+func (r *TokenRepo) CountSessions(userID int64) (int64, error) {
+	var count int64
+	return count, r.db.Model(&model.Session{}).Where("user_id = ?", userID).Count(&count).Error
+}
+
+func (r *TokenRepo) DeviceKnown(userID int64, deviceHash string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Session{}).
+		Where("user_id = ? AND device_hash = ?", userID, deviceHash).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RevokeSession отзывает сессию и все refresh-токены ее семейства
+func (r *TokenRepo) RevokeSession(id string, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Session{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", at).Error
+	})
+}
+
+func (r *TokenRepo) RevokeUserSessions(userID int64, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", at).Error
+	})
+}
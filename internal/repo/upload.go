@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type UploadRepo struct {
+	db *gorm.DB
+}
+
+func NewUploadRepo(db *gorm.DB) *UploadRepo {
+	return &UploadRepo{db: db}
+}
+
+func (r *UploadRepo) CreateUpload(upload model.Upload) (model.Upload, error) {
+	return upload, r.db.Create(&upload).Error
+}
+
+func (r *UploadRepo) GetUpload(id string) (model.Upload, error) {
+	var upload model.Upload
+	return upload, r.db.Where("id = ?", id).First(&upload).Error
+}
+
+// UpdateUploadStatus переводит загрузку из статуса from в to. Возвращает false,
+// если статус уже изменил другой запрос.
+func (r *UploadRepo) UpdateUploadStatus(id string, from, to model.UploadStatus) (bool, error) {
+	res := r.db.Model(&model.Upload{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return res.RowsAffected > 0, res.Error
+}
+
+// GetExpiredPendingUploads возвращает неподтвержденные загрузки, истекшие до before,
+// и загрузки, зависшие в обработке дольше stuckAfter после истечения
+func (r *UploadRepo) GetExpiredPendingUploads(before time.Time, stuckAfter time.Duration, limit int) ([]model.Upload, error) {
+	var uploads []model.Upload
+	return uploads, r.db.
+		Where("(status = ? AND expires_at < ?) OR (status = ? AND expires_at < ?)",
+			model.UploadStatusPending, before, model.UploadStatusProcessing, before.Add(-stuckAfter)).
+		Limit(limit).
+		Find(&uploads).Error
+}
+
+func (r *UploadRepo) DeleteUpload(id string) error {
+	return r.db.Where("id = ?", id).Delete(&model.Upload{}).Error
+}
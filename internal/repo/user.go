@@ -21,15 +21,25 @@ func (r *UserRepo) CreateUser(user model.User) (model.User, error) {
 	return user, r.db.Create(&user).Error
 }
 
-func (r *UserRepo) DeleteUser(id int64) error {
-	return r.db.Where("id = ?", id).Delete(&model.User{}).Error
-}
-
 func (r *UserRepo) GetUserByEmail(email string) (model.User, error) {
 	var user model.User
 	return user, r.db.Where("email = ?", email).First(&user).Error
 }
 
+// GetUserByPhone ищет пользователя по подтвержденному номеру
+func (r *UserRepo) GetUserByPhone(phone string) (model.User, error) {
+	var user model.User
+	return user, r.db.Where("phone = ? AND is_phone_verified", phone).First(&user).Error
+}
+
+// SetPhone привязывает подтвержденный номер; nil отвязывает номер
+func (r *UserRepo) SetPhone(userID int64, phone *string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"phone":             phone,
+		"is_phone_verified": phone != nil,
+	}).Error
+}
+
 func (r *UserRepo) GetUserByID(id int64) (model.User, error) {
 	var user model.User
 	return user, r.db.Where("id = ?", id).First(&user).Error
@@ -63,15 +73,28 @@ func (r *UserRepo) CreateVerificationCode(code string, expiredAt time.Time, user
 	return verifCode, r.db.Create(&verifCode).Error
 }
 
-func (r *UserRepo) GetVerificationCode(userID int64, code string) (model.VerificationCode, error) {
+// GetLatestVerificationCode возвращает последний отправленный пользователю код
+func (r *UserRepo) GetLatestVerificationCode(userID int64) (model.VerificationCode, error) {
 	var verifCode model.VerificationCode
-	return verifCode, r.db.Where("user_id = ? AND code = ?", userID, code).
-		Order("expired_at DESC").
+	return verifCode, r.db.Where("user_id = ?", userID).
+		Order("sent_at DESC, id DESC").
 		First(&verifCode).Error
 }
 
-func (r *UserRepo) DeleteVerificationCode(code model.VerificationCode) error {
-	return r.db.Delete(&code).Error
+// IncrementVerificationAttempts увеличивает счетчик неверных попыток и возвращает новое значение
+func (r *UserRepo) IncrementVerificationAttempts(id int64) (int, error) {
+	var attempts int
+	return attempts, r.db.Raw("UPDATE verification_codes SET attempts = attempts + 1 WHERE id = ? RETURNING attempts", id).
+		Scan(&attempts).Error
+}
+
+func (r *UserRepo) DeleteVerificationCodes(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.VerificationCode{}).Error
+}
+
+func (r *UserRepo) DeleteExpiredVerificationCodes(now time.Time) (int64, error) {
+	res := r.db.Where("expired_at <= ?", now).Delete(&model.VerificationCode{})
+	return res.RowsAffected, res.Error
 }
 
 func (r *UserRepo) VerifyEmail(userID int64) error {
@@ -82,6 +105,104 @@ func (r *UserRepo) SetPassword(userID int64, passwordHash string) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error
 }
 
-func (r *UserRepo) UpdateUser(userID int64, user model.User) error {
-	return r.db.Model(&model.User{}).Where("id = ?", userID).Updates(user).Error
+// ReplacePasswordHash обновляет хеш, только если пароль не сменили параллельно
+func (r *UserRepo) ReplacePasswordHash(userID int64, oldHash, newHash string) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ? AND password_hash = ?", userID, oldHash).
+		Update("password_hash", newHash).Error
+}
+
+// UpdateUser обновляет только перечисленные поля профиля
+func (r *UserRepo) UpdateUser(userID int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Updates(fields).Error
+}
+
+// CreateEmailChange сохраняет запрос на смену почты; прежние неподтвержденные запросы отменяются
+func (r *UserRepo) CreateEmailChange(change model.EmailChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND confirmed_at IS NULL", change.UserID).Delete(&model.EmailChange{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&change).Error
+	})
+}
+
+// GetPendingEmailChange возвращает последний неподтвержденный запрос на смену почты
+func (r *UserRepo) GetPendingEmailChange(userID int64) (model.EmailChange, error) {
+	var change model.EmailChange
+	return change, r.db.Where("user_id = ? AND confirmed_at IS NULL", userID).Order("id DESC").First(&change).Error
+}
+
+// IncrementEmailChangeAttempts увеличивает счетчик неверных попыток и возвращает новое значение
+func (r *UserRepo) IncrementEmailChangeAttempts(id int64) (int, error) {
+	var attempts int
+	return attempts, r.db.Raw("UPDATE email_changes SET code_attempts = code_attempts + 1 WHERE id = ? RETURNING code_attempts", id).
+		Scan(&attempts).Error
+}
+
+func (r *UserRepo) DeleteEmailChange(id int64) error {
+	return r.db.Where("id = ?", id).Delete(&model.EmailChange{}).Error
+}
+
+// ConfirmEmailChange меняет почту на подтвержденный адрес и отзывает старые коды.
+// Условие на confirmed_at не дает применить один запрос дважды.
+func (r *UserRepo) ConfirmEmailChange(change model.EmailChange, at time.Time) (bool, error) {
+	confirmed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.EmailChange{}).
+			Where("id = ? AND confirmed_at IS NULL", change.ID).
+			Updates(map[string]any{
+				"confirmed_at":      at,
+				"revert_token_hash": change.RevertTokenHash,
+				"revert_expires_at": change.RevertExpiresAt,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		err := tx.Model(&model.User{}).Where("id = ?", change.UserID).
+			Updates(map[string]any{"email": change.NewEmail, "is_email_verified": true}).Error
+		if err != nil {
+			return err
+		}
+		confirmed = true
+		return tx.Where("user_id = ?", change.UserID).Delete(&model.VerificationCode{}).Error
+	})
+	return confirmed, err
+}
+
+func (r *UserRepo) GetEmailChangeByRevertHash(hash string) (model.EmailChange, error) {
+	var change model.EmailChange
+	return change, r.db.Where("revert_token_hash = ?", hash).First(&change).Error
+}
+
+// RevertEmailChange возвращает прежнюю почту. Условие на reverted_at не дает
+// применить одну ссылку дважды.
+func (r *UserRepo) RevertEmailChange(change model.EmailChange, at time.Time) (bool, error) {
+	reverted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.EmailChange{}).
+			Where("id = ? AND reverted_at IS NULL", change.ID).
+			Update("reverted_at", at)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		err := tx.Model(&model.User{}).Where("id = ?", change.UserID).
+			Updates(map[string]any{"email": change.OldEmail, "is_email_verified": change.OldEmailVerified}).Error
+		if err != nil {
+			return err
+		}
+		reverted = true
+		return tx.Where("user_id = ?", change.UserID).Delete(&model.VerificationCode{}).Error
+	})
+	return reverted, err
+}
+
+func (r *UserRepo) SetRole(userID int64, role model.UserRoleType) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("role", role).Error
 }
@@ -0,0 +1,86 @@
+// Package requisites validates Russian company requisites: INN and OGRN/OGRNIP.
+package requisites
+
+import "fmt"
+
+var (
+	inn10Weights = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	inn11Weights = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	inn12Weights = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+// ValidINN проверяет ИНН по контрольным цифрам: 10 цифр у организации, 12 - у ИП и физлица
+func ValidINN(inn string) bool {
+	d, ok := digits(inn)
+	if !ok {
+		return false
+	}
+
+	switch len(d) {
+	case 10:
+		return innChecksum(d, inn10Weights) == d[9]
+	case 12:
+		return innChecksum(d, inn11Weights) == d[10] && innChecksum(d, inn12Weights) == d[11]
+	default:
+		return false
+	}
+}
+
+// ValidOGRN проверяет ОГРН (13 цифр) или ОГРНИП (15 цифр) по контрольной цифре:
+// это последняя цифра остатка от деления остальных цифр на 11 и 13 соответственно
+func ValidOGRN(ogrn string) bool {
+	d, ok := digits(ogrn)
+	if !ok {
+		return false
+	}
+
+	var mod int64
+	switch len(d) {
+	case 13:
+		mod = 11
+	case 15:
+		mod = 13
+	default:
+		return false
+	}
+
+	var n int64
+	for _, v := range d[:len(d)-1] {
+		n = n*10 + int64(v)
+	}
+	return int(n%mod%10) == d[len(d)-1]
+}
+
+// Matches сообщает, соответствуют ли ИНН и ОГРН одному типу лица:
+// у организации 10-значный ИНН и ОГРН, у ИП - 12-значный ИНН и ОГРНИП
+func Matches(inn, ogrn string) bool {
+	return len(inn) == 10 && len(ogrn) == 13 || len(inn) == 12 && len(ogrn) == 15
+}
+
+// FormatINN восстанавливает ведущие нули ИНН, потерянные при хранении числом.
+// 12-значный ИНН с нулем в начале больше 10^10, поэтому длины не путаются.
+func FormatINN(inn int64) string {
+	if inn < 1e10 {
+		return fmt.Sprintf("%010d", inn)
+	}
+	return fmt.Sprintf("%012d", inn)
+}
+
+func innChecksum(d []int, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += d[i] * w
+	}
+	return sum % 11 % 10
+}
+
+func digits(s string) ([]int, bool) {
+	d := make([]int, 0, len(s))
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		d = append(d, int(r-'0'))
+	}
+	return d, len(d) > 0
+}
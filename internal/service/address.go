@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/address"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/sms"
+	"gorm.io/gorm"
+	"strings"
+)
+
+var (
+	ErrAddressNotFound  = errors.New("address not found")
+	ErrTooManyAddresses = errors.New("too many saved addresses")
+	ErrAddressRequired  = errors.New("address or address_id is required")
+	// ErrAddressNotRecognized - адрес не удалось разобрать, его нужно уточнить
+	ErrAddressNotRecognized = address.ErrNotRecognized
+)
+
+// maxUserAddresses - сколько адресов можно сохранить в профиле
+const maxUserAddresses = 20
+
+// AddressService - адресная книга пользователя, подсказки и стандартизация адресов
+type AddressService struct {
+	repo     *repo.AddressRepo
+	provider address.Provider
+}
+
+func NewAddressService(repo *repo.AddressRepo, provider address.Provider) *AddressService {
+	return &AddressService{repo: repo, provider: provider}
+}
+
+// Suggest возвращает подсказки для введенной части адреса
+func (s *AddressService) Suggest(ctx context.Context, query string, count int) ([]model.AddressSuggestion, error) {
+	if count < 1 || count > address.MaxSuggestions {
+		count = address.MaxSuggestions / 2
+	}
+	return s.provider.Suggest(ctx, query, count)
+}
+
+// Normalize приводит адрес, введенный строкой, к стандартному виду
+func (s *AddressService) Normalize(ctx context.Context, raw string) (model.AddressSuggestion, error) {
+	return s.provider.Normalize(ctx, raw)
+}
+
+func (s *AddressService) GetAddresses(userID int64) ([]model.UserAddress, error) {
+	return s.repo.GetUserAddresses(userID)
+}
+
+func (s *AddressService) GetAddress(userID, id int64) (model.UserAddress, error) {
+	address, err := s.repo.GetUserAddress(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserAddress{}, ErrAddressNotFound
+	}
+	return address, err
+}
+
+func (s *AddressService) CreateAddress(userID int64, input model.UserAddressInput) (model.UserAddress, error) {
+	address, err := makeUserAddress(userID, input)
+	if err != nil {
+		return model.UserAddress{}, err
+	}
+
+	count, err := s.repo.CountUserAddresses(userID)
+	if err != nil {
+		return model.UserAddress{}, err
+	}
+	if count >= maxUserAddresses {
+		return model.UserAddress{}, ErrTooManyAddresses
+	}
+
+	return s.repo.CreateAddress(address)
+}
+
+func (s *AddressService) UpdateAddress(userID, id int64, input model.UserAddressInput) (model.UserAddress, error) {
+	address, err := makeUserAddress(userID, input)
+	if err != nil {
+		return model.UserAddress{}, err
+	}
+	address.ID = id
+
+	address, err = s.repo.UpdateAddress(address)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserAddress{}, ErrAddressNotFound
+	}
+	return address, err
+}
+
+func (s *AddressService) SetDefaultAddress(userID, id int64) error {
+	err := s.repo.SetDefaultAddress(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAddressNotFound
+	}
+	return err
+}
+
+func (s *AddressService) DeleteAddress(userID, id int64) error {
+	err := s.repo.DeleteAddress(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAddressNotFound
+	}
+	return err
+}
+
+func makeUserAddress(userID int64, input model.UserAddressInput) (model.UserAddress, error) {
+	phone, err := sms.NormalizePhone(input.RecipientPhone)
+	if err != nil {
+		return model.UserAddress{}, err
+	}
+
+	return model.UserAddress{
+		UserID: userID,
+		Label:  strings.TrimSpace(input.Label),
+		AddressFields: model.AddressFields{
+			Region:         strings.TrimSpace(input.Region),
+			City:           strings.TrimSpace(input.City),
+			Street:         strings.TrimSpace(input.Street),
+			House:          strings.TrimSpace(input.House),
+			Apartment:      strings.TrimSpace(input.Apartment),
+			Postcode:       input.Postcode,
+			FiasID:         strings.ToLower(input.FiasID),
+			KladrID:        input.KladrID,
+			Latitude:       input.Latitude,
+			Longitude:      input.Longitude,
+			RecipientName:  strings.TrimSpace(input.RecipientName),
+			RecipientPhone: phone,
+		},
+		IsDefault: input.IsDefault,
+	}, nil
+}
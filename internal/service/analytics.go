@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidAnalyticsDate  = errors.New("dates must be in YYYY-MM-DD format")
+	ErrInvalidAnalyticsRange = errors.New("from must not be after to and the range must not exceed 366 days")
+)
+
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+	analyticsDefaultTop  = 10
+)
+
+// AnalyticsService - аналитика продаж для продавцов. Отчеты строятся по дневным
+// агрегатам, которые периодически пересчитывает Rollup, поэтому свежие продажи
+// появляются в отчете с задержкой до интервала свертки.
+type AnalyticsService struct {
+	repo *repo.AnalyticsRepo
+	cfg  config.AnalyticsConfig
+	loc  *time.Location
+}
+
+func NewAnalyticsService(repo *repo.AnalyticsRepo, cfg config.AnalyticsConfig) *AnalyticsService {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		slog.Error("unknown analytics time zone, using UTC", slog.String("tz", cfg.TimeZone), sl.Err(err))
+		loc, cfg.TimeZone = time.UTC, "UTC"
+	}
+	return &AnalyticsService{
+		repo: repo,
+		cfg:  cfg,
+		loc:  loc,
+	}
+}
+
+// Track записывает просмотр товара или добавление в корзину. Товар передает
+// вызывающий, он уже загружен обработчиком. Ошибки только логируются: аналитика
+// не должна мешать покупателю. Учитываются только опубликованные товары;
+// userID 0 - анонимный покупатель.
+func (s *AnalyticsService) Track(kind model.ProductEventKind, product *model.Product, userID int64) {
+	if product.Status != model.StatusApprove {
+		return
+	}
+
+	event := model.ProductEvent{
+		ProductID:  product.ID,
+		BusinessID: product.BusinessID,
+		Kind:       kind,
+	}
+	if userID != 0 {
+		event.UserID = &userID
+	}
+	if err := s.repo.RecordEvent(event); err != nil {
+		slog.Warn("cannot record analytics event", slog.Int64("product_id", product.ID), sl.Err(err))
+	}
+}
+
+// Rollup пересчитывает агрегаты за последние Lookback. При первом запуске
+// собирает всю историю заказов и событий.
+func (s *AnalyticsService) Rollup(ctx context.Context) error {
+	now := time.Now()
+	from := now.Add(-s.cfg.Lookback)
+
+	last, err := s.repo.LastRollup()
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		first, err := s.repo.FirstActivity()
+		if err != nil {
+			return err
+		}
+		if first != nil && first.Before(from) {
+			from = *first
+		}
+	}
+
+	if err := s.repo.Rollup(s.day(from), s.cfg.TimeZone, now); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteEventsBefore(now.Add(-s.cfg.EventRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Info("old analytics events deleted", slog.Int64("count", deleted))
+	}
+	return nil
+}
+
+// day возвращает календарный день момента t в часовом поясе аналитики
+func (s *AnalyticsService) day(t time.Time) model.DateOnly {
+	t = t.In(s.loc)
+	return model.DateOnly{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// GetBusinessAnalytics строит отчет по продажам бизнеса за период query
+func (s *AnalyticsService) GetBusinessAnalytics(businessID int64, query model.AnalyticsQuery) (model.BusinessAnalytics, error) {
+	today := s.day(time.Now())
+	from, to, err := analyticsRange(query, today)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+	interval := query.Interval
+	if interval == "" {
+		interval = model.AnalyticsDay
+	}
+	top := query.Top
+	if top == 0 {
+		top = analyticsDefaultTop
+	}
+
+	totals, err := s.repo.GetTotals(businessID, from, to)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+	totals.AverageOrderValue = averageOrderValue(totals.Revenue, totals.Orders)
+
+	buckets, err := s.repo.GetSeries(businessID, from, to, interval)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+
+	products, err := s.repo.GetTopProducts(businessID, from, to, top)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+	if products == nil {
+		products = []model.TopProduct{}
+	}
+
+	funnel, err := s.repo.GetFunnel(businessID, from, to)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+	funnel.ViewToCart = conversion(funnel.CartAdds, funnel.Views)
+	funnel.CartToOrder = conversion(funnel.Orders, funnel.CartAdds)
+	funnel.ViewToOrder = conversion(funnel.Orders, funnel.Views)
+
+	forecast, err := s.stockForecast(businessID, today)
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+
+	updatedAt, err := s.repo.LastRollup()
+	if err != nil {
+		return model.BusinessAnalytics{}, err
+	}
+
+	return model.BusinessAnalytics{
+		From:          from,
+		To:            to,
+		Interval:      interval,
+		Totals:        totals,
+		Series:        fillSeries(buckets, from, to, interval),
+		TopProducts:   products,
+		Funnel:        funnel,
+		StockForecast: forecast,
+		UpdatedAt:     updatedAt,
+	}, nil
+}
+
+// analyticsRange разбирает период отчета; по умолчанию - последние 30 дней
+func analyticsRange(query model.AnalyticsQuery, today model.DateOnly) (model.DateOnly, model.DateOnly, error) {
+	to := today
+	if query.To != "" {
+		t, err := time.Parse(time.DateOnly, query.To)
+		if err != nil {
+			return model.DateOnly{}, model.DateOnly{}, ErrInvalidAnalyticsDate
+		}
+		to = model.DateOnly{Time: t}
+	}
+	from := model.DateOnly{Time: to.AddDate(0, 0, -(analyticsDefaultDays - 1))}
+	if query.From != "" {
+		t, err := time.Parse(time.DateOnly, query.From)
+		if err != nil {
+			return model.DateOnly{}, model.DateOnly{}, ErrInvalidAnalyticsDate
+		}
+		from = model.DateOnly{Time: t}
+	}
+
+	if from.After(to.Time) || to.Sub(from.Time) >= analyticsMaxDays*24*time.Hour {
+		return model.DateOnly{}, model.DateOnly{}, ErrInvalidAnalyticsRange
+	}
+	return from, to, nil
+}
+
+// fillSeries дополняет ряд пустыми интервалами, чтобы на графике не было разрывов
+func fillSeries(buckets []model.AnalyticsBucket, from, to model.DateOnly, interval model.AnalyticsInterval) []model.AnalyticsBucket {
+	byStart := make(map[string]model.AnalyticsBucket, len(buckets))
+	for _, b := range buckets {
+		byStart[b.Start.Format(time.DateOnly)] = b
+	}
+
+	series := make([]model.AnalyticsBucket, 0)
+	for start := truncateDay(from.Time, interval); !start.After(to.Time); start = nextBucket(start, interval) {
+		b := byStart[start.Format(time.DateOnly)]
+		b.Start = model.DateOnly{Time: start}
+		b.AverageOrderValue = averageOrderValue(b.Revenue, b.Orders)
+		series = append(series, b)
+	}
+	return series
+}
+
+// truncateDay возвращает начало интервала, как date_trunc в Postgres: неделя начинается с понедельника
+func truncateDay(t time.Time, interval model.AnalyticsInterval) time.Time {
+	switch interval {
+	case model.AnalyticsWeek:
+		return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	case model.AnalyticsMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return t
+	}
+}
+
+func nextBucket(t time.Time, interval model.AnalyticsInterval) time.Time {
+	switch interval {
+	case model.AnalyticsWeek:
+		return t.AddDate(0, 0, 7)
+	case model.AnalyticsMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// stockForecast оценивает, через сколько дней закончится остаток каждого товара
+// при среднем темпе продаж за последние ForecastWindow дней. Первыми идут
+// товары, которые закончатся раньше; товары без продаж - в конце.
+func (s *AnalyticsService) stockForecast(businessID int64, today model.DateOnly) ([]model.StockForecast, error) {
+	window := max(s.cfg.ForecastWindow, 1)
+	since := model.DateOnly{Time: today.AddDate(0, 0, -(window - 1))}
+
+	sales, err := s.repo.GetProductSales(businessID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := make([]model.StockForecast, 0, len(sales))
+	for _, p := range sales {
+		f := model.StockForecast{
+			ProductID:  p.ProductID,
+			Title:      p.Title,
+			Quantity:   p.Quantity,
+			DailyUnits: math.Round(float64(p.Units)/float64(window)*100) / 100,
+		}
+		if p.Units > 0 {
+			days := math.Round(float64(max(p.Quantity, 0))/(float64(p.Units)/float64(window))*10) / 10
+			date := model.DateOnly{Time: today.AddDate(0, 0, int(days))}
+			f.DaysLeft, f.StockOutDate = &days, &date
+		}
+		forecast = append(forecast, f)
+	}
+
+	sort.SliceStable(forecast, func(i, j int) bool {
+		a, b := forecast[i].DaysLeft, forecast[j].DaysLeft
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+	return forecast, nil
+}
+
+// averageOrderValue - средний чек, округленный до копеек
+func averageOrderValue(revenue float64, orders int64) float64 {
+	if orders == 0 {
+		return 0
+	}
+	return math.Round(revenue/float64(orders)*100) / 100
+}
+
+// conversion - доля шагов воронки с точностью до сотой процента
+func conversion(to, from int64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return math.Round(float64(to)/float64(from)*10000) / 10000
+}
@@ -1,111 +1,110 @@
-//package service
-//
-//import (
-//	"bytes"
-//	"encoding/json"
-//	"fmt"
-//	"github.com/RCSE2025/backend-go/internal/repo"
-//	"io/ioutil"
-//	"net/http"
-//)
-//
-//type BusinessService struct {
-//	repo *repo.BusinessRepo
-//}
-//
-//func NewBusinessService(repo *repo.BusinessRepo) *BusinessService {
-//	return &BusinessService{
-//		repo: repo,
-//	}
-//}
-//
-
 package service
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
+	"github.com/RCSE2025/backend-go/internal/integrations/dadata"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/repo"
-	"io/ioutil"
-	"net/http"
+	"github.com/RCSE2025/backend-go/internal/requisites"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"log/slog"
+	"strconv"
 )
 
 var (
 	ErrBusinessNotFound = errors.New("business not found")
 	ErrBusinessExists   = errors.New("business already exists")
+	ErrInvalidINN       = dadata.ErrInvalidINN
+	ErrInvalidOGRN      = errors.New("invalid ogrn")
+	// ErrRequisitesLocked - ИНН и ОГРН нельзя менять, пока бизнес на проверке или подтвержден
+	ErrRequisitesLocked = errors.New("inn and ogrn cannot be changed once verification is requested")
+	// ErrBusinessInfoNotFound - DaData не знает организацию с таким ИНН
+	ErrBusinessInfoNotFound = dadata.ErrPartyNotFound
+	// ErrBusinessInfoUnavailable - интеграция с DaData не настроена
+	ErrBusinessInfoUnavailable = dadata.ErrNotConfigured
 )
 
 type BusinessService struct {
 	repo     *repo.BusinessRepo
 	userRepo *repo.UserRepo
+	dadata   *dadata.Client
 }
 
-func NewBusinessService(repo *repo.BusinessRepo, userRepo *repo.UserRepo) *BusinessService {
+func NewBusinessService(repo *repo.BusinessRepo, userRepo *repo.UserRepo, dadata *dadata.Client) *BusinessService {
 	return &BusinessService{
 		repo:     repo,
 		userRepo: userRepo,
+		dadata:   dadata,
 	}
 }
 
-const (
-	url   = "http://suggestions.dadata.ru/suggestions/api/4_1/rs/findById/party"
-	token = "a88bd145b3272ed1e6876a27d2d592d8bb473865"
-)
-
-type Request struct {
-	Query      string `json:"query"`
-	BranchType string `json:"branch_type"`
+// GetBusinessInfoByINN возвращает сведения об организации или ИП из ЕГРЮЛ/ЕГРИП
+func (s *BusinessService) GetBusinessInfoByINN(ctx context.Context, inn string) (dadata.Party, error) {
+	return s.dadata.FindPartyByINN(ctx, inn)
 }
 
-func (s *BusinessService) GetBusinessInfoByINN(inn string) (any, error) {
-	reqData := Request{
-		Query:      inn,
-		BranchType: "MAIN",
+// CreateBusiness создает бизнес и привязывает к нему пользователя. Незаполненные
+// ОГРН, названия, адрес и владелец берутся из ЕГРЮЛ; если DaData недоступна,
+// бизнес создается с тем, что ввел пользователь.
+func (s *BusinessService) CreateBusiness(ctx context.Context, userID int64, business model.Business) (model.Business, error) {
+	if err := validateRequisites(business); err != nil {
+		return model.Business{}, err
 	}
-
-	jsonData, err := json.Marshal(reqData)
-	if err != nil {
-		return nil, err
+	if _, err := s.repo.GetBusinessByINN(business.INN); err == nil {
+		return model.Business{}, ErrBusinessExists
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
+	party, err := s.dadata.FindPartyByINN(ctx, requisites.FormatINN(business.INN))
+	switch {
+	case errors.Is(err, ErrInvalidINN):
+		return model.Business{}, err
+	case err != nil && !errors.Is(err, ErrBusinessInfoUnavailable):
+		slog.Warn("cannot get business info", slog.Int64("inn", business.INN), sl.Err(err))
+	case err == nil:
+		fillBusiness(&business, party)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Token "+token)
+	business.VerificationStatus = model.BusinessUnverified
+	business.VerifiedAt = nil
+	clearStorefront(&business)
+	return s.repo.CreateBusiness(userID, business)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// validateRequisites проверяет контрольные цифры ИНН и ОГРН и то, что они
+// относятся к одному типу лица
+func validateRequisites(b model.Business) error {
+	inn := requisites.FormatINN(b.INN)
+	if b.INN <= 0 || !requisites.ValidINN(inn) {
+		return ErrInvalidINN
 	}
-	defer resp.Body.Close()
-
-	// Читаем ответ
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if b.OGRN == nil {
+		return nil
 	}
 
-	fmt.Println(body)
-
-	var response any
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
+	ogrn := strconv.FormatInt(*b.OGRN, 10)
+	if !requisites.ValidOGRN(ogrn) || !requisites.Matches(inn, ogrn) {
+		return ErrInvalidOGRN
 	}
-
-	return response, nil
+	return nil
 }
 
-func (s *BusinessService) CreateBusiness(userID int64, business model.Business) error {
-	return s.repo.CreateBusiness(userID, business)
+// fillBusiness заполняет пустые поля бизнеса сведениями из ЕГРЮЛ, не трогая введенные
+func fillBusiness(b *model.Business, p dadata.Party) {
+	if b.OGRN == nil && p.OGRN != "" {
+		if ogrn, err := strconv.ParseInt(p.OGRN, 10, 64); err == nil {
+			b.OGRN = &ogrn
+		}
+	}
+	fill := func(dst **string, v string) {
+		if (*dst == nil || **dst == "") && v != "" {
+			*dst = &v
+		}
+	}
+	fill(&b.ShortName, p.ShortName)
+	fill(&b.FullName, p.FullName)
+	fill(&b.Address, p.Address)
+	fill(&b.Owner, p.Manager)
 }
 
 func (s *BusinessService) GetAllBusinesses() ([]model.Business, error) {
@@ -135,9 +134,50 @@ func (s *BusinessService) UpdateBusiness(id int64, business model.Business) erro
 		return ErrBusinessNotFound
 	}
 
+	existing, err := s.repo.GetBusinessByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Статус проверки меняется только через заявку, Updates пропускает нулевые поля
+	business.VerificationStatus = ""
+	business.VerifiedAt = nil
+	clearStorefront(&business)
+
+	merged := existing
+	if business.INN != 0 {
+		merged.INN = business.INN
+	}
+	if business.OGRN != nil {
+		merged.OGRN = business.OGRN
+	}
+	if merged.INN != existing.INN || !sameOGRN(merged.OGRN, existing.OGRN) {
+		if existing.VerificationStatus == model.BusinessPending || existing.VerificationStatus == model.BusinessVerified {
+			return ErrRequisitesLocked
+		}
+		if err := validateRequisites(merged); err != nil {
+			return err
+		}
+	}
+
 	return s.repo.UpdateBusiness(id, business)
 }
 
+// clearStorefront сбрасывает поля витрины: они меняются через StorefrontService
+func clearStorefront(b *model.Business) {
+	b.Slug = nil
+	b.Description = nil
+	b.Logo = nil
+	b.Banner = nil
+}
+
+func sameOGRN(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (s *BusinessService) DeleteBusiness(id int64) error {
 	exists, err := s.repo.BusinessExists(id)
 	if err != nil {
@@ -194,47 +234,3 @@ func (s *BusinessService) GetBusinessUsers(businessID int64) ([]model.User, erro
 
 	return s.repo.GetBusinessesUsers(businessID)
 }
-
-func (s *BusinessService) AddUserToBusiness(userID int64, businessID int64) error {
-	userExists, err := s.userRepo.UserExists(userID)
-	if err != nil {
-		return err
-	}
-
-	if !userExists {
-		return ErrUserNotFound
-	}
-
-	businessExists, err := s.repo.BusinessExists(businessID)
-	if err != nil {
-		return err
-	}
-
-	if !businessExists {
-		return ErrBusinessNotFound
-	}
-
-	return s.repo.AddUserToBusiness(userID, businessID)
-}
-
-func (s *BusinessService) RemoveUserFromBusiness(userID int64, businessID int64) error {
-	userExists, err := s.userRepo.UserExists(userID)
-	if err != nil {
-		return err
-	}
-
-	if !userExists {
-		return ErrUserNotFound
-	}
-
-	businessExists, err := s.repo.BusinessExists(businessID)
-	if err != nil {
-		return err
-	}
-
-	if !businessExists {
-		return ErrBusinessNotFound
-	}
-
-	return s.repo.RemoveUserFromBusiness(userID, businessID)
-}
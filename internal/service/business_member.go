@@ -0,0 +1,390 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/email"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/requisites"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"html/template"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMemberNotFound = errors.New("business member not found")
+	ErrAlreadyMember  = errors.New("user is already a member of the business")
+	// ErrLastOwner - у бизнеса всегда должен оставаться владелец
+	ErrLastOwner           = errors.New("business must have at least one owner")
+	ErrOwnershipToSelf     = errors.New("cannot transfer ownership to yourself")
+	ErrInvitationNotFound  = errors.New("invitation not found")
+	ErrInvitationExpired   = errors.New("invitation has expired or was already answered")
+	ErrInvitationForbidden = errors.New("invitation was sent to another email")
+)
+
+// memberRoleNames - названия ролей для писем
+var memberRoleNames = map[model.MemberRole]string{
+	model.MemberOwner:         "владелец",
+	model.MemberManager:       "менеджер",
+	model.MemberContentEditor: "редактор товаров",
+	model.MemberAccountant:    "бухгалтер",
+}
+
+// BusinessMemberService управляет участниками бизнеса и их ролями. Менеджер
+// может приглашать и исключать участников, кроме владельцев; назначать
+// владельцев может только владелец.
+type BusinessMemberService struct {
+	repo         *repo.BusinessMemberRepo
+	businessRepo *repo.BusinessRepo
+	userRepo     *repo.UserRepo
+	permissions  *PermissionService
+	mailer       *email.Mailer
+	frontendURL  string
+	cfg          config.BusinessConfig
+}
+
+func NewBusinessMemberService(repo *repo.BusinessMemberRepo, businessRepo *repo.BusinessRepo, userRepo *repo.UserRepo, permissions *PermissionService, mailer *email.Mailer, frontendURL string, cfg config.BusinessConfig) *BusinessMemberService {
+	return &BusinessMemberService{
+		repo:         repo,
+		businessRepo: businessRepo,
+		userRepo:     userRepo,
+		permissions:  permissions,
+		mailer:       mailer,
+		frontendURL:  frontendURL,
+		cfg:          cfg,
+	}
+}
+
+func (s *BusinessMemberService) business(businessID int64) (model.Business, error) {
+	business, err := s.businessRepo.GetBusinessByID(businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Business{}, ErrBusinessNotFound
+	}
+	return business, err
+}
+
+// actorRole возвращает роль пользователя в бизнесе. Право business.manage
+// приравнивается к владельцу.
+func (s *BusinessMemberService) actorRole(ctx context.Context, businessID, userID int64) (model.MemberRole, error) {
+	ok, err := s.permissions.HasPermission(ctx, userID, model.PermBusinessManage)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return model.MemberOwner, nil
+	}
+
+	role, ok, err := s.businessRepo.GetMemberRole(userID, businessID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrForbidden
+	}
+	return role, nil
+}
+
+func (s *BusinessMemberService) member(businessID, userID int64) (model.UserToBusiness, error) {
+	m, err := s.repo.GetMember(businessID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserToBusiness{}, ErrMemberNotFound
+	}
+	return m, err
+}
+
+func (s *BusinessMemberService) GetMembers(businessID int64) ([]model.BusinessMember, error) {
+	if _, err := s.business(businessID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(businessID)
+}
+
+// UpdateMemberRole меняет роль участника userID
+func (s *BusinessMemberService) UpdateMemberRole(ctx context.Context, businessID, actorID, userID int64, role model.MemberRole) error {
+	actor, err := s.actorRole(ctx, businessID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := s.member(businessID, userID)
+	if err != nil {
+		return err
+	}
+	if actor != model.MemberOwner && (target.Role == model.MemberOwner || role == model.MemberOwner) {
+		return ErrForbidden
+	}
+
+	ok, err := s.repo.UpdateMemberRole(businessID, userID, role)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// RemoveMember исключает участника userID. Выйти из бизнеса может любой
+// участник, кроме последнего владельца.
+func (s *BusinessMemberService) RemoveMember(ctx context.Context, businessID, actorID, userID int64) error {
+	target, err := s.member(businessID, userID)
+	if err != nil {
+		return err
+	}
+	if actorID != userID {
+		actor, err := s.actorRole(ctx, businessID, actorID)
+		if err != nil {
+			return err
+		}
+		if actor != model.MemberOwner && (actor != model.MemberManager || target.Role == model.MemberOwner) {
+			return ErrForbidden
+		}
+	}
+
+	ok, err := s.repo.RemoveMember(businessID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// TransferOwnership передает владение участнику userID, прежний владелец
+// становится менеджером
+func (s *BusinessMemberService) TransferOwnership(businessID, actorID, userID int64) error {
+	if actorID == userID {
+		return ErrOwnershipToSelf
+	}
+	if _, err := s.member(businessID, userID); err != nil {
+		return err
+	}
+
+	err := s.repo.TransferOwnership(businessID, actorID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	}
+	return err
+}
+
+// Invite отправляет приглашение на почту. Ссылка действует InvitationTTL;
+// повторное приглашение того же адреса отменяет прежнюю ссылку.
+func (s *BusinessMemberService) Invite(ctx context.Context, businessID, actorID int64, req model.BusinessInvitationCreate) (model.BusinessInvitation, error) {
+	business, err := s.business(businessID)
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+	actor, err := s.actorRole(ctx, businessID, actorID)
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+	if actor != model.MemberOwner && req.Role == model.MemberOwner {
+		return model.BusinessInvitation{}, ErrForbidden
+	}
+
+	addr := strings.ToLower(strings.TrimSpace(req.Email))
+	if user, err := s.userRepo.GetUserByEmail(addr); err == nil {
+		if _, ok, err := s.businessRepo.GetMemberRole(user.ID, businessID); err != nil {
+			return model.BusinessInvitation{}, err
+		} else if ok {
+			return model.BusinessInvitation{}, ErrAlreadyMember
+		}
+	}
+
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+
+	now := time.Now()
+	inv, err := s.repo.CreateInvitation(model.BusinessInvitation{
+		BusinessID: businessID,
+		Email:      addr,
+		Role:       req.Role,
+		TokenHash:  hash,
+		InvitedBy:  actorID,
+		Status:     model.InvitationPending,
+		ExpiresAt:  now.Add(s.cfg.InvitationTTL),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+
+	s.sendInvitationEmail(business, inv, token)
+	return inv, nil
+}
+
+func (s *BusinessMemberService) GetInvitations(businessID int64) ([]model.BusinessInvitation, error) {
+	if _, err := s.business(businessID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPendingInvitations(businessID, time.Now())
+}
+
+// RevokeInvitation отзывает приглашение. Приглашение владельца, как и его
+// отправку, может отозвать только владелец.
+func (s *BusinessMemberService) RevokeInvitation(ctx context.Context, businessID, actorID, id int64) error {
+	inv, err := s.repo.GetInvitation(businessID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvitationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	actor, err := s.actorRole(ctx, businessID, actorID)
+	if err != nil {
+		return err
+	}
+	if actor != model.MemberOwner && inv.Role == model.MemberOwner {
+		return ErrForbidden
+	}
+
+	err = s.repo.RevokeInvitation(businessID, id, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvitationNotFound
+	}
+	return err
+}
+
+// invitation находит приглашение по токену из письма и проверяет, что оно
+// адресовано пользователю userID
+func (s *BusinessMemberService) invitation(userID int64, token string) (model.BusinessInvitation, error) {
+	inv, err := s.repo.GetInvitationByHash(hashRefreshToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.BusinessInvitation{}, ErrInvitationNotFound
+	}
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+	if inv.Status != model.InvitationPending || !time.Now().Before(inv.ExpiresAt) {
+		return model.BusinessInvitation{}, ErrInvitationExpired
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.BusinessInvitation{}, ErrUserNotFound
+	}
+	if err != nil {
+		return model.BusinessInvitation{}, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), inv.Email) {
+		return model.BusinessInvitation{}, ErrInvitationForbidden
+	}
+	return inv, nil
+}
+
+// AcceptInvitation добавляет пользователя в бизнес с ролью из приглашения
+func (s *BusinessMemberService) AcceptInvitation(userID int64, token string) (model.Business, error) {
+	inv, err := s.invitation(userID, token)
+	if err != nil {
+		return model.Business{}, err
+	}
+	if _, ok, err := s.businessRepo.GetMemberRole(userID, inv.BusinessID); err != nil {
+		return model.Business{}, err
+	} else if ok {
+		return model.Business{}, ErrAlreadyMember
+	}
+
+	ok, err := s.repo.AcceptInvitation(inv, userID, time.Now())
+	if err != nil {
+		return model.Business{}, err
+	}
+	if !ok {
+		return model.Business{}, ErrInvitationExpired
+	}
+	return s.business(inv.BusinessID)
+}
+
+func (s *BusinessMemberService) DeclineInvitation(userID int64, token string) error {
+	inv, err := s.invitation(userID, token)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.repo.DeclineInvitation(inv.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+// CleanupInvitations удаляет истекшие приглашения
+func (s *BusinessMemberService) CleanupInvitations(ctx context.Context) error {
+	_, err := s.repo.DeleteExpiredInvitations(time.Now())
+	return err
+}
+
+const businessInvitationEmailTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Приглашение в бизнес</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Вас пригласили в «{{.Business}}» с ролью «{{.Role}}».</p>
+            <p style="font-size: 16px; line-height: 1.5;">Войдите в аккаунт с этой почтой и примите приглашение до {{.Date}}:</p>
+            <p>
+                <a href="{{.AcceptLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Принять приглашение</a>
+            </p>
+            <p style="font-size: 14px; color: #666;">Если вы не ждали приглашения, просто проигнорируйте это письмо.</p>
+        </div>
+    </body>
+</html>
+`
+
+func makeBusinessInvitationEmailTemplate(business string, role string, expiresAt time.Time, acceptLink string) (string, error) {
+	tmpl, err := template.New("business_invitation_email").Parse(businessInvitationEmailTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Business   string
+		Role       string
+		Date       string
+		AcceptLink string
+	}{
+		Business:   business,
+		Role:       role,
+		Date:       expiresAt.Format("02.01.2006 15:04"),
+		AcceptLink: acceptLink,
+	})
+
+	return body.String(), err
+}
+
+func (s *BusinessMemberService) sendInvitationEmail(business model.Business, inv model.BusinessInvitation, token string) {
+	name := "ИНН " + requisites.FormatINN(business.INN)
+	if business.ShortName != nil && *business.ShortName != "" {
+		name = *business.ShortName
+	}
+
+	body, err := makeBusinessInvitationEmailTemplate(name, memberRoleNames[inv.Role], inv.ExpiresAt,
+		fmt.Sprintf("%s/business/invitations/accept?token=%s", s.frontendURL, token))
+	if err != nil {
+		slog.Error("cannot make business invitation email", sl.Err(err))
+		return
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(inv.Email, "Приглашение в бизнес", body); err != nil {
+			slog.Error("cannot send business invitation email", sl.Err(err))
+		}
+	}()
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/integrations/dadata"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/requisites"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrBusinessNotVerified     = errors.New("business is not verified")
+	ErrBusinessAlreadyVerified = errors.New("business is already verified")
+	ErrVerificationPending     = errors.New("business verification is already pending")
+	ErrVerificationNotPending  = errors.New("business has no pending verification")
+	ErrVerificationNotFound    = errors.New("business verification not found")
+	ErrDocumentsRequired       = errors.New("upload at least one document before requesting verification")
+	ErrDocumentNotFound        = errors.New("document not found")
+	ErrTooManyDocuments        = errors.New("too many documents")
+	ErrDocumentTooLarge        = errors.New("document is too large")
+	ErrUnsupportedDocument     = errors.New("document must be a PDF, JPEG or PNG file")
+	ErrInvalidDocumentKind     = errors.New("invalid document kind")
+	ErrBusinessNotInRegistry   = errors.New("business is not found in the registry")
+	ErrBusinessInactive        = errors.New("business is not active in the registry")
+	ErrBusinessRegistryOGRN    = errors.New("ogrn does not match the registry")
+	ErrBusinessNameMismatch    = errors.New("business name does not match the registry")
+)
+
+// documentExtensions - допустимые типы документов по результату http.DetectContentType
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// BusinessVerificationService проверяет, что бизнес существует и принадлежит
+// заявителю. При подаче заявки реквизиты сверяются с ЕГРЮЛ через DaData,
+// затем администратор решает по загруженным документам.
+type BusinessVerificationService struct {
+	repo         *repo.BusinessVerificationRepo
+	businessRepo *repo.BusinessRepo
+	dadata       *dadata.Client
+	blob         storage.Blob
+	cfg          config.BusinessConfig
+}
+
+func NewBusinessVerificationService(
+	repo *repo.BusinessVerificationRepo,
+	businessRepo *repo.BusinessRepo,
+	dadata *dadata.Client,
+	blob storage.Blob,
+	cfg config.BusinessConfig,
+) *BusinessVerificationService {
+	return &BusinessVerificationService{
+		repo:         repo,
+		businessRepo: businessRepo,
+		dadata:       dadata,
+		blob:         blob,
+		cfg:          cfg,
+	}
+}
+
+func (s *BusinessVerificationService) getBusiness(businessID int64) (model.Business, error) {
+	business, err := s.businessRepo.GetBusinessByID(businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Business{}, ErrBusinessNotFound
+	}
+	return business, err
+}
+
+// checkEditable запрещает менять документы заявки на проверке и подтвержденного бизнеса
+func checkEditable(business model.Business) error {
+	switch business.VerificationStatus {
+	case model.BusinessPending:
+		return ErrVerificationPending
+	case model.BusinessVerified:
+		return ErrBusinessAlreadyVerified
+	}
+	return nil
+}
+
+func (s *BusinessVerificationService) GetDocuments(businessID int64) ([]model.BusinessDocument, error) {
+	if _, err := s.getBusiness(businessID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDocuments(businessID)
+}
+
+// UploadDocument сохраняет документ для проверки. Тип файла определяется по
+// содержимому, а не по расширению и заголовку клиента.
+func (s *BusinessVerificationService) UploadDocument(
+	ctx context.Context,
+	businessID, userID int64,
+	kind model.BusinessDocumentKind,
+	file *multipart.FileHeader,
+) (model.BusinessDocument, error) {
+	switch kind {
+	case model.BusinessDocumentRegistration, model.BusinessDocumentCharter,
+		model.BusinessDocumentAuthority, model.BusinessDocumentOther:
+	default:
+		return model.BusinessDocument{}, ErrInvalidDocumentKind
+	}
+	if file.Size > s.cfg.DocumentMaxSize {
+		return model.BusinessDocument{}, ErrDocumentTooLarge
+	}
+
+	business, err := s.getBusiness(businessID)
+	if err != nil {
+		return model.BusinessDocument{}, err
+	}
+	if err := checkEditable(business); err != nil {
+		return model.BusinessDocument{}, err
+	}
+
+	count, err := s.repo.CountDocuments(businessID)
+	if err != nil {
+		return model.BusinessDocument{}, err
+	}
+	if count >= int64(s.cfg.MaxDocuments) {
+		return model.BusinessDocument{}, ErrTooManyDocuments
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return model.BusinessDocument{}, err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return model.BusinessDocument{}, ErrUnsupportedDocument
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := documentExtensions[contentType]
+	if !ok {
+		return model.BusinessDocument{}, ErrUnsupportedDocument
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return model.BusinessDocument{}, err
+	}
+
+	key := fmt.Sprintf("%d/%s%s", businessID, uuid.NewString(), ext)
+	if err := s.blob.Put(ctx, key, src, file.Size, contentType); err != nil {
+		return model.BusinessDocument{}, err
+	}
+
+	doc, err := s.repo.CreateDocument(model.BusinessDocument{
+		BusinessID:  businessID,
+		Kind:        kind,
+		FileName:    documentFileName(file.Filename, ext),
+		ContentType: contentType,
+		Size:        file.Size,
+		ObjectKey:   key,
+		UploadedBy:  userID,
+	})
+	if err != nil {
+		if err := s.blob.Delete(ctx, key); err != nil {
+			slog.Error("cannot delete orphaned business document", slog.String("key", key), sl.Err(err))
+		}
+		return model.BusinessDocument{}, err
+	}
+	return doc, nil
+}
+
+// documentFileName оставляет от имени файла клиента только базовое имя
+func documentFileName(name, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "document" + ext
+	}
+	if r := []rune(name); len(r) > 255 {
+		name = string(r[:255])
+	}
+	return name
+}
+
+func (s *BusinessVerificationService) OpenDocument(ctx context.Context, businessID, docID int64) (io.ReadCloser, model.BusinessDocument, error) {
+	doc, err := s.repo.GetDocument(businessID, docID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, model.BusinessDocument{}, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, model.BusinessDocument{}, err
+	}
+
+	rc, _, err := s.blob.Get(ctx, doc.ObjectKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, model.BusinessDocument{}, ErrDocumentNotFound
+	}
+	return rc, doc, err
+}
+
+func (s *BusinessVerificationService) DeleteDocument(ctx context.Context, businessID, docID int64) error {
+	business, err := s.getBusiness(businessID)
+	if err != nil {
+		return err
+	}
+	if err := checkEditable(business); err != nil {
+		return err
+	}
+
+	doc, err := s.repo.GetDocument(businessID, docID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDocumentNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDocument(businessID, docID); err != nil {
+		return err
+	}
+	if err := s.blob.Delete(ctx, doc.ObjectKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		slog.Error("cannot delete business document", slog.String("key", doc.ObjectKey), sl.Err(err))
+	}
+	return nil
+}
+
+// GetVerification возвращает последнюю заявку бизнеса
+func (s *BusinessVerificationService) GetVerification(businessID int64) (model.BusinessVerification, error) {
+	v, err := s.repo.GetLatestVerification(businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.BusinessVerification{}, ErrVerificationNotFound
+	}
+	return v, err
+}
+
+// GetPendingVerifications возвращает заявки, ожидающие решения, в порядке подачи
+func (s *BusinessVerificationService) GetPendingVerifications() ([]model.BusinessVerification, error) {
+	return s.repo.GetVerificationsByStatus(model.BusinessPending)
+}
+
+// Submit подает заявку на проверку. Организация должна быть действующей,
+// а ОГРН и название - совпадать с ЕГРЮЛ. Если DaData недоступна, заявка
+// все равно принимается, и сверку выполняет администратор.
+func (s *BusinessVerificationService) Submit(ctx context.Context, businessID, userID int64) (model.BusinessVerification, error) {
+	business, err := s.getBusiness(businessID)
+	if err != nil {
+		return model.BusinessVerification{}, err
+	}
+	if err := checkEditable(business); err != nil {
+		return model.BusinessVerification{}, err
+	}
+
+	count, err := s.repo.CountDocuments(businessID)
+	if err != nil {
+		return model.BusinessVerification{}, err
+	}
+	if count == 0 {
+		return model.BusinessVerification{}, ErrDocumentsRequired
+	}
+
+	v := model.BusinessVerification{
+		BusinessID:     businessID,
+		Status:         model.BusinessPending,
+		RegistryStatus: model.RegistryUnavailable,
+		SubmittedBy:    userID,
+		SubmittedAt:    time.Now(),
+	}
+
+	party, err := s.dadata.FindPartyByINN(ctx, requisites.FormatINN(business.INN))
+	switch {
+	case errors.Is(err, dadata.ErrPartyNotFound):
+		return model.BusinessVerification{}, ErrBusinessNotInRegistry
+	case err != nil:
+		slog.Warn("cannot cross-check business with registry", slog.Int64("business_id", businessID), sl.Err(err))
+	default:
+		if err := crossCheck(business, party); err != nil {
+			return model.BusinessVerification{}, err
+		}
+		v.RegistryStatus = string(party.Status)
+		v.RegistryName = party.FullName
+	}
+
+	v, ok, err := s.repo.Submit(v)
+	if err != nil {
+		return model.BusinessVerification{}, err
+	}
+	if !ok {
+		return model.BusinessVerification{}, ErrVerificationPending
+	}
+	return v, nil
+}
+
+// crossCheck сверяет бизнес со сведениями ЕГРЮЛ
+func crossCheck(business model.Business, party dadata.Party) error {
+	if party.Status != dadata.PartyActive {
+		return ErrBusinessInactive
+	}
+	if business.OGRN != nil && strconv.FormatInt(*business.OGRN, 10) != party.OGRN {
+		return ErrBusinessRegistryOGRN
+	}
+
+	registry := []string{normalizeName(party.ShortName), normalizeName(party.FullName)}
+	for _, name := range []*string{business.ShortName, business.FullName} {
+		if name == nil {
+			continue
+		}
+		n := normalizeName(*name)
+		for _, r := range registry {
+			if n != "" && n == r {
+				return nil
+			}
+		}
+	}
+	return ErrBusinessNameMismatch
+}
+
+// normalizeName приводит название к виду для сравнения: регистр, "ё", кавычки
+// и пробелы в названиях из ЕГРЮЛ и введенных пользователем обычно расходятся
+func normalizeName(name string) string {
+	name = strings.ReplaceAll(strings.ToUpper(name), "Ё", "Е")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Approve подтверждает бизнес по заявке на проверке
+func (s *BusinessVerificationService) Approve(businessID, reviewerID int64, comment string) error {
+	if _, err := s.getBusiness(businessID); err != nil {
+		return err
+	}
+
+	ok, err := s.repo.Review(businessID, []model.BusinessVerificationStatus{model.BusinessPending},
+		model.BusinessVerified, reviewerID, comment, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerificationNotPending
+	}
+	return nil
+}
+
+// Reject отклоняет заявку или отзывает подтверждение. Товары бизнеса снимаются с публикации.
+func (s *BusinessVerificationService) Reject(businessID, reviewerID int64, reason string) error {
+	if _, err := s.getBusiness(businessID); err != nil {
+		return err
+	}
+
+	ok, err := s.repo.Review(businessID, []model.BusinessVerificationStatus{model.BusinessPending, model.BusinessVerified},
+		model.BusinessRejected, reviewerID, reason, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerificationNotPending
+	}
+	return nil
+}
@@ -19,23 +19,24 @@ func NewCartService(repo *repo.CartRepo, pr *repo.ProductRepo) *CartService {
 	}
 }
 
-func (s *CartService) PostInCart(userID, productID int64, quantity int) (model.CartItem, error) {
-	_, err := s.pr.GetProductByID(context.Background(), productID)
+// PostInCart добавляет товар в корзину и возвращает его для учета в аналитике
+func (s *CartService) PostInCart(userID, productID int64, quantity int) (model.CartItem, *model.Product, error) {
+	product, err := s.pr.GetProductByID(context.Background(), productID)
 	if err != nil {
-		return model.CartItem{}, err
+		return model.CartItem{}, nil, err
 	}
 
 	userCart, err := s.repo.GetCart(userID)
 	if err != nil {
-		return model.CartItem{}, err
+		return model.CartItem{}, nil, err
 	}
 	for _, p := range userCart {
 		if p.ProductID == productID {
-			return model.CartItem{}, errors.New("product already in cart")
+			return model.CartItem{}, nil, errors.New("product already in cart")
 		}
 	}
 	cart, err := s.repo.PostInCart(model.CartItem{UserID: userID, Quantity: quantity, ProductID: productID})
-	return cart, err
+	return cart, product, err
 }
 
 func (s *CartService) DeleteCart(userID int64, productIDs []int64) error {
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"html/template"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWrongPassword          = errors.New("wrong password")
+	ErrSameEmail              = errors.New("new email matches the current one")
+	ErrInvalidRevertToken     = errors.New("invalid or expired revert link")
+	ErrEmailChangeNotFound    = errors.New("no pending email change")
+	ErrReauthenticationNeeded = errors.New("log in again to change email")
+)
+
+const (
+	// emailChangeRevertTTL - сколько действует ссылка отмены смены почты
+	emailChangeRevertTTL = 7 * 24 * time.Hour
+	// emailChangeReauthWindow - пользователь без пароля может сменить почту
+	// только из сессии, начатой не раньше этого срока
+	emailChangeReauthWindow = 10 * time.Minute
+)
+
+// RequestEmailChange отправляет код подтверждения на новый адрес. Почта
+// меняется только после ввода кода (ConfirmEmailChange), поэтому занять чужой
+// адрес нельзя. Пользователь с паролем подтверждает смену паролем, без пароля
+// (вход через провайдера или по телефону) - свежим входом в сессию sessionID.
+func (s *UserService) RequestEmailChange(userID int64, sessionID string, req model.EmailChangeRequest) error {
+	user, err := s.repo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(user, sessionID, req.Password); err != nil {
+		return err
+	}
+
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrSameEmail
+	}
+	if err := s.EmailExistsWithErr(newEmail); err != nil {
+		return err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+	// Ссылка отмены выдается при подтверждении, до него хеш лишь занимает место
+	_, revertHash, err := newRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.repo.CreateEmailChange(model.EmailChange{
+		UserID:           user.ID,
+		OldEmail:         user.Email,
+		NewEmail:         newEmail,
+		OldEmailVerified: user.IsEmailVerified,
+		Code:             code,
+		CodeExpiresAt:    now.Add(verificationCodeExpirationTime),
+		RevertTokenHash:  revertHash,
+		RevertExpiresAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	body, err := makeVerificationEmailTemplate(user.Name, code)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.mailer.SendMail(newEmail, "Подтверждение почты", body); err != nil {
+			slog.Error("cannot send verification email", sl.Err(err))
+		}
+	}()
+	return nil
+}
+
+// reauthenticate проверяет пароль, а если его нет - что сессия начата недавно
+func (s *UserService) reauthenticate(user model.User, sessionID string, plain string) error {
+	if user.PasswordHash != "" {
+		ok, _, err := s.hasher.Verify(user.PasswordHash, plain)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrWrongPassword
+		}
+		return nil
+	}
+
+	session, err := s.tokenRepo.GetSession(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrReauthenticationNeeded
+	}
+	if err != nil {
+		return err
+	}
+	if session.UserID != user.ID || time.Since(session.CreatedAt) > emailChangeReauthWindow {
+		return ErrReauthenticationNeeded
+	}
+	return nil
+}
+
+// ConfirmEmailChange меняет почту по коду с нового адреса. На старый адрес
+// уходит уведомление со ссылкой для отмены.
+func (s *UserService) ConfirmEmailChange(userID int64, code string) error {
+	change, err := s.repo.GetPendingEmailChange(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrEmailChangeNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.After(change.CodeExpiresAt) {
+		return ErrCodeExpired
+	}
+	if change.CodeAttempts >= verificationCodeMaxAttempts {
+		return ErrTooManyCodeAttempts
+	}
+	if subtle.ConstantTimeCompare([]byte(change.Code), []byte(code)) != 1 {
+		attempts, err := s.repo.IncrementEmailChangeAttempts(change.ID)
+		if err != nil {
+			return err
+		}
+		if attempts >= verificationCodeMaxAttempts {
+			if err := s.repo.DeleteEmailChange(change.ID); err != nil {
+				return err
+			}
+			return ErrTooManyCodeAttempts
+		}
+		return ErrInvalidCode
+	}
+
+	// Адрес мог зарегистрировать кто-то другой, пока шло подтверждение
+	if err := s.EmailExistsWithErr(change.NewEmail); err != nil {
+		return err
+	}
+
+	revertToken, revertHash, err := newRefreshToken()
+	if err != nil {
+		return err
+	}
+	change.RevertTokenHash = revertHash
+	change.RevertExpiresAt = now.Add(emailChangeRevertTTL)
+	confirmed, err := s.repo.ConfirmEmailChange(change, now)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrEmailChangeNotFound
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	body, err := makeEmailChangedTemplate(user.Name, change.NewEmail, fmt.Sprintf("%s/email/revert?token=%s", s.frontendURL, revertToken))
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.mailer.SendMail(change.OldEmail, "Адрес почты изменен", body); err != nil {
+			slog.Error("cannot send email change notice", sl.Err(err))
+		}
+	}()
+	return nil
+}
+
+// RevertEmailChange возвращает прежнюю почту по ссылке из уведомления и
+// завершает все сессии: смену могли сделать с украденного устройства
+func (s *UserService) RevertEmailChange(token string) error {
+	change, err := s.repo.GetEmailChangeByRevertHash(hashRefreshToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidRevertToken
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if change.ConfirmedAt == nil || change.RevertedAt != nil || now.After(change.RevertExpiresAt) {
+		return ErrInvalidRevertToken
+	}
+
+	// Освободившийся адрес мог занять другой пользователь
+	owner, err := s.repo.GetUserByEmail(change.OldEmail)
+	if err == nil && owner.ID != change.UserID {
+		return ErrEmailExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	reverted, err := s.repo.RevertEmailChange(change, now)
+	if err != nil {
+		return err
+	}
+	if !reverted {
+		return ErrInvalidRevertToken
+	}
+
+	return s.LogoutAll(change.UserID)
+}
+
+const emailChangedTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Адрес почты изменен</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Здравствуйте, {{.Name}}!</p>
+            <p style="font-size: 16px; line-height: 1.5;">Почта вашего аккаунта изменена на {{.NewEmail}}.</p>
+            <p style="font-size: 16px; line-height: 1.5;">Если это сделали не вы, верните прежний адрес по ссылке ниже. Все сеансы будут завершены, после этого смените пароль.</p>
+            <p>
+                <a href="{{.RevertLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Отменить изменение</a>
+            </p>
+            <p style="font-size: 14px; color: #666;">Ссылка действует 7 дней.</p>
+        </div>
+    </body>
+</html>
+`
+
+func makeEmailChangedTemplate(name string, newEmail string, revertLink string) (string, error) {
+	tmpl, err := template.New("email_changed").Parse(emailChangedTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Name       string
+		NewEmail   string
+		RevertLink string
+	}{Name: name, NewEmail: newEmail, RevertLink: revertLink})
+
+	return body.String(), err
+}
@@ -3,136 +3,139 @@ package service
 import (
 	"errors"
 	"fmt"
+	"github.com/RCSE2025/backend-go/internal/jwks"
 	"github.com/golang-jwt/jwt/v5"
-	"os"
 	"time"
 )
 
+// Типы токенов в claim typ. Токен одного типа не принимается вместо другого.
+const (
+	TokenTypeAccess        = "access"
+	TokenTypePasswordReset = "password_reset"
+	TokenTypeMFAPending    = "mfa_pending"
+	TokenTypeMFAEnroll     = "mfa_enroll"
+	TokenTypeAccountUnlock = "account_unlock"
+)
+
+var ErrWrongTokenType = errors.New("wrong token type")
+
 type JWTService interface {
-	GenerateToken(userId int64, role string) (string, error)
-	GenerateRefreshToken(userId int64, role string) (string, error)
+	GenerateToken(userId int64, role string, sessionID string) (string, error)
 	ValidateToken(token string) (*jwt.Token, error)
-	GetUserIDByToken(token string) (int64, error)
-	GetUserRole(token string) string
 	GenerateRefreshPasswordToken(userId int64) (string, error)
 	ValidateRefreshPasswordToken(token string) (refreshPasswordClaim, error)
+	GenerateMFAToken(userId int64, tokenType string) (string, error)
+	ValidateMFAToken(token string, tokenType string) (int64, error)
+	GenerateAccountUnlockToken(userId int64) (string, error)
+	ValidateAccountUnlockToken(token string) (int64, error)
+	JWKS() jwks.JSONWebKeySet
 }
 
 type jwtCustomClaim struct {
-	UserID int64  `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    int64  `json:"user_id"`
+	Role      string `json:"role"`
+	Type      string `json:"typ"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// jwtService подписывает токены асимметричным ключом (RS256 или EdDSA).
+// Ключ указывается в заголовке kid, проверка принимает любой ключ из набора.
 type jwtService struct {
-	secretKey string
+	keys      *jwks.Set
 	issuer    string
+	accessTTL time.Duration
+	parser    *jwt.Parser
+	// sessions отклоняет токены доступа отозванных сессий
+	sessions *SessionChecker
 }
 
-func NewJWTService() JWTService {
+func NewJWTService(keys *jwks.Set, issuer string, accessTTL time.Duration, sessions *SessionChecker) JWTService {
 	return &jwtService{
-		secretKey: getSecretKey(),
-		issuer:    "Template",
+		keys:      keys,
+		issuer:    issuer,
+		accessTTL: accessTTL,
+		parser:    jwt.NewParser(jwt.WithValidMethods(keys.Methods()), jwt.WithIssuer(issuer)),
+		sessions:  sessions,
 	}
 }
 
-func getSecretKey() string {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "Template"
-	}
-	return secretKey
+func (j *jwtService) sign(claims jwt.Claims) (string, error) {
+	key := j.keys.Signing()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// JWKS возвращает открытые ключи для проверки токенов другими сервисами
+func (j *jwtService) JWKS() jwks.JSONWebKeySet {
+	return j.keys.JWKS()
 }
 
-func (j *jwtService) GenerateToken(userId int64, role string) (string, error) {
+func (j *jwtService) GenerateToken(userId int64, role string, sessionID string) (string, error) {
 	claims := jwtCustomClaim{
 		userId,
 		role,
+		TokenTypeAccess,
+		sessionID,
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 120)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTTL)),
 			Issuer:    j.issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
-	if err != nil {
-		return "", err
-	}
-	return tx, nil
+	return j.sign(claims)
 }
 
 func (j *jwtService) parseToken(t_ *jwt.Token) (any, error) {
-	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok {
+	kid, _ := t_.Header["kid"].(string)
+	key, ok := j.keys.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t_.Method.Alg() != key.Method.Alg() {
 		return nil, fmt.Errorf("unexpected signing method %v", t_.Header["alg"])
 	}
-	return []byte(j.secretKey), nil
+	return key.Public, nil
 }
 
+// ValidateToken проверяет подпись и срок токена доступа, а также что
+// его сессия не отозвана выходом, сменой пароля или из списка сеансов
 func (j *jwtService) ValidateToken(token string) (*jwt.Token, error) {
-	return jwt.Parse(token, j.parseToken)
-}
-
-func (j *jwtService) GetUserIDByToken(token string) (int64, error) {
-	t_Token, err := j.ValidateToken(token)
+	t, err := j.parser.Parse(token, j.parseToken)
 	if err != nil {
-		return 0, err
-	}
-
-	claims := t_Token.Claims.(jwt.MapClaims)
-	fmt.Println(claims)
-	id, ok := claims["user_id"].(float64)
-	if !ok {
-		return 0, errors.New("user_id not found")
+		return nil, err
 	}
-	idInt := int64(id)
-	return idInt, nil
-}
 
-func (j *jwtService) GenerateRefreshToken(userId int64, role string) (string, error) {
-	claims := jwtCustomClaim{
-		userId,
-		role,
-		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7 * 30)),
-			Issuer:    j.issuer,
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != TokenTypeAccess {
+		return nil, ErrWrongTokenType
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
+	sid, _ := claims["sid"].(string)
+	active, err := j.sessions.Active(sid)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return tx, nil
-}
-
-func (j *jwtService) GetUserRole(token string) string {
-	t_Token, err := j.ValidateToken(token)
-	if err != nil {
-		return ""
-	}
-
-	claims := t_Token.Claims.(jwt.MapClaims)
-	role, ok := claims["role"].(string)
-	if !ok {
-		return ""
+	if !active {
+		return nil, ErrSessionRevoked
 	}
-	return role
+	return t, nil
 }
 
 const passwordRefreshTokenExpirationTime = 20 * time.Minute
 
 type refreshPasswordClaim struct {
-	UserID int64 `json:"user_id"`
+	UserID int64  `json:"user_id"`
+	Type   string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
 func (j *jwtService) GenerateRefreshPasswordToken(userId int64) (string, error) {
 	claims := refreshPasswordClaim{
 		userId,
+		TokenTypePasswordReset,
 		jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(passwordRefreshTokenExpirationTime)),
 			Issuer:    j.issuer,
@@ -140,20 +143,67 @@ func (j *jwtService) GenerateRefreshPasswordToken(userId int64) (string, error)
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
-	if err != nil {
-		return "", err
-	}
-	return tx, nil
+	return j.sign(claims)
 }
 
 func (j *jwtService) ValidateRefreshPasswordToken(token string) (refreshPasswordClaim, error) {
 	var claims refreshPasswordClaim
-	_, err := jwt.ParseWithClaims(token, &claims, j.parseToken)
+	_, err := j.parser.ParseWithClaims(token, &claims, j.parseToken)
 	if err != nil {
 		return refreshPasswordClaim{}, err
 	}
+	if claims.Type != TokenTypePasswordReset {
+		return refreshPasswordClaim{}, ErrWrongTokenType
+	}
 
 	return claims, nil
 }
+
+const (
+	mfaTokenExpirationTime           = 5 * time.Minute
+	accountUnlockTokenExpirationTime = time.Hour
+)
+
+// generateTypedToken выдает короткоживущий токен для одного действия с пользователем
+func (j *jwtService) generateTypedToken(userId int64, tokenType string, ttl time.Duration) (string, error) {
+	claims := refreshPasswordClaim{
+		userId,
+		tokenType,
+		jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			Issuer:    j.issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return j.sign(claims)
+}
+
+func (j *jwtService) validateTypedToken(token string, tokenType string) (int64, error) {
+	var claims refreshPasswordClaim
+	if _, err := j.parser.ParseWithClaims(token, &claims, j.parseToken); err != nil {
+		return 0, err
+	}
+	if claims.Type != tokenType {
+		return 0, ErrWrongTokenType
+	}
+	return claims.UserID, nil
+}
+
+// GenerateMFAToken выдает короткоживущий токен промежуточного шага входа:
+// пароль проверен, но вход еще не завершен
+func (j *jwtService) GenerateMFAToken(userId int64, tokenType string) (string, error) {
+	return j.generateTypedToken(userId, tokenType, mfaTokenExpirationTime)
+}
+
+func (j *jwtService) ValidateMFAToken(token string, tokenType string) (int64, error) {
+	return j.validateTypedToken(token, tokenType)
+}
+
+// GenerateAccountUnlockToken выдает токен для ссылки разблокировки аккаунта
+func (j *jwtService) GenerateAccountUnlockToken(userId int64) (string, error) {
+	return j.generateTypedToken(userId, TokenTypeAccountUnlock, accountUnlockTokenExpirationTime)
+}
+
+func (j *jwtService) ValidateAccountUnlockToken(token string) (int64, error) {
+	return j.validateTypedToken(token, TokenTypeAccountUnlock)
+}
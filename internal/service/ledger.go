@@ -0,0 +1,372 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCommissionRuleNotFound = errors.New("commission rule not found")
+	ErrInvalidCategory        = errors.New("unknown product category")
+	ErrInvalidStatementMonth  = errors.New("month must be in YYYY-MM format")
+)
+
+const ledgerCurrency = "RUB"
+
+// csvBOM нужен Excel, чтобы прочитать кириллицу в UTF-8
+const csvBOM = "\ufeff"
+
+// csvText экранирует текст, который Excel принял бы за формулу
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// LedgerService ведет расчеты площадки с продавцами методом двойной записи.
+// Все деньги покупателей приходят на один счет ЮKassa; по каждому бизнесу
+// учитывается, сколько из них площадка должна ему за вычетом комиссии.
+type LedgerService struct {
+	repo         *repo.LedgerRepo
+	businessRepo *repo.BusinessRepo
+	cfg          config.FinanceConfig
+	loc          *time.Location
+}
+
+func NewLedgerService(repo *repo.LedgerRepo, businessRepo *repo.BusinessRepo, cfg config.FinanceConfig) *LedgerService {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		slog.Error("unknown finance time zone, using UTC", slog.String("tz", cfg.TimeZone), sl.Err(err))
+		loc = time.UTC
+	}
+	return &LedgerService{
+		repo:         repo,
+		businessRepo: businessRepo,
+		cfg:          cfg,
+		loc:          loc,
+	}
+}
+
+// kopecks переводит рубли в копейки
+func kopecks(rubles float64) int64 {
+	return int64(math.Round(rubles * 100))
+}
+
+// formatKopecks печатает сумму в рублях с копейками: -1234.50
+func formatKopecks(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// orderShare - доля продавца в заказе в копейках
+type orderShare struct {
+	businessID int64
+	amount     int64
+	commission int64
+}
+
+// orderShares делит заказ между продавцами и считает комиссию по каждой позиции
+func (s *LedgerService) orderShares(orderID int64) ([]orderShare, error) {
+	items, err := s.repo.GetOrderItems(orderID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.GetCommissionRules()
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []orderShare
+	for _, item := range items {
+		if len(shares) == 0 || shares[len(shares)-1].businessID != item.BusinessID {
+			shares = append(shares, orderShare{businessID: item.BusinessID})
+		}
+		rate := commissionRate(rules, item.BusinessID, item.Category, s.cfg.CommissionRate)
+		share := &shares[len(shares)-1]
+		share.amount += item.Amount
+		share.commission += int64(math.Round(float64(item.Amount) * rate / 100))
+	}
+	return shares, nil
+}
+
+// commissionRate выбирает самое точное подходящее правило
+func commissionRate(rules []model.CommissionRule, businessID int64, category model.ProductCategory, fallback float64) float64 {
+	rate, best := fallback, -1
+	for _, r := range rules {
+		if (r.BusinessID != nil && *r.BusinessID != businessID) || (r.Category != nil && *r.Category != category) {
+			continue
+		}
+		score := 0
+		if r.BusinessID != nil {
+			score += 2
+		}
+		if r.Category != nil {
+			score++
+		}
+		if score > best {
+			rate, best = r.Rate, score
+		}
+	}
+	return rate
+}
+
+// PostCapture проводит поступление оплаты заказа и комиссию площадки по
+// каждому продавцу. Повторный вызов для того же заказа ничего не меняет.
+func (s *LedgerService) PostCapture(orderID int64) error {
+	shares, err := s.orderShares(orderID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var txs []model.LedgerTransaction
+	for _, share := range shares {
+		capture := model.NewLedgerTransfer(model.LedgerCapture, fmt.Sprintf("capture:%d:%d", orderID, share.businessID),
+			share.businessID, model.LedgerCash, model.LedgerSellerPayable, share.amount)
+		capture.OrderID = &orderID
+		capture.Description = fmt.Sprintf("Оплата заказа №%d", orderID)
+		capture.CreatedAt = now
+		txs = append(txs, capture)
+
+		if share.commission == 0 {
+			continue
+		}
+		commission := model.NewLedgerTransfer(model.LedgerCommissionCharge, fmt.Sprintf("commission:%d:%d", orderID, share.businessID),
+			share.businessID, model.LedgerSellerPayable, model.LedgerCommission, share.commission)
+		commission.OrderID = &orderID
+		commission.Description = fmt.Sprintf("Комиссия площадки по заказу №%d", orderID)
+		commission.CreatedAt = now
+		txs = append(txs, commission)
+	}
+	return s.repo.Post(txs...)
+}
+
+// PostRefund проводит возврат. Сумма делится между продавцами пропорционально
+// их позициям, комиссия с возвращенной части возвращается продавцу.
+func (s *LedgerService) PostRefund(refund model.OrderRefund) error {
+	if err := s.PostCapture(refund.OrderID); err != nil {
+		return err
+	}
+	shares, err := s.orderShares(refund.OrderID)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, share := range shares {
+		total += share.amount
+	}
+	if total == 0 {
+		return nil
+	}
+	amount := min(kopecks(refund.Amount), total)
+
+	now := time.Now()
+	var txs []model.LedgerTransaction
+	rest := amount
+	for i, share := range shares {
+		// Остаток от округления достается последнему продавцу
+		part := amount * share.amount / total
+		if i == len(shares)-1 {
+			part = rest
+		}
+		rest -= part
+		if part <= 0 {
+			continue
+		}
+
+		t := model.NewLedgerTransfer(model.LedgerRefund, fmt.Sprintf("refund:%s:%d", refund.ID, share.businessID),
+			share.businessID, model.LedgerSellerPayable, model.LedgerCash, part)
+		t.OrderID, t.RefundID = &refund.OrderID, &refund.ID
+		t.Description = fmt.Sprintf("Возврат по заказу №%d", refund.OrderID)
+		t.CreatedAt = now
+		txs = append(txs, t)
+
+		commission, err := s.repo.GetOrderCommission(refund.OrderID, share.businessID)
+		if err != nil {
+			return err
+		}
+		back := int64(math.Round(float64(commission) * float64(part) / float64(share.amount)))
+		if back <= 0 {
+			continue
+		}
+		t = model.NewLedgerTransfer(model.LedgerCommissionRefund, fmt.Sprintf("commission_refund:%s:%d", refund.ID, share.businessID),
+			share.businessID, model.LedgerCommission, model.LedgerSellerPayable, back)
+		t.OrderID, t.RefundID = &refund.OrderID, &refund.ID
+		t.Description = fmt.Sprintf("Возврат комиссии по заказу №%d", refund.OrderID)
+		t.CreatedAt = now
+		txs = append(txs, t)
+	}
+	return s.repo.Post(txs...)
+}
+
+// PostMissingCaptures проводит оплаченные заказы, которые не попали в учет,
+// например если проводка при подтверждении оплаты не удалась
+func (s *LedgerService) PostMissingCaptures() error {
+	ids, err := s.repo.GetUncapturedOrders(100)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.PostCapture(id); err != nil {
+			slog.Error("cannot post order capture", slog.Int64("order_id", id), sl.Err(err))
+		}
+	}
+	return nil
+}
+
+func (s *LedgerService) GetCommissionRules() ([]model.CommissionRule, error) {
+	rules, err := s.repo.GetCommissionRules()
+	if rules == nil {
+		rules = []model.CommissionRule{}
+	}
+	return rules, err
+}
+
+// SaveCommissionRule задает комиссию для бизнеса, категории или их пары.
+// Ставка применяется к заказам, оплаченным после изменения.
+func (s *LedgerService) SaveCommissionRule(req model.CommissionRuleCreate) (model.CommissionRule, error) {
+	if req.BusinessID != nil {
+		if _, err := s.businessRepo.GetBusinessByID(*req.BusinessID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.CommissionRule{}, ErrBusinessNotFound
+			}
+			return model.CommissionRule{}, err
+		}
+	}
+	if req.Category != nil {
+		if _, ok := model.ProductCategoryMap[*req.Category]; !ok {
+			return model.CommissionRule{}, ErrInvalidCategory
+		}
+	}
+
+	return s.repo.SaveCommissionRule(model.CommissionRule{
+		BusinessID: req.BusinessID,
+		Category:   req.Category,
+		Rate:       *req.Rate,
+	})
+}
+
+func (s *LedgerService) DeleteCommissionRule(id int64) error {
+	ok, err := s.repo.DeleteCommissionRule(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCommissionRuleNotFound
+	}
+	return nil
+}
+
+// GetSellerBalance возвращает расчеты с продавцом на текущий момент
+func (s *LedgerService) GetSellerBalance(businessID int64) (model.SellerBalance, error) {
+	balance, err := s.repo.GetSellerBalance(businessID, time.Now().Add(-s.cfg.PayoutHold))
+	if err != nil {
+		return model.SellerBalance{}, err
+	}
+	balance.Available = max(balance.Available, 0)
+	balance.Currency = ledgerCurrency
+	return balance, nil
+}
+
+func (s *LedgerService) GetPlatformBalance() (model.PlatformBalance, error) {
+	balance, err := s.repo.GetPlatformBalance()
+	balance.Currency = ledgerCurrency
+	return balance, err
+}
+
+// GetStatement возвращает выписку по расчетам с продавцом за месяц YYYY-MM.
+// Границы месяца берутся в часовом поясе бухгалтерии.
+func (s *LedgerService) GetStatement(businessID int64, month string) (model.Statement, error) {
+	start, err := time.ParseInLocation("2006-01", month, s.loc)
+	if err != nil {
+		return model.Statement{}, ErrInvalidStatementMonth
+	}
+	end := start.AddDate(0, 1, 0)
+
+	opening, err := s.repo.GetSellerBalanceAt(businessID, start)
+	if err != nil {
+		return model.Statement{}, err
+	}
+	lines, err := s.repo.GetStatementLines(businessID, start, end)
+	if err != nil {
+		return model.Statement{}, err
+	}
+	if lines == nil {
+		lines = []model.StatementLine{}
+	}
+
+	statement := model.Statement{
+		BusinessID:     businessID,
+		Month:          start.Format("2006-01"),
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Lines:          lines,
+	}
+	for i, line := range lines {
+		lines[i].Date = line.Date.In(s.loc)
+		statement.ClosingBalance += line.Amount
+		switch line.Kind {
+		case model.LedgerCapture:
+			statement.Sales += line.Amount
+		case model.LedgerRefund:
+			statement.Refunds += line.Amount
+		case model.LedgerCommissionCharge, model.LedgerCommissionRefund:
+			statement.Commission += line.Amount
+		case model.LedgerPayout, model.LedgerPayoutFailed:
+			statement.Payouts += line.Amount
+		}
+	}
+	return statement, nil
+}
+
+// WriteStatementCSV пишет выписку в CSV для загрузки в учетную систему.
+// Разделитель - точка с запятой, суммы в рублях: так файл открывается в Excel.
+func WriteStatementCSV(w io.Writer, statement model.Statement) error {
+	if _, err := io.WriteString(w, csvBOM); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	optional := func(id *int64) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatInt(*id, 10)
+	}
+
+	records := [][]string{
+		{"date", "kind", "order_id", "payout_id", "description", "amount"},
+		{"", "opening_balance", "", "", "Входящий остаток", formatKopecks(statement.OpeningBalance)},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.Date.Format("2006-01-02 15:04:05"),
+			string(line.Kind),
+			optional(line.OrderID),
+			optional(line.PayoutID),
+			csvText(line.Description),
+			formatKopecks(line.Amount),
+		})
+	}
+	records = append(records, []string{"", "closing_balance", "", "", "Исходящий остаток", formatKopecks(statement.ClosingBalance)})
+
+	return cw.WriteAll(records)
+}
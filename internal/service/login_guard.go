@@ -0,0 +1,296 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/attempts"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"html/template"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTooManyLoginAttempts = errors.New("too many login attempts, try again later")
+	ErrInvalidUnlockToken   = errors.New("invalid or expired unlock token")
+)
+
+// Причины неудачного входа в журнале аудита
+const (
+	loginFailUnknownUser   = "unknown_user"
+	loginFailWrongPassword = "wrong_password"
+	loginFailThrottled     = "throttled"
+)
+
+// RetryAfterError сообщает, через сколько клиент может повторить запрос
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// LoginGuard ограничивает подбор паролей. Неудачные попытки считаются отдельно
+// по аккаунту и по IP: после FreeAttempts ошибок каждая следующая попытка
+// откладывается на экспоненциально растущее время, а после порога блокировки
+// вход закрывается на LockDuration.
+type LoginGuard struct {
+	counter attempts.Counter
+	audit   *repo.AuditRepo
+	cfg     config.LoginGuardConfig
+}
+
+func NewLoginGuard(counter attempts.Counter, audit *repo.AuditRepo, cfg config.LoginGuardConfig) *LoginGuard {
+	// Счетчик не должен обнуляться раньше, чем закончится блокировка
+	if cfg.Window < cfg.LockDuration {
+		cfg.Window = cfg.LockDuration
+	}
+	return &LoginGuard{
+		counter: counter,
+		audit:   audit,
+		cfg:     cfg,
+	}
+}
+
+func accountAttemptsKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptsKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check возвращает *RetryAfterError, если вход для аккаунта или IP временно запрещен
+func (g *LoginGuard) Check(ctx context.Context, email string, meta model.SessionMeta) error {
+	now := time.Now()
+
+	wait, err := g.wait(ctx, accountAttemptsKey(email), g.cfg.AccountLockAttempts, now)
+	if err != nil {
+		return err
+	}
+	if meta.IP != "" {
+		ipWait, err := g.wait(ctx, ipAttemptsKey(meta.IP), g.cfg.IPLockAttempts, now)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, ipWait)
+	}
+
+	if wait > 0 {
+		g.record(ctx, model.AuthEvent{
+			Type:      model.AuthEventLoginFailed,
+			Email:     email,
+			IP:        meta.IP,
+			UserAgent: meta.UserAgent,
+			Reason:    loginFailThrottled,
+		})
+		return &RetryAfterError{Err: ErrTooManyLoginAttempts, RetryAfter: wait}
+	}
+	return nil
+}
+
+func (g *LoginGuard) wait(ctx context.Context, key string, lockAttempts int, now time.Time) (time.Duration, error) {
+	state, err := g.counter.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if state.Failures == 0 {
+		return 0, nil
+	}
+	return state.LastFailureAt.Add(g.delay(state.Failures, lockAttempts)).Sub(now), nil
+}
+
+// delay - сколько нужно ждать после failures неудачных попыток подряд
+func (g *LoginGuard) delay(failures, lockAttempts int) time.Duration {
+	if lockAttempts > 0 && failures >= lockAttempts {
+		return g.cfg.LockDuration
+	}
+	if failures < g.cfg.FreeAttempts {
+		return 0
+	}
+
+	shift := failures - g.cfg.FreeAttempts
+	if shift > 30 {
+		return g.cfg.MaxDelay
+	}
+	d := g.cfg.BaseDelay << shift
+	if d <= 0 || d > g.cfg.MaxDelay {
+		return g.cfg.MaxDelay
+	}
+	return d
+}
+
+// Fail учитывает неудачный вход и пишет его в журнал аудита.
+// locked = true, если аккаунт заблокирован именно этой попыткой.
+func (g *LoginGuard) Fail(ctx context.Context, email string, userID *int64, reason string, meta model.SessionMeta) (bool, error) {
+	now := time.Now()
+
+	state, err := g.counter.Fail(ctx, accountAttemptsKey(email), now, g.cfg.Window)
+	if err != nil {
+		return false, err
+	}
+	if meta.IP != "" {
+		if _, err := g.counter.Fail(ctx, ipAttemptsKey(meta.IP), now, g.cfg.Window); err != nil {
+			return false, err
+		}
+	}
+
+	event := model.AuthEvent{
+		Type:      model.AuthEventLoginFailed,
+		UserID:    userID,
+		Email:     email,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+		Reason:    reason,
+	}
+	g.record(ctx, event)
+
+	locked := g.cfg.AccountLockAttempts > 0 && state.Failures == g.cfg.AccountLockAttempts
+	if locked {
+		event.Type = model.AuthEventAccountLocked
+		event.Reason = ""
+		g.record(ctx, event)
+	}
+	return locked, nil
+}
+
+// Succeed сбрасывает счетчик аккаунта после успешного входа.
+// Счетчик IP не сбрасывается, иначе вход в свой аккаунт позволял бы
+// продолжать перебор чужих.
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	return g.counter.Reset(ctx, accountAttemptsKey(email))
+}
+
+// Unlock снимает блокировку аккаунта по ссылке из письма
+func (g *LoginGuard) Unlock(ctx context.Context, user model.User, meta model.SessionMeta) error {
+	if err := g.counter.Reset(ctx, accountAttemptsKey(user.Email)); err != nil {
+		return err
+	}
+
+	g.record(ctx, model.AuthEvent{
+		Type:      model.AuthEventAccountUnlocked,
+		UserID:    &user.ID,
+		Email:     user.Email,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+	})
+	return nil
+}
+
+// Cleanup удаляет устаревшие счетчики и записи журнала старше AuditRetention
+func (g *LoginGuard) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	if err := g.counter.Cleanup(ctx, now); err != nil {
+		return err
+	}
+	return g.audit.DeleteAuthEventsBefore(now.Add(-g.cfg.AuditRetention))
+}
+
+// record пишет событие в журнал; ошибка журнала не должна мешать входу
+func (g *LoginGuard) record(ctx context.Context, event model.AuthEvent) {
+	event.UserAgent = truncate(event.UserAgent, 512)
+	if err := g.audit.CreateAuthEvent(event); err != nil {
+		slog.Error("cannot write auth audit event", slog.String("type", string(event.Type)), sl.Err(err))
+	}
+}
+
+// loginFailed учитывает неудачный вход; при блокировке аккаунта владельцу
+// отправляется письмо со ссылкой для разблокировки
+func (s *UserService) loginFailed(ctx context.Context, email string, user *model.User, reason string, meta model.SessionMeta) error {
+	var userID *int64
+	if user != nil {
+		userID = &user.ID
+	}
+
+	locked, err := s.guard.Fail(ctx, email, userID, reason, meta)
+	if err != nil {
+		return err
+	}
+	if locked && user != nil {
+		s.sendUnlockEmail(*user)
+	}
+	return ErrWrongEmailOrPassword
+}
+
+// UnlockAccount снимает блокировку входа по токену из письма
+func (s *UserService) UnlockAccount(ctx context.Context, token string, meta model.SessionMeta) error {
+	userID, err := s.jwtService.ValidateAccountUnlockToken(token)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidUnlockToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.guard.Unlock(ctx, user, meta)
+}
+
+const unlockAccountEmailTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Вход временно заблокирован</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Здравствуйте, {{.Name}}!</p>
+            <p style="font-size: 16px; line-height: 1.5;">Мы зафиксировали несколько неудачных попыток входа в ваш аккаунт и временно заблокировали вход.</p>
+            <p style="font-size: 16px; line-height: 1.5;">Если это были вы, разблокируйте аккаунт по ссылке ниже:</p>
+            <p>
+                <a href="{{.UnlockLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Разблокировать</a>
+            </p>
+            <p style="font-size: 14px; color: #666;">Если это были не вы, рекомендуем сменить пароль и включить двухфакторную аутентификацию.</p>
+        </div>
+    </body>
+</html>
+`
+
+func makeUnlockAccountEmailTemplate(name string, unlockLink string) (string, error) {
+	tmpl, err := template.New("unlock_account_email").Parse(unlockAccountEmailTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Name       string
+		UnlockLink string
+	}{Name: name, UnlockLink: unlockLink})
+
+	return body.String(), err
+}
+
+func (s *UserService) sendUnlockEmail(user model.User) {
+	token, err := s.jwtService.GenerateAccountUnlockToken(user.ID)
+	if err != nil {
+		slog.Error("cannot generate unlock token", sl.Err(err))
+		return
+	}
+
+	body, err := makeUnlockAccountEmailTemplate(user.Name, fmt.Sprintf("%s/unlock?token=%s", s.frontendURL, token))
+	if err != nil {
+		slog.Error("cannot make unlock email", sl.Err(err))
+		return
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(user.Email, "Вход в аккаунт заблокирован", body); err != nil {
+			slog.Error("cannot send unlock email", sl.Err(err))
+		}
+	}()
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+	"image/png"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor enrollment was not started")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrMFALocked         = errors.New("too many invalid codes, try again later")
+)
+
+const (
+	totpPeriod = 30
+	// totpSkew - допустимое расхождение часов клиента в шагах
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	mfaMaxAttempts    = 5
+	mfaLockDuration   = 15 * time.Minute
+	qrCodeSize        = 256
+)
+
+// MFAConfig - настройки двухфакторной аутентификации
+type MFAConfig struct {
+	Issuer string
+	// EncryptionKey шифрует секреты TOTP в базе
+	EncryptionKey string
+	// RequiredRoles - роли, которым нельзя входить без 2FA
+	RequiredRoles []string
+}
+
+// recoveryAlphabet не содержит похожих символов (0/o, 1/l/i)
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// MFARequired сообщает, обязана ли роль пользователя использовать 2FA
+func (s *UserService) MFARequired(user model.User) bool {
+	return slices.Contains(s.mfaCfg.RequiredRoles, string(user.Role))
+}
+
+// loginStep проверяет, нужен ли второй шаг входа. Если нужен, возвращает
+// токен с mfa_token вместо пары токенов доступа.
+func (s *UserService) loginStep(user model.User) (model.Token, bool, error) {
+	mfa, err := s.mfaRepo.GetMFA(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Token{}, false, err
+	}
+
+	switch {
+	case err == nil && mfa.Enabled:
+		mfaToken, err := s.jwtService.GenerateMFAToken(user.ID, TokenTypeMFAPending)
+		if err != nil {
+			return model.Token{}, false, err
+		}
+		return model.Token{MFARequired: true, MFAToken: mfaToken}, true, nil
+	case s.MFARequired(user):
+		mfaToken, err := s.jwtService.GenerateMFAToken(user.ID, TokenTypeMFAEnroll)
+		if err != nil {
+			return model.Token{}, false, err
+		}
+		return model.Token{MFAEnrollmentRequired: true, MFAToken: mfaToken}, true, nil
+	}
+	return model.Token{}, false, nil
+}
+
+// CompleteMFALogin завершает вход по mfa_pending токену и коду из приложения или коду восстановления
+func (s *UserService) CompleteMFALogin(mfaToken, code string, meta model.SessionMeta) (model.Token, error) {
+	userID, err := s.jwtService.ValidateMFAToken(mfaToken, TokenTypeMFAPending)
+	if err != nil {
+		return model.Token{}, ErrInvalidMFAToken
+	}
+
+	mfa, err := s.mfaRepo.GetMFA(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !mfa.Enabled) {
+		return model.Token{}, ErrMFANotEnabled
+	}
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	if err := s.verifyMFACode(mfa, code, true); err != nil {
+		return model.Token{}, err
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return model.Token{}, err
+	}
+	return s.GenerateNewToken(user, meta)
+}
+
+// MFAEnrollUserID возвращает пользователя, которому при входе выдан mfa_enroll токен
+func (s *UserService) MFAEnrollUserID(mfaToken string) (int64, error) {
+	userID, err := s.jwtService.ValidateMFAToken(mfaToken, TokenTypeMFAEnroll)
+	if err != nil {
+		return 0, ErrInvalidMFAToken
+	}
+	return userID, nil
+}
+
+func (s *UserService) GetMFAStatus(userID int64) (model.MFAStatus, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return model.MFAStatus{}, err
+	}
+
+	status := model.MFAStatus{Required: s.MFARequired(user)}
+
+	mfa, err := s.mfaRepo.GetMFA(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return model.MFAStatus{}, err
+	}
+	status.Enabled = mfa.Enabled
+
+	if mfa.Enabled {
+		left, err := s.mfaRepo.CountRecoveryCodes(userID)
+		if err != nil {
+			return model.MFAStatus{}, err
+		}
+		status.RecoveryCodesLeft = int(left)
+	}
+	return status, nil
+}
+
+// StartMFAEnrollment создает новый секрет TOTP. 2FA включается только после ConfirmMFA.
+func (s *UserService) StartMFAEnrollment(userID int64) (model.MFAEnrollment, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return model.MFAEnrollment{}, err
+	}
+
+	mfa, err := s.mfaRepo.GetMFA(userID)
+	if err == nil && mfa.Enabled {
+		return model.MFAEnrollment{}, ErrMFAAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.MFAEnrollment{}, err
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.mfaCfg.Issuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return model.MFAEnrollment{}, err
+	}
+
+	secretEnc, err := s.encryptSecret(key.Secret())
+	if err != nil {
+		return model.MFAEnrollment{}, err
+	}
+	if err := s.mfaRepo.SaveMFA(model.UserMFA{UserID: userID, SecretEnc: secretEnc}); err != nil {
+		return model.MFAEnrollment{}, err
+	}
+
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return model.MFAEnrollment{}, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return model.MFAEnrollment{}, err
+	}
+
+	return model.MFAEnrollment{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+		QRCodePNG:       qr.Bytes(),
+	}, nil
+}
+
+// ConfirmMFA включает 2FA после проверки первого кода и выдает коды восстановления
+func (s *UserService) ConfirmMFA(userID int64, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.GetMFA(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err := s.verifyMFACode(mfa, code, false); err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.EnableMFA(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+// ConfirmMFALogin подтверждает обязательное подключение 2FA и завершает вход
+func (s *UserService) ConfirmMFALogin(mfaToken, code string, meta model.SessionMeta) (model.MFAConfirmed, error) {
+	userID, err := s.MFAEnrollUserID(mfaToken)
+	if err != nil {
+		return model.MFAConfirmed{}, err
+	}
+
+	codes, err := s.ConfirmMFA(userID, code)
+	if err != nil {
+		return model.MFAConfirmed{}, err
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return model.MFAConfirmed{}, err
+	}
+	token, err := s.GenerateNewToken(user, meta)
+	if err != nil {
+		return model.MFAConfirmed{}, err
+	}
+	return model.MFAConfirmed{RecoveryCodes: codes, Token: &token}, nil
+}
+
+// DisableMFA отключает 2FA; требуется код из приложения или код восстановления
+func (s *UserService) DisableMFA(userID int64, code string) error {
+	mfa, err := s.enabledMFA(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyMFACode(mfa, code, true); err != nil {
+		return err
+	}
+	return s.mfaRepo.DeleteMFA(userID)
+}
+
+// RegenerateRecoveryCodes заменяет все коды восстановления новыми
+func (s *UserService) RegenerateRecoveryCodes(userID int64, code string) ([]string, error) {
+	mfa, err := s.enabledMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyMFACode(mfa, code, false); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+func (s *UserService) enabledMFA(userID int64) (model.UserMFA, error) {
+	mfa, err := s.mfaRepo.GetMFA(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !mfa.Enabled) {
+		return model.UserMFA{}, ErrMFANotEnabled
+	}
+	return mfa, err
+}
+
+// verifyMFACode проверяет код TOTP, а при allowRecovery - и код восстановления.
+// Каждый временной шаг принимается один раз; после mfaMaxAttempts ошибок
+// проверка блокируется на mfaLockDuration.
+func (s *UserService) verifyMFACode(mfa model.UserMFA, code string, allowRecovery bool) error {
+	now := time.Now()
+	if mfa.LockedUntil != nil && now.Before(*mfa.LockedUntil) {
+		return ErrMFALocked
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == int(otp.DigitsSix) {
+		secret, err := s.decryptSecret(mfa.SecretEnc)
+		if err != nil {
+			return err
+		}
+		if step, ok := matchTOTP(secret, code, now); ok {
+			used, err := s.mfaRepo.UseStep(mfa.UserID, step)
+			if err != nil {
+				return err
+			}
+			if used {
+				return nil
+			}
+		}
+	} else if allowRecovery {
+		ok, err := s.mfaRepo.UseRecoveryCode(mfa.UserID, hashRecoveryCode(code), now)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return s.mfaRepo.ResetFailures(mfa.UserID)
+		}
+	}
+
+	if err := s.mfaRepo.RegisterFailure(mfa.UserID, mfaMaxAttempts, now.Add(mfaLockDuration)); err != nil {
+		return err
+	}
+	return ErrInvalidMFACode
+}
+
+// matchTOTP возвращает временной шаг, которому соответствует код
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	counter := now.Unix() / totpPeriod
+	for d := int64(-totpSkew); d <= totpSkew; d++ {
+		step := counter + d
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func (s *UserService) newRecoveryCodes(userID int64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode возвращает код вида xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = recoveryAlphabet[n.Int64()]
+	}
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+// hashRecoveryCode нормализует код (регистр, дефисы, пробелы) и хеширует его
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *UserService) mfaCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(s.mfaCfg.EncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *UserService) encryptSecret(secret string) (string, error) {
+	gcm, err := s.mfaCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func (s *UserService) decryptSecret(enc string) (string, error) {
+	gcm, err := s.mfaCipher()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("cannot decode mfa secret: %w", err)
+	}
+
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt mfa secret: %w", err)
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/oauth"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownOAuthProvider  = oauth.ErrUnknownProvider
+	ErrOAuthExchangeFailed   = oauth.ErrExchangeFailed
+	ErrInvalidOAuthState     = errors.New("invalid or expired oauth state")
+	ErrOAuthEmailRequired    = errors.New("provider did not return a verified email")
+	ErrOAuthEmailConflict    = errors.New("an account with this email exists, log in and link the provider from the profile")
+	ErrIdentityLinked        = errors.New("this provider account is linked to another user")
+	ErrProviderAlreadyLinked = errors.New("provider is already linked")
+	ErrIdentityNotFound      = errors.New("provider is not linked")
+	ErrLastLoginMethod       = errors.New("cannot unlink the last login method, set a password first")
+)
+
+// OAuthService - вход и регистрация через внешних провайдеров
+// (authorization code + PKCE) и привязка провайдеров к профилю
+type OAuthService struct {
+	providers   *oauth.Registry
+	repo        OAuthStore
+	users       OAuthUsers
+	userService *UserService
+	stateTTL    time.Duration
+}
+
+// OAuthStore хранит запросы авторизации и привязанных провайдеров; реализуется repo.OAuthRepo
+type OAuthStore interface {
+	CreateState(state model.OAuthState) error
+	TakeState(stateHash string) (model.OAuthState, error)
+	DeleteExpiredStates(now time.Time) error
+	GetIdentity(provider string, subject string) (model.UserIdentity, error)
+	GetUserIdentities(userID int64) ([]model.UserIdentity, error)
+	CreateIdentity(identity model.UserIdentity) (model.UserIdentity, error)
+	CreateUserWithIdentity(user model.User, identity model.UserIdentity) (model.User, error)
+	TouchIdentity(id int64, now time.Time) error
+	DeleteIdentity(userID int64, provider string) (bool, error)
+}
+
+// OAuthUsers - пользователи, с которыми связываются провайдеры; реализуется repo.UserRepo
+type OAuthUsers interface {
+	GetUserByID(id int64) (model.User, error)
+	GetUserByEmail(email string) (model.User, error)
+}
+
+func NewOAuthService(providers *oauth.Registry, repo OAuthStore, users OAuthUsers, userService *UserService, stateTTL time.Duration) *OAuthService {
+	return &OAuthService{
+		providers:   providers,
+		repo:        repo,
+		users:       users,
+		userService: userService,
+		stateTTL:    stateTTL,
+	}
+}
+
+func (s *OAuthService) Providers() []string {
+	return s.providers.Names()
+}
+
+// Authorize начинает вход у провайдера и возвращает адрес, куда отправить пользователя.
+// userID задается при привязке провайдера к существующему аккаунту.
+func (s *OAuthService) Authorize(providerName string, userID *int64) (string, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	state, stateHash, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oauth.NewCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.CreateState(model.OAuthState{
+		StateHash:    stateHash,
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(s.stateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, oauth.CodeChallenge(verifier)), nil
+}
+
+// identity проверяет state и получает данные пользователя у провайдера
+func (s *OAuthService) identity(ctx context.Context, providerName string, cb model.OAuthCallback, userID *int64) (oauth.Identity, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return oauth.Identity{}, err
+	}
+
+	state, err := s.repo.TakeState(hashRefreshToken(cb.State))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return oauth.Identity{}, ErrInvalidOAuthState
+	}
+	if err != nil {
+		return oauth.Identity{}, err
+	}
+
+	sameUser := (state.UserID == nil && userID == nil) ||
+		(state.UserID != nil && userID != nil && *state.UserID == *userID)
+	if state.Provider != providerName || time.Now().After(state.ExpiresAt) || !sameUser {
+		return oauth.Identity{}, ErrInvalidOAuthState
+	}
+
+	return provider.Identity(ctx, oauth.Callback{Code: cb.Code, State: cb.State, DeviceID: cb.DeviceID}, state.CodeVerifier)
+}
+
+// Login завершает вход через провайдера. Учетная запись провайдера
+// связывается с пользователем по подтвержденной почте; если такого
+// пользователя нет, он регистрируется без пароля.
+func (s *OAuthService) Login(ctx context.Context, providerName string, cb model.OAuthCallback, meta model.SessionMeta) (model.Token, error) {
+	identity, err := s.identity(ctx, providerName, cb, nil)
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	user, err := s.userForIdentity(identity)
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	if token, pending, err := s.userService.loginStep(user); err != nil || pending {
+		return token, err
+	}
+	return s.userService.GenerateNewToken(user, meta)
+}
+
+func (s *OAuthService) userForIdentity(identity oauth.Identity) (model.User, error) {
+	linked, err := s.repo.GetIdentity(identity.Provider, identity.Subject)
+	if err == nil {
+		if err := s.repo.TouchIdentity(linked.ID, time.Now()); err != nil {
+			slog.Error("cannot update identity login time", sl.Err(err))
+		}
+		return s.users.GetUserByID(linked.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return model.User{}, ErrOAuthEmailRequired
+	}
+
+	now := time.Now()
+	record := model.UserIdentity{
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: &now,
+	}
+
+	user, err := s.users.GetUserByEmail(strings.TrimSpace(identity.Email))
+	switch {
+	case err == nil:
+		// Неподтвержденный адрес мог зарегистрировать кто угодно; иначе
+		// владелец почты получил бы аккаунт с чужим паролем
+		if !user.IsEmailVerified {
+			return model.User{}, ErrOAuthEmailConflict
+		}
+		record.UserID = user.ID
+		if _, err := s.repo.CreateIdentity(record); err != nil {
+			return model.User{}, err
+		}
+		return user, nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Пароль не задан: войти можно через провайдера или задав пароль через восстановление
+		return s.repo.CreateUserWithIdentity(model.User{
+			Name:            identity.Name,
+			Surname:         identity.Surname,
+			Email:           strings.TrimSpace(identity.Email),
+			IsEmailVerified: true,
+			Role:            model.UserRole,
+		}, record)
+	default:
+		return model.User{}, err
+	}
+}
+
+// Link привязывает провайдера к аккаунту пользователя
+func (s *OAuthService) Link(ctx context.Context, userID int64, providerName string, cb model.OAuthCallback) error {
+	identity, err := s.identity(ctx, providerName, cb, &userID)
+	if err != nil {
+		return err
+	}
+
+	linked, err := s.repo.GetIdentity(identity.Provider, identity.Subject)
+	if err == nil {
+		if linked.UserID != userID {
+			return ErrIdentityLinked
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	identities, err := s.repo.GetUserIdentities(userID)
+	if err != nil {
+		return err
+	}
+	for _, i := range identities {
+		if i.Provider == identity.Provider {
+			return ErrProviderAlreadyLinked
+		}
+	}
+
+	_, err = s.repo.CreateIdentity(model.UserIdentity{
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	return err
+}
+
+func (s *OAuthService) Identities(userID int64) ([]model.UserIdentity, error) {
+	return s.repo.GetUserIdentities(userID)
+}
+
+// Unlink отвязывает провайдера, если у пользователя остается другой способ входа
+func (s *OAuthService) Unlink(userID int64, providerName string) error {
+	user, err := s.users.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	identities, err := s.repo.GetUserIdentities(userID)
+	if err != nil {
+		return err
+	}
+	others := 0
+	for _, i := range identities {
+		if i.Provider != providerName {
+			others++
+		}
+	}
+	if others == len(identities) {
+		return ErrIdentityNotFound
+	}
+	if others == 0 && user.PasswordHash == "" && !user.IsPhoneVerified {
+		return ErrLastLoginMethod
+	}
+
+	deleted, err := s.repo.DeleteIdentity(userID, providerName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
+
+// Cleanup удаляет незавершенные запросы авторизации
+func (s *OAuthService) Cleanup(ctx context.Context) error {
+	return s.repo.DeleteExpiredStates(time.Now())
+}
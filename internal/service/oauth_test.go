@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/oauth"
+	"gorm.io/gorm"
+)
+
+const mockProvider = "mock"
+
+// mockOAuthServer - провайдер OpenID Connect с authorize, token и userinfo.
+// Токен выдается только при верном code_verifier для code_challenge из authorize.
+type mockOAuthServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	challenges map[string]string // код -> code_challenge
+	tokens     map[string]bool
+	nextCode   int
+	user       map[string]any
+}
+
+func newMockOAuthServer(t *testing.T, user map[string]any) *mockOAuthServer {
+	m := &mockOAuthServer{
+		challenges: make(map[string]string),
+		tokens:     make(map[string]bool),
+		user:       user,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"userinfo_endpoint":      m.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/authorize", m.authorize)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/userinfo", m.userinfo)
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (m *mockOAuthServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != "client" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	m.nextCode++
+	code := fmt.Sprintf("code-%d", m.nextCode)
+	m.challenges[code] = q.Get("code_challenge")
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockOAuthServer) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	challenge, ok := m.challenges[code]
+	delete(m.challenges, code)
+	if !ok || oauth.CodeChallenge(r.PostForm.Get("code_verifier")) != challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	token := "token-" + code
+	m.tokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": token, "token_type": "Bearer"})
+}
+
+func (m *mockOAuthServer) userinfo(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	ok := m.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	m.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, m.user)
+}
+
+type fakeOAuthStore struct {
+	states     map[string]model.OAuthState
+	identities []model.UserIdentity
+	users      map[int64]model.User
+}
+
+func (f *fakeOAuthStore) CreateState(state model.OAuthState) error {
+	f.states[state.StateHash] = state
+	return nil
+}
+
+func (f *fakeOAuthStore) TakeState(stateHash string) (model.OAuthState, error) {
+	state, ok := f.states[stateHash]
+	if !ok {
+		return model.OAuthState{}, gorm.ErrRecordNotFound
+	}
+	delete(f.states, stateHash)
+	return state, nil
+}
+
+func (f *fakeOAuthStore) DeleteExpiredStates(now time.Time) error {
+	return nil
+}
+
+func (f *fakeOAuthStore) GetIdentity(provider string, subject string) (model.UserIdentity, error) {
+	for _, i := range f.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return model.UserIdentity{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOAuthStore) GetUserIdentities(userID int64) ([]model.UserIdentity, error) {
+	identities := make([]model.UserIdentity, 0)
+	for _, i := range f.identities {
+		if i.UserID == userID {
+			identities = append(identities, i)
+		}
+	}
+	return identities, nil
+}
+
+func (f *fakeOAuthStore) CreateIdentity(identity model.UserIdentity) (model.UserIdentity, error) {
+	identity.ID = int64(len(f.identities) + 1)
+	f.identities = append(f.identities, identity)
+	return identity, nil
+}
+
+func (f *fakeOAuthStore) CreateUserWithIdentity(user model.User, identity model.UserIdentity) (model.User, error) {
+	user.ID = int64(len(f.users) + 1)
+	f.users[user.ID] = user
+	identity.UserID = user.ID
+	_, err := f.CreateIdentity(identity)
+	return user, err
+}
+
+func (f *fakeOAuthStore) TouchIdentity(id int64, now time.Time) error {
+	return nil
+}
+
+func (f *fakeOAuthStore) DeleteIdentity(userID int64, provider string) (bool, error) {
+	for n, i := range f.identities {
+		if i.UserID == userID && i.Provider == provider {
+			f.identities = append(f.identities[:n], f.identities[n+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeOAuthStore) GetUserByID(id int64) (model.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return model.User{}, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeOAuthStore) GetUserByEmail(email string) (model.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return model.User{}, gorm.ErrRecordNotFound
+}
+
+func newTestOAuthService(t *testing.T, providerUser map[string]any, users ...model.User) (*OAuthService, *fakeOAuthStore) {
+	srv := newMockOAuthServer(t, providerUser)
+	providers, err := oauth.New(context.Background(), config.OAuthConfig{
+		Timeout:      5 * time.Second,
+		OIDCName:     mockProvider,
+		OIDCIssuer:   srv.URL,
+		OIDCClientID: "client",
+		OIDCScopes:   []string{"openid", "email"},
+	}, "http://frontend.test")
+	if err != nil {
+		t.Fatalf("oauth.New: %v", err)
+	}
+
+	store := &fakeOAuthStore{states: make(map[string]model.OAuthState), users: make(map[int64]model.User)}
+	for _, u := range users {
+		store.users[u.ID] = u
+	}
+	return NewOAuthService(providers, store, store, nil, time.Minute), store
+}
+
+// authorize проходит страницу входа провайдера и возвращает параметры redirect_uri
+func authorize(t *testing.T, s *OAuthService, userID *int64) model.OAuthCallback {
+	authURL, err := s.Authorize(mockProvider, userID)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("GET authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d", resp.StatusCode)
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("authorize location: %v", err)
+	}
+	if !strings.HasPrefix(location.String(), "http://frontend.test/oauth/callback/"+mockProvider) {
+		t.Fatalf("unexpected redirect %s", location)
+	}
+	return model.OAuthCallback{Code: location.Query().Get("code"), State: location.Query().Get("state")}
+}
+
+// loginUser - вход через провайдера без выпуска токенов
+func loginUser(s *OAuthService, cb model.OAuthCallback) (model.User, error) {
+	identity, err := s.identity(context.Background(), mockProvider, cb, nil)
+	if err != nil {
+		return model.User{}, err
+	}
+	return s.userForIdentity(identity)
+}
+
+func TestOAuthLoginLinksVerifiedEmail(t *testing.T) {
+	local := model.User{ID: 7, Email: "ivan@example.com", IsEmailVerified: true, PasswordHash: "hash"}
+	s, store := newTestOAuthService(t, map[string]any{
+		"sub":            "subject-1",
+		"email":          "ivan@example.com",
+		"email_verified": "true",
+	}, local)
+
+	user, err := loginUser(s, authorize(t, s, nil))
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user.ID != local.ID {
+		t.Fatalf("logged in as user %d, want %d", user.ID, local.ID)
+	}
+	if len(store.identities) != 1 || store.identities[0].UserID != local.ID || store.identities[0].Subject != "subject-1" {
+		t.Fatalf("identity not linked: %+v", store.identities)
+	}
+
+	// Повторный вход находит пользователя по привязке
+	user, err = loginUser(s, authorize(t, s, nil))
+	if err != nil || user.ID != local.ID {
+		t.Fatalf("second login = %d, %v", user.ID, err)
+	}
+	if len(store.identities) != 1 {
+		t.Fatalf("identity linked twice: %+v", store.identities)
+	}
+}
+
+func TestOAuthLoginUnverifiedLocalEmail(t *testing.T) {
+	local := model.User{ID: 7, Email: "ivan@example.com", PasswordHash: "hash"}
+	s, store := newTestOAuthService(t, map[string]any{
+		"sub":            "subject-1",
+		"email":          "ivan@example.com",
+		"email_verified": true,
+	}, local)
+
+	_, err := loginUser(s, authorize(t, s, nil))
+	if !errors.Is(err, ErrOAuthEmailConflict) {
+		t.Fatalf("err = %v, want %v", err, ErrOAuthEmailConflict)
+	}
+	if len(store.identities) != 0 {
+		t.Fatalf("identity must not be linked: %+v", store.identities)
+	}
+}
+
+func TestOAuthLoginUnverifiedProviderEmail(t *testing.T) {
+	s, store := newTestOAuthService(t, map[string]any{
+		"sub":   "subject-1",
+		"email": "ivan@example.com",
+	})
+
+	_, err := loginUser(s, authorize(t, s, nil))
+	if !errors.Is(err, ErrOAuthEmailRequired) {
+		t.Fatalf("err = %v, want %v", err, ErrOAuthEmailRequired)
+	}
+	if len(store.users) != 0 {
+		t.Fatalf("user must not be created: %+v", store.users)
+	}
+}
+
+func TestOAuthCodeVerifierMismatch(t *testing.T) {
+	s, store := newTestOAuthService(t, map[string]any{
+		"sub":            "subject-1",
+		"email":          "ivan@example.com",
+		"email_verified": true,
+	})
+
+	cb := authorize(t, s, nil)
+	hash := hashRefreshToken(cb.State)
+	state := store.states[hash]
+	state.CodeVerifier = strings.Repeat("x", 43)
+	store.states[hash] = state
+
+	_, err := loginUser(s, cb)
+	if !errors.Is(err, ErrOAuthExchangeFailed) {
+		t.Fatalf("err = %v, want %v", err, ErrOAuthExchangeFailed)
+	}
+
+	// state одноразовый
+	_, err = loginUser(s, cb)
+	if !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("reused state: err = %v, want %v", err, ErrInvalidOAuthState)
+	}
+}
+
+func TestOAuthUnlinkLastLoginMethod(t *testing.T) {
+	s, store := newTestOAuthService(t, map[string]any{
+		"sub":            "subject-1",
+		"email":          "ivan@example.com",
+		"email_verified": true,
+	})
+
+	user, err := loginUser(s, authorize(t, s, nil))
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user.PasswordHash != "" {
+		t.Fatalf("oauth user must be created without password")
+	}
+
+	if err := s.Unlink(user.ID, mockProvider); !errors.Is(err, ErrLastLoginMethod) {
+		t.Fatalf("err = %v, want %v", err, ErrLastLoginMethod)
+	}
+	if len(store.identities) != 1 {
+		t.Fatalf("last identity must stay linked: %+v", store.identities)
+	}
+	if err := s.Unlink(user.ID, "other"); !errors.Is(err, ErrIdentityNotFound) {
+		t.Fatalf("unlink unknown provider: err = %v, want %v", err, ErrIdentityNotFound)
+	}
+
+	user.PasswordHash = "hash"
+	store.users[user.ID] = user
+	if err := s.Unlink(user.ID, mockProvider); err != nil {
+		t.Fatalf("unlink with password: %v", err)
+	}
+	if len(store.identities) != 0 {
+		t.Fatalf("identity not unlinked: %+v", store.identities)
+	}
+}
+
+func TestOAuthLinkFromProfile(t *testing.T) {
+	local := model.User{ID: 7, Email: "ivan@example.com", IsEmailVerified: true, PasswordHash: "hash"}
+	s, store := newTestOAuthService(t, map[string]any{"sub": "subject-1"}, local)
+
+	// state привязки нельзя завершить от имени другого пользователя
+	other := int64(8)
+	cb := authorize(t, s, &local.ID)
+	if err := s.Link(context.Background(), other, mockProvider, cb); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidOAuthState)
+	}
+
+	if err := s.Link(context.Background(), local.ID, mockProvider, authorize(t, s, &local.ID)); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if len(store.identities) != 1 || store.identities[0].UserID != local.ID {
+		t.Fatalf("identity not linked: %+v", store.identities)
+	}
+}
@@ -2,28 +2,83 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/sms"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"log/slog"
+	"strings"
 )
 
 type OrderService struct {
 	repo        *repo.OrderRepo
 	productRepo *repo.ProductRepo
+	userRepo    *repo.UserRepo
 	cartService *CartService
+	addresses   *AddressService
 	yookassa    *YookassaPayment
+	ledger      *LedgerService
+	// sms уведомляет о заказах на подтвержденный телефон; nil отключает уведомления
+	sms sms.Sender
 }
 
-func NewOrderService(repo *repo.OrderRepo, productRepo *repo.ProductRepo, yookassa *YookassaPayment, cartService *CartService) *OrderService {
+func NewOrderService(repo *repo.OrderRepo, productRepo *repo.ProductRepo, userRepo *repo.UserRepo, yookassa *YookassaPayment, ledger *LedgerService, cartService *CartService, addresses *AddressService, smsSender sms.Sender) *OrderService {
 	return &OrderService{
 		repo:        repo,
 		productRepo: productRepo,
+		userRepo:    userRepo,
 		yookassa:    yookassa,
+		ledger:      ledger,
 		cartService: cartService,
+		addresses:   addresses,
+		sms:         smsSender,
 	}
 }
 
-func (ordS *OrderService) CreateOrder(userID int64, address string) (model.Order, error) {
-	return ordS.repo.CreateOrder(model.Order{UserID: userID, Status: model.StatusCreated, Address: address})
+// CreateOrder оформляет заказ на сохраненный адрес addressID или на адрес,
+// введенный строкой. Сохраненный адрес копируется в заказ, введенный
+// строкой - стандартизируется.
+func (ordS *OrderService) CreateOrder(ctx context.Context, userID int64, addressID *int64, address string) (model.Order, error) {
+	order := model.Order{UserID: userID, Status: model.StatusCreated, Address: strings.TrimSpace(address)}
+	switch {
+	case addressID != nil:
+		saved, err := ordS.addresses.GetAddress(userID, *addressID)
+		if err != nil {
+			return model.Order{}, err
+		}
+		order.AddressID = &saved.ID
+		order.Delivery = saved.AddressFields
+		order.Address = saved.AddressFields.String()
+	case order.Address == "":
+		return model.Order{}, ErrAddressRequired
+	default:
+		normalized, err := ordS.addresses.Normalize(ctx, order.Address)
+		if errors.Is(err, ErrAddressNotRecognized) {
+			return model.Order{}, err
+		}
+		if err != nil {
+			// Недоступность сервиса подсказок не должна мешать оформить заказ
+			slog.Warn("cannot normalize order address", sl.Err(err))
+			break
+		}
+		order.Address = normalized.Value
+		order.Delivery = model.AddressFields{
+			Region:    normalized.Region,
+			City:      normalized.City,
+			Street:    normalized.Street,
+			House:     normalized.House,
+			Apartment: normalized.Apartment,
+			Postcode:  normalized.Postcode,
+			FiasID:    normalized.FiasID,
+			KladrID:   normalized.KladrID,
+			Latitude:  normalized.Latitude,
+			Longitude: normalized.Longitude,
+		}
+	}
+
+	return ordS.repo.CreateOrder(order)
 }
 
 func (ordS *OrderService) CreateOrderItem(userID, orderID, productID int64, quantity int) (model.OrderItem, error) {
@@ -35,18 +90,131 @@ func (ordS *OrderService) CreateOrderItem(userID, orderID, productID int64, quan
 	return ordS.repo.CreateOrderItem(model.OrderItem{UserID: userID, OrderID: orderID, ProductID: productID, Quantity: quantity, Price: product.Price})
 }
 
-func (ordS *OrderService) SetOrderStatus(orderID, userID int64, status model.OrderStatusType) error {
-	return ordS.repo.SetOrderStatus(orderID, userID, status)
+func (ordS *OrderService) SetOrderStatus(userID, orderID int64, status model.OrderStatusType) error {
+	if err := ordS.repo.SetOrderStatus(userID, orderID, status); err != nil {
+		return err
+	}
+	ordS.notify(orderID, orderStatusText(status))
+	return nil
 }
 
 func (ordS *OrderService) GetUserOrders(userID int64) ([]model.OrderItemResponse, error) {
 	return ordS.repo.GetUserOrders(userID)
 }
 
+// ConfirmOrderPayment отмечает заказ оплаченным без платежа ЮKassa.
+// Такой заказ в расчеты с продавцами не попадает.
 func (ordS *OrderService) ConfirmOrderPayment(orderID int64) error {
-	return ordS.repo.ConfirmOrderPayment(orderID)
+	if err := ordS.repo.ConfirmOrderPayment(orderID); err != nil {
+		return err
+	}
+	ordS.notify(orderID, "оплачен и передан продавцу")
+	return nil
+}
+
+// ConfirmYookassaPayment проверяет платеж по id из уведомления ЮKassa,
+// отмечает заказ оплаченным и проводит оплату в расчетах с продавцами.
+// При ошибке проводки уведомление придет повторно, пропущенные заказы
+// также проводит задача выплат.
+func (ordS *OrderService) ConfirmYookassaPayment(paymentID string) error {
+	payment, err := ordS.yookassa.GetPayment(paymentID)
+	if err != nil {
+		return err
+	}
+	if err := ordS.repo.ConfirmYookassaPayment(payment.OrderID, payment.ID); err != nil {
+		return err
+	}
+	if err := ordS.ledger.PostCapture(payment.OrderID); err != nil {
+		return err
+	}
+	ordS.notify(payment.OrderID, "оплачен и передан продавцу")
+	return nil
+}
+
+// RecordRefund сохраняет проведенный возврат по id из уведомления ЮKassa
+// и списывает его с балансов продавцов
+func (ordS *OrderService) RecordRefund(refundID string) error {
+	refund, err := ordS.yookassa.GetRefund(refundID)
+	if err != nil {
+		return err
+	}
+	orderRefund := model.OrderRefund{
+		ID:        refund.ID,
+		OrderID:   refund.OrderID,
+		Amount:    refund.Amount,
+		CreatedAt: refund.CreatedAt,
+	}
+	if err := ordS.repo.CreateRefund(orderRefund); err != nil {
+		return err
+	}
+	return ordS.ledger.PostRefund(orderRefund)
 }
 
-func (ordS *OrderService) CreateOrderPayment(orderID int64, amount float64) (string, error) {
-	return ordS.yookassa.CreateOrderPayment(orderID, amount)
+// CreateOrderPayment создает платеж; чек по 54-ФЗ получает покупатель
+// на подтвержденный телефон или почту
+func (ordS *OrderService) CreateOrderPayment(userID, orderID int64, amount float64) (string, error) {
+	user, err := ordS.userRepo.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	items, err := ordS.repo.GetOrderItems(orderID)
+	if err != nil {
+		return "", err
+	}
+
+	receipt := Receipt{Email: user.Email}
+	if user.IsPhoneVerified && user.Phone != nil {
+		receipt.Phone = *user.Phone
+	}
+	for _, item := range items {
+		receipt.Items = append(receipt.Items, ReceiptItem{
+			Description: item.Product.Title,
+			Quantity:    item.OrderItem.Quantity,
+			Price:       item.OrderItem.Price,
+		})
+	}
+
+	return ordS.yookassa.CreateOrderPayment(orderID, amount, receipt)
+}
+
+func orderStatusText(status model.OrderStatusType) string {
+	switch status {
+	case model.StatusCreated:
+		return "оформлен"
+	case model.StatusDelivery:
+		return "передан в доставку"
+	case model.StatusClosed:
+		return "выполнен"
+	default:
+		return string(status)
+	}
+}
+
+// notify отправляет покупателю SMS о заказе, если его телефон подтвержден.
+// Ошибки только пишутся в лог: уведомление не должно ломать работу с заказом.
+func (ordS *OrderService) notify(orderID int64, text string) {
+	if ordS.sms == nil {
+		return
+	}
+
+	go func() {
+		order, err := ordS.repo.GetOrderByID(orderID)
+		if err != nil {
+			slog.Error("cannot get order for notification", slog.Int64("order_id", orderID), sl.Err(err))
+			return
+		}
+		user, err := ordS.userRepo.GetUserByID(order.UserID)
+		if err != nil {
+			slog.Error("cannot get user for order notification", slog.Int64("order_id", orderID), sl.Err(err))
+			return
+		}
+		if !user.IsPhoneVerified || user.Phone == nil {
+			return
+		}
+
+		message := fmt.Sprintf("Заказ №%d %s.", orderID, text)
+		if err := ordS.sms.Send(context.Background(), *user.Phone, message); err != nil {
+			slog.Error("cannot send order notification", slog.Int64("order_id", orderID), sl.Err(err))
+		}
+	}()
 }
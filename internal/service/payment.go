@@ -1,29 +1,68 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
 	"github.com/rvinnie/yookassa-sdk-go/yookassa"
 	yoocommon "github.com/rvinnie/yookassa-sdk-go/yookassa/common"
 	"github.com/rvinnie/yookassa-sdk-go/yookassa/payment"
+	"github.com/rvinnie/yookassa-sdk-go/yookassa/refund"
 	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRefundNotSucceeded - возврат еще не проведен или отменен
+	ErrRefundNotSucceeded = errors.New("refund is not succeeded")
+	// ErrPaymentNotSucceeded - платеж еще не проведен или отменен
+	ErrPaymentNotSucceeded = errors.New("payment is not succeeded")
 )
 
 type YookassaPayment struct {
-	client *yookassa.Client
+	client          *yookassa.Client
+	receiptsEnabled bool
+	vatCode         int
 }
 
-func NewYookassaPayment(accountId string, secretKey string) *YookassaPayment {
+func NewYookassaPayment(cfg config.YookassaСonfig) *YookassaPayment {
 	return &YookassaPayment{
-		client: yookassa.NewClient(accountId, secretKey),
+		client:          yookassa.NewClient(cfg.AccountId, cfg.SecretKey),
+		receiptsEnabled: cfg.ReceiptsEnabled,
+		vatCode:         cfg.VatCode,
 	}
 }
 
-func (p *YookassaPayment) CreateOrderPayment(orderID int64, amount float64) (string, error) {
+// ReceiptItem - позиция фискального чека
+type ReceiptItem struct {
+	Description string
+	Quantity    int
+	Price       float64
+}
+
+// Receipt - данные чека по 54-ФЗ. Чек уходит покупателю на телефон,
+// если он подтвержден, иначе на почту.
+type Receipt struct {
+	Email string
+	Phone string
+	Items []ReceiptItem
+}
+
+// receiptDescriptionMaxLength - ограничение ЮKassa на название позиции
+const receiptDescriptionMaxLength = 128
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", math.Round(amount*100)/100)
+}
+
+func (p *YookassaPayment) CreateOrderPayment(orderID int64, amount float64, receipt Receipt) (string, error) {
 	paymentHandler := yookassa.NewPaymentHandler(p.client)
-	// Создаем платеж
-	payment, _ := paymentHandler.CreatePayment(&yoopayment.Payment{
+
+	request := &yoopayment.Payment{
 		Amount: &yoocommon.Amount{
-			Value:    fmt.Sprintf("%.2f", math.Round(amount*100)/100),
+			Value:    formatAmount(amount),
 			Currency: "RUB",
 		},
 		PaymentMethod: yoopayment.PaymentMethodType("bank_card"),
@@ -35,7 +74,122 @@ func (p *YookassaPayment) CreateOrderPayment(orderID int64, amount float64) (str
 		Metadata: map[string]interface{}{
 			"order_id": orderID,
 		},
-	})
+	}
+	if p.receiptsEnabled {
+		request.Receipt = p.makeReceipt(receipt)
+	}
+
+	// Создаем платеж
+	payment, err := paymentHandler.CreatePayment(request)
+	if err != nil {
+		return "", err
+	}
+
+	confirmation, ok := payment.Confirmation.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected confirmation %T", payment.Confirmation)
+	}
+	url, _ := confirmation["confirmation_url"].(string)
+	return url, nil
+}
+
+func (p *YookassaPayment) makeReceipt(receipt Receipt) *yoopayment.Receipt {
+	customer := &yoocommon.Customer{}
+	if receipt.Phone != "" {
+		// ЮKassa принимает номер в формате E.164 без "+"
+		customer.Phone = strings.TrimPrefix(receipt.Phone, "+")
+	} else {
+		customer.Email = receipt.Email
+	}
+
+	items := make([]*yoocommon.Item, 0, len(receipt.Items))
+	for _, item := range receipt.Items {
+		description := []rune(item.Description)
+		if len(description) > receiptDescriptionMaxLength {
+			description = description[:receiptDescriptionMaxLength]
+		}
+		items = append(items, &yoocommon.Item{
+			Description: string(description),
+			Quantity:    strconv.Itoa(item.Quantity),
+			Amount: &yoocommon.Amount{
+				Value:    formatAmount(item.Price),
+				Currency: "RUB",
+			},
+			VatCode: strconv.Itoa(p.vatCode),
+		})
+	}
 
-	return payment.Confirmation.(map[string]interface{})["confirmation_url"].(string), nil
+	return &yoopayment.Receipt{Customer: customer, Items: items}
+}
+
+// Payment - проведенный платеж по заказу
+type Payment struct {
+	ID      string
+	OrderID int64
+}
+
+// GetPayment запрашивает платеж в ЮKassa. Как и в GetRefund, данным
+// уведомления не доверяем: статус и заказ берутся из API.
+func (p *YookassaPayment) GetPayment(id string) (Payment, error) {
+	payment, err := yookassa.NewPaymentHandler(p.client).FindPayment(id)
+	if err != nil {
+		return Payment{}, err
+	}
+	if payment.Status != yoopayment.Succeeded || !payment.Paid {
+		return Payment{}, ErrPaymentNotSucceeded
+	}
+	orderID, err := paymentOrderID(payment)
+	if err != nil {
+		return Payment{}, err
+	}
+	return Payment{ID: payment.ID, OrderID: orderID}, nil
+}
+
+func paymentOrderID(payment *yoopayment.Payment) (int64, error) {
+	metadata, _ := payment.Metadata.(map[string]interface{})
+	orderID, err := strconv.ParseInt(fmt.Sprint(metadata["order_id"]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("payment %s has no order_id: %w", payment.ID, err)
+	}
+	return orderID, nil
+}
+
+// Refund - проведенный возврат по заказу
+type Refund struct {
+	ID        string
+	OrderID   int64
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// GetRefund запрашивает возврат в ЮKassa. Данные уведомления не используются:
+// статус, сумма и заказ берутся из API, поэтому поддельное уведомление
+// не создаст возврат.
+func (p *YookassaPayment) GetRefund(id string) (Refund, error) {
+	r, err := yookassa.NewRefundHandler(p.client).FindRefund(id)
+	if err != nil {
+		return Refund{}, err
+	}
+	if r.Status != yoorefund.Succeeded || r.Amount == nil {
+		return Refund{}, ErrRefundNotSucceeded
+	}
+	amount, err := strconv.ParseFloat(r.Amount.Value, 64)
+	if err != nil {
+		return Refund{}, fmt.Errorf("invalid refund amount %q: %w", r.Amount.Value, err)
+	}
+
+	payment, err := yookassa.NewPaymentHandler(p.client).FindPayment(r.PaymentId)
+	if err != nil {
+		return Refund{}, err
+	}
+	orderID, err := paymentOrderID(payment)
+	if err != nil {
+		return Refund{}, err
+	}
+
+	refund := Refund{ID: r.Id, OrderID: orderID, Amount: amount, CreatedAt: time.Now()}
+	if r.CreatedAt != nil {
+		refund.CreatedAt = *r.CreatedAt
+	}
+	return refund, nil
 }
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/requisites"
+	"gorm.io/gorm"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrPayoutNotFound      = errors.New("payout not found")
+	ErrPayoutBatchNotFound = errors.New("payout batch not found")
+	ErrPayoutNotPending    = errors.New("payout is already settled")
+)
+
+const (
+	payoutBatchesLimit  = 100
+	businessPayoutLimit = 100
+)
+
+// PayoutService формирует реестры выплат продавцам. Деньги отправляет
+// бухгалтерия по выгруженному реестру и отмечает ответ банка по каждой выплате.
+type PayoutService struct {
+	repo       *repo.PayoutRepo
+	ledgerRepo *repo.LedgerRepo
+	ledger     *LedgerService
+	policy     *PolicyService
+	cfg        config.FinanceConfig
+}
+
+func NewPayoutService(repo *repo.PayoutRepo, ledgerRepo *repo.LedgerRepo, ledger *LedgerService, policy *PolicyService, cfg config.FinanceConfig) *PayoutService {
+	return &PayoutService{
+		repo:       repo,
+		ledgerRepo: ledgerRepo,
+		ledger:     ledger,
+		policy:     policy,
+		cfg:        cfg,
+	}
+}
+
+// GenerateBatch собирает в реестр доступные балансы продавцов не меньше
+// минимальной суммы. Выплаты получают только подтвержденные бизнесы,
+// баланс остальных копится до подтверждения.
+func (s *PayoutService) GenerateBatch(ctx context.Context) error {
+	if err := s.ledger.PostMissingCaptures(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-s.cfg.PayoutHold)
+	balances, err := s.ledgerRepo.GetAvailableBalances(cutoff)
+	if err != nil {
+		return err
+	}
+
+	minAmount := kopecks(s.cfg.PayoutMin)
+	var payouts []model.Payout
+	for _, b := range balances {
+		if b.Amount < minAmount {
+			continue
+		}
+		err := s.policy.RequireVerifiedBusiness(b.BusinessID)
+		if errors.Is(err, ErrBusinessNotVerified) || errors.Is(err, ErrBusinessNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		payouts = append(payouts, model.Payout{BusinessID: b.BusinessID, Amount: b.Amount})
+	}
+	if len(payouts) == 0 {
+		return nil
+	}
+
+	batch, err := s.repo.CreateBatch(payouts, cutoff, now)
+	if err != nil {
+		return err
+	}
+	if batch.ID != 0 {
+		slog.Info("payout batch created",
+			slog.Int64("batch_id", batch.ID), slog.Int("count", batch.Count), slog.String("total", formatKopecks(batch.Total)))
+	}
+	return nil
+}
+
+func (s *PayoutService) GetBatches() ([]model.PayoutBatch, error) {
+	batches, err := s.repo.GetBatches(payoutBatchesLimit)
+	if batches == nil {
+		batches = []model.PayoutBatch{}
+	}
+	return batches, err
+}
+
+func (s *PayoutService) GetBatch(id int64) (model.PayoutBatch, error) {
+	batch, err := s.repo.GetBatch(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.PayoutBatch{}, ErrPayoutBatchNotFound
+	}
+	return batch, err
+}
+
+func (s *PayoutService) GetBusinessPayouts(businessID int64) ([]model.Payout, error) {
+	payouts, err := s.repo.GetBusinessPayouts(businessID, businessPayoutLimit)
+	if payouts == nil {
+		payouts = []model.Payout{}
+	}
+	return payouts, err
+}
+
+// ExportBatchCSV пишет реестр ожидающих выплат для загрузки в банк
+func (s *PayoutService) ExportBatchCSV(w io.Writer, batchID int64) error {
+	rows, err := s.repo.GetExportRows(batchID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, csvBOM); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	records := [][]string{{"payout_id", "business_id", "inn", "name", "amount", "purpose"}}
+	for _, row := range rows {
+		id := strconv.FormatInt(row.PayoutID, 10)
+		records = append(records, []string{
+			id,
+			strconv.FormatInt(row.BusinessID, 10),
+			requisites.FormatINN(row.INN),
+			csvText(row.Name),
+			formatKopecks(row.Amount),
+			"Выплата №" + id + " по расчетам с продавцом",
+		})
+	}
+	return cw.WriteAll(records)
+}
+
+// MarkPaid отмечает выплату проведенной банком
+func (s *PayoutService) MarkPaid(id int64) (model.Payout, error) {
+	return s.settle(id, model.PayoutPaid, "")
+}
+
+// MarkFailed отмечает, что банк не провел выплату; сумма возвращается на баланс продавца
+func (s *PayoutService) MarkFailed(id int64, reason string) (model.Payout, error) {
+	return s.settle(id, model.PayoutFailed, reason)
+}
+
+func (s *PayoutService) settle(id int64, status model.PayoutStatus, reason string) (model.Payout, error) {
+	ok, err := s.repo.SettlePayout(id, status, reason, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Payout{}, ErrPayoutNotFound
+	}
+	if err != nil {
+		return model.Payout{}, err
+	}
+	if !ok {
+		return model.Payout{}, ErrPayoutNotPending
+	}
+	return s.repo.GetPayoutByID(id)
+}
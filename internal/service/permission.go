@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"gorm.io/gorm"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrAdminLockout      = errors.New("admin role must keep role.manage permission")
+)
+
+type cachedPermissions struct {
+	perms     map[model.Permission]struct{}
+	fetchedAt time.Time
+}
+
+// PermissionService хранит соответствие ролей и прав.
+// Роль пользователя читается из базы при каждой проверке, а права ролей
+// кешируются на cacheTTL, поэтому изменения применяются без перевыпуска токенов.
+type PermissionService struct {
+	repo     *repo.PermissionRepo
+	userRepo *repo.UserRepo
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[model.UserRoleType]cachedPermissions
+}
+
+func NewPermissionService(repo *repo.PermissionRepo, userRepo *repo.UserRepo, cacheTTL time.Duration) *PermissionService {
+	return &PermissionService{
+		repo:     repo,
+		userRepo: userRepo,
+		cacheTTL: cacheTTL,
+		cache:    make(map[model.UserRoleType]cachedPermissions),
+	}
+}
+
+// EnsureDefaults заполняет права ролей значениями по умолчанию, если таблица
+// пуста. В уже заполненной базе роли получают только права, появившиеся
+// в новой версии приложения: например, admin получает новое право finance.manage.
+func (s *PermissionService) EnsureDefaults(ctx context.Context) error {
+	count, err := s.repo.CountRolePermissions()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		for role, perms := range model.DefaultRolePermissions {
+			if err := s.repo.SetRolePermissions(role, perms); err != nil {
+				return fmt.Errorf("cannot seed permissions for %s: %w", role, err)
+			}
+		}
+	}
+
+	known, err := s.repo.GetKnownPermissions()
+	if err != nil {
+		return err
+	}
+	if count > 0 && len(known) == 0 {
+		// База заполнена до появления known_permissions. Выданные права уже
+		// существовали, и их отзыв администратором нельзя отменять; новыми
+		// считаются только права, которых нет ни у одной роли.
+		granted, err := s.repo.GetGrantedPermissions()
+		if err != nil {
+			return err
+		}
+		if err := s.repo.GrantNewPermissions(nil, granted); err != nil {
+			return fmt.Errorf("cannot record known permissions: %w", err)
+		}
+		known = granted
+	}
+	var added []model.Permission
+	for _, p := range model.AllPermissions {
+		if !slices.Contains(known, p) {
+			added = append(added, p)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	var rows []model.RolePermission
+	for role, perms := range model.DefaultRolePermissions {
+		for _, p := range perms {
+			if slices.Contains(added, p) {
+				rows = append(rows, model.RolePermission{Role: role, Permission: p})
+			}
+		}
+	}
+	if err := s.repo.GrantNewPermissions(rows, added); err != nil {
+		return fmt.Errorf("cannot grant new permissions: %w", err)
+	}
+	return nil
+}
+
+// HasPermission проверяет, что текущая роль пользователя содержит все перечисленные права
+func (s *PermissionService) HasPermission(ctx context.Context, userID int64, perms ...model.Permission) (bool, error) {
+	role, err := s.UserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return s.RoleHasPermission(ctx, role, perms...)
+}
+
+// UserRole возвращает актуальную роль пользователя из базы
+func (s *PermissionService) UserRole(ctx context.Context, userID int64) (model.UserRoleType, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+func (s *PermissionService) RoleHasPermission(ctx context.Context, role model.UserRoleType, perms ...model.Permission) (bool, error) {
+	granted, err := s.rolePermissions(role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range perms {
+		if _, ok := granted[p]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *PermissionService) rolePermissions(role model.UserRoleType) (map[model.Permission]struct{}, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[role]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < s.cacheTTL {
+		return cached.perms, nil
+	}
+
+	list, err := s.repo.GetRolePermissions(role)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make(map[model.Permission]struct{}, len(list))
+	for _, p := range list {
+		perms[p] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.cache[role] = cachedPermissions{perms: perms, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	return perms, nil
+}
+
+// GetRoles возвращает все роли с их правами
+func (s *PermissionService) GetRoles(ctx context.Context) ([]model.RolePermissions, error) {
+	rows, err := s.repo.GetAllRolePermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[model.UserRoleType][]model.Permission, len(model.AllRoles))
+	for _, row := range rows {
+		byRole[row.Role] = append(byRole[row.Role], row.Permission)
+	}
+
+	roles := make([]model.RolePermissions, 0, len(model.AllRoles))
+	for _, role := range model.AllRoles {
+		perms := byRole[role]
+		if perms == nil {
+			perms = []model.Permission{}
+		}
+		roles = append(roles, model.RolePermissions{Role: role, Permissions: perms})
+	}
+	return roles, nil
+}
+
+// SetRolePermissions заменяет права роли и сбрасывает кеш
+func (s *PermissionService) SetRolePermissions(ctx context.Context, role model.UserRoleType, perms []model.Permission) (model.RolePermissions, error) {
+	if !slices.Contains(model.AllRoles, role) {
+		return model.RolePermissions{}, ErrUnknownRole
+	}
+	for _, p := range perms {
+		if !slices.Contains(model.AllPermissions, p) {
+			return model.RolePermissions{}, fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+	}
+	if role == model.AdminRole && !slices.Contains(perms, model.PermRoleManage) {
+		return model.RolePermissions{}, ErrAdminLockout
+	}
+
+	perms = slices.Clone(perms)
+	slices.Sort(perms)
+	perms = slices.Compact(perms)
+	if err := s.repo.SetRolePermissions(role, perms); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, role)
+	s.mu.Unlock()
+
+	return model.RolePermissions{Role: role, Permissions: perms}, nil
+}
+
+// SetUserRole меняет роль пользователя; новые права действуют со следующего запроса
+func (s *PermissionService) SetUserRole(ctx context.Context, userID int64, role model.UserRoleType) error {
+	if !slices.Contains(model.AllRoles, role) {
+		return ErrUnknownRole
+	}
+
+	if _, err := s.UserRole(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.userRepo.SetRole(userID, role)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/ratelimit"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/sms"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrInvalidPhone         = sms.ErrInvalidPhone
+	ErrPhoneExists          = errors.New("phone already exists")
+	ErrPhoneAlreadyVerified = errors.New("phone is already verified")
+	ErrTooManySMS           = errors.New("too many sms sent to this number, try again later")
+)
+
+// Причина неудачного входа по SMS в журнале аудита
+const loginFailWrongCode = "wrong_code"
+
+// PhoneOTP выдает и проверяет одноразовые коды из SMS. Отправка ограничена
+// паузой между кодами и лимитом на номер, чтобы через нас нельзя было
+// заваливать чужой телефон сообщениями и тратить деньги на шлюз.
+type PhoneOTP struct {
+	repo     *repo.PhoneCodeRepo
+	sender   sms.Sender
+	limiter  ratelimit.Limiter
+	rate     ratelimit.Rate
+	ttl      time.Duration
+	cooldown time.Duration
+}
+
+func NewPhoneOTP(repo *repo.PhoneCodeRepo, sender sms.Sender, limiter ratelimit.Limiter, cfg config.SMSConfig) (*PhoneOTP, error) {
+	rate, err := ratelimit.ParseRate(cfg.RatePerNumber)
+	if err != nil {
+		return nil, fmt.Errorf("SMS_RATE_PER_NUMBER: %w", err)
+	}
+	return &PhoneOTP{
+		repo:     repo,
+		sender:   sender,
+		limiter:  limiter,
+		rate:     rate,
+		ttl:      cfg.CodeTTL,
+		cooldown: cfg.ResendCooldown,
+	}, nil
+}
+
+// Send отправляет новый код на номер; прежние коды с той же целью перестают действовать.
+// Возвращает *RetryAfterError, если отправлять еще рано.
+func (o *PhoneOTP) Send(ctx context.Context, phone string, purpose model.PhoneCodePurpose, userID *int64) error {
+	now := time.Now()
+
+	latest, err := o.repo.GetLatestPhoneCode(phone, purpose)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil {
+		if wait := latest.SentAt.Add(o.cooldown).Sub(now); wait > 0 {
+			return &RetryAfterError{Err: ErrResendCooldown, RetryAfter: wait}
+		}
+	}
+
+	res, err := o.limiter.Allow(ctx, "sms:"+phone, o.rate)
+	if err != nil {
+		// Пауза между кодами продолжает действовать и без лимитера
+		slog.Error("sms rate limiter failed", sl.Err(err))
+	} else if !res.Allowed {
+		return &RetryAfterError{Err: ErrTooManySMS, RetryAfter: res.RetryAfter}
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+	_, err = o.repo.ReplacePhoneCode(model.PhoneCode{
+		Phone:     phone,
+		Purpose:   purpose,
+		UserID:    userID,
+		CodeHash:  hashRefreshToken(code),
+		ExpiresAt: now.Add(o.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("Код подтверждения: %s. Никому его не сообщайте.", code)
+	if purpose == model.PhoneCodeLogin {
+		text = fmt.Sprintf("Код для входа: %s. Никому его не сообщайте.", code)
+	}
+	return o.sender.Send(ctx, phone, text)
+}
+
+// Verify проверяет код и гасит его. После verificationCodeMaxAttempts
+// неверных попыток код удаляется, и нужно запросить новый.
+func (o *PhoneOTP) Verify(phone string, purpose model.PhoneCodePurpose, code string) (model.PhoneCode, error) {
+	latest, err := o.repo.GetLatestPhoneCode(phone, purpose)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.PhoneCode{}, ErrInvalidCode
+	}
+	if err != nil {
+		return model.PhoneCode{}, err
+	}
+
+	if time.Now().After(latest.ExpiresAt) {
+		return model.PhoneCode{}, ErrCodeExpired
+	}
+	if latest.Attempts >= verificationCodeMaxAttempts {
+		return model.PhoneCode{}, ErrTooManyCodeAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(latest.CodeHash), []byte(hashRefreshToken(code))) != 1 {
+		attempts, err := o.repo.IncrementPhoneCodeAttempts(latest.ID)
+		if err != nil {
+			return model.PhoneCode{}, err
+		}
+		if attempts >= verificationCodeMaxAttempts {
+			if err := o.repo.DeletePhoneCodes(phone, purpose); err != nil {
+				return model.PhoneCode{}, err
+			}
+			return model.PhoneCode{}, ErrTooManyCodeAttempts
+		}
+		return model.PhoneCode{}, ErrInvalidCode
+	}
+
+	return latest, o.repo.DeletePhoneCodes(phone, purpose)
+}
+
+// Cleanup удаляет просроченные коды
+func (o *PhoneOTP) Cleanup(ctx context.Context) error {
+	return o.repo.DeleteExpiredPhoneCodes(time.Now())
+}
+
+// phoneOwner возвращает ошибку, если номер уже подтвержден другим пользователем
+func (s *UserService) phoneOwner(phone string, userID int64) error {
+	owner, err := s.repo.GetUserByPhone(phone)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if owner.ID != userID {
+		return ErrPhoneExists
+	}
+	return ErrPhoneAlreadyVerified
+}
+
+// RequestPhoneVerification отправляет код на номер, который пользователь хочет привязать
+func (s *UserService) RequestPhoneVerification(ctx context.Context, userID int64, rawPhone string) error {
+	phone, err := sms.NormalizePhone(rawPhone)
+	if err != nil {
+		return err
+	}
+	if err := s.phoneOwner(phone, userID); err != nil {
+		return err
+	}
+	return s.otp.Send(ctx, phone, model.PhoneCodeVerify, &userID)
+}
+
+// VerifyPhone привязывает номер после ввода кода из SMS
+func (s *UserService) VerifyPhone(userID int64, rawPhone string, code string) error {
+	phone, err := sms.NormalizePhone(rawPhone)
+	if err != nil {
+		return err
+	}
+
+	phoneCode, err := s.otp.Verify(phone, model.PhoneCodeVerify, code)
+	if err != nil {
+		return err
+	}
+	// Код был выдан другому пользователю
+	if phoneCode.UserID == nil || *phoneCode.UserID != userID {
+		return ErrInvalidCode
+	}
+	if err := s.phoneOwner(phone, userID); err != nil {
+		return err
+	}
+
+	return s.repo.SetPhone(userID, &phone)
+}
+
+// RemovePhone отвязывает номер; вход по SMS становится недоступен
+func (s *UserService) RemovePhone(userID int64) error {
+	return s.repo.SetPhone(userID, nil)
+}
+
+// RequestPhoneLogin отправляет код для входа. Для неизвестного номера SMS
+// не отправляется, но ответ тот же, чтобы нельзя было перебирать номера.
+func (s *UserService) RequestPhoneLogin(ctx context.Context, rawPhone string) error {
+	phone, err := sms.NormalizePhone(rawPhone)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByPhone(phone)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.otp.Send(ctx, phone, model.PhoneCodeLogin, &user.ID)
+}
+
+// PhoneLogin выдает токены по коду из SMS. Неверные коды учитываются
+// LoginGuard так же, как неверные пароли.
+func (s *UserService) PhoneLogin(ctx context.Context, rawPhone string, code string, meta model.SessionMeta) (model.Token, error) {
+	phone, err := sms.NormalizePhone(rawPhone)
+	if err != nil {
+		return model.Token{}, err
+	}
+	if err := s.guard.Check(ctx, phone, meta); err != nil {
+		return model.Token{}, err
+	}
+
+	phoneCode, err := s.otp.Verify(phone, model.PhoneCodeLogin, code)
+	if errors.Is(err, ErrInvalidCode) || errors.Is(err, ErrTooManyCodeAttempts) {
+		if _, failErr := s.guard.Fail(ctx, phone, nil, loginFailWrongCode, meta); failErr != nil {
+			return model.Token{}, failErr
+		}
+		return model.Token{}, err
+	}
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	user, err := s.repo.GetUserByPhone(phone)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && phoneCode.UserID != nil && *phoneCode.UserID != user.ID) {
+		// Номер успели отвязать или передать другому пользователю
+		return model.Token{}, ErrInvalidCode
+	}
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	if err := s.guard.Succeed(ctx, phone); err != nil {
+		return model.Token{}, err
+	}
+
+	if token, pending, err := s.loginStep(user); err != nil || pending {
+		return token, err
+	}
+	return s.GenerateNewToken(user, meta)
+}
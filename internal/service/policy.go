@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"gorm.io/gorm"
+	"slices"
+)
+
+var ErrForbidden = errors.New("access denied")
+
+// Action - тип операции над ресурсом
+type Action string
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+	// ActionProducts - создание и изменение товаров бизнеса
+	ActionProducts Action = "products"
+	// ActionMembers - управление участниками и приглашениями
+	ActionMembers Action = "members"
+	// ActionFinance - выручка, выплаты и выписки
+	ActionFinance Action = "finance"
+	// ActionOwn - удаление бизнеса и передача владения
+	ActionOwn Action = "own"
+)
+
+// actionRoles - роли участников, которым разрешено действие
+var actionRoles = map[Action][]model.MemberRole{
+	ActionRead:     {model.MemberOwner, model.MemberManager, model.MemberContentEditor, model.MemberAccountant},
+	ActionWrite:    {model.MemberOwner, model.MemberManager},
+	ActionProducts: {model.MemberOwner, model.MemberManager, model.MemberContentEditor},
+	ActionMembers:  {model.MemberOwner, model.MemberManager},
+	ActionFinance:  {model.MemberOwner, model.MemberManager, model.MemberAccountant},
+	ActionOwn:      {model.MemberOwner},
+}
+
+// PolicyService решает, может ли пользователь выполнить действие над товаром или бизнесом.
+// Право business.manage разрешает любые действия с любым бизнесом, business.read - читать любой,
+// остальные пользователи работают только с бизнесами, в которых они состоят, в пределах своей роли.
+type PolicyService struct {
+	productRepo  PolicyProducts
+	businessRepo PolicyBusinesses
+	permissions  PermissionChecker
+}
+
+// PolicyProducts - товары, к которым PolicyService проверяет доступ; реализуется repo.ProductRepo
+type PolicyProducts interface {
+	GetProductByID(ctx context.Context, id int64) (*model.Product, error)
+}
+
+// PolicyBusinesses - бизнесы и роли участников; реализуется repo.BusinessRepo
+type PolicyBusinesses interface {
+	GetBusinessByID(id int64) (model.Business, error)
+	GetMemberRole(userID int64, businessID int64) (model.MemberRole, bool, error)
+}
+
+// PermissionChecker проверяет глобальные права пользователя; реализуется PermissionService
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userID int64, perms ...model.Permission) (bool, error)
+}
+
+func NewPolicyService(productRepo PolicyProducts, businessRepo PolicyBusinesses, permissions PermissionChecker) *PolicyService {
+	return &PolicyService{
+		productRepo:  productRepo,
+		businessRepo: businessRepo,
+		permissions:  permissions,
+	}
+}
+
+// AuthorizeBusiness проверяет доступ к бизнесу businessID
+func (p *PolicyService) AuthorizeBusiness(ctx context.Context, userID int64, businessID int64, action Action) error {
+	if _, err := p.businessRepo.GetBusinessByID(businessID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBusinessNotFound
+		}
+		return err
+	}
+
+	return p.authorize(ctx, userID, businessID, action)
+}
+
+// RequireVerifiedBusiness возвращает ErrBusinessNotVerified, если бизнес не прошел
+// проверку. Публиковать товары и получать выплаты может только подтвержденный бизнес.
+func (p *PolicyService) RequireVerifiedBusiness(businessID int64) error {
+	business, err := p.businessRepo.GetBusinessByID(businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBusinessNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if business.VerificationStatus != model.BusinessVerified {
+		return ErrBusinessNotVerified
+	}
+	return nil
+}
+
+// AuthorizeProduct проверяет доступ к товару productID через бизнес, которому он принадлежит
+func (p *PolicyService) AuthorizeProduct(ctx context.Context, userID int64, productID int64, action Action) error {
+	product, err := p.productRepo.GetProductByID(ctx, productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.authorize(ctx, userID, product.BusinessID, action)
+}
+
+func (p *PolicyService) authorize(ctx context.Context, userID int64, businessID int64, action Action) error {
+	override := model.PermBusinessManage
+	if action == ActionRead {
+		override = model.PermBusinessRead
+	}
+
+	ok, err := p.permissions.HasPermission(ctx, userID, override)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	role, ok, err := p.businessRepo.GetMemberRole(userID, businessID)
+	if err != nil {
+		return err
+	}
+	if !ok || !slices.Contains(actionRoles[action], role) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/RCSE2025/backend-go/internal/email"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"html/template"
+	"io"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrExportNotFound       = errors.New("data export not found")
+	ErrExportNotReady       = errors.New("data export is not ready yet")
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	// ErrSoleBusinessOwner - без владельца бизнесом никто не сможет управлять
+	ErrSoleBusinessOwner = errors.New("transfer ownership of your businesses before deleting the account")
+)
+
+// PrivacyService - права субъекта персональных данных по 152-ФЗ:
+// выгрузка своих данных и удаление аккаунта с обезличиванием
+type PrivacyService struct {
+	repo        *repo.PrivacyRepo
+	users       *repo.UserRepo
+	orders      *repo.OrderRepo
+	addresses   *repo.AddressRepo
+	oauth       *repo.OAuthRepo
+	blob        storage.Blob
+	mailer      *email.Mailer
+	sessions    *SessionChecker
+	frontendURL string
+	cfg         config.PrivacyConfig
+}
+
+func NewPrivacyService(repo *repo.PrivacyRepo, users *repo.UserRepo, orders *repo.OrderRepo, addresses *repo.AddressRepo, oauth *repo.OAuthRepo, blob storage.Blob, mailer *email.Mailer, sessions *SessionChecker, frontendURL string, cfg config.PrivacyConfig) *PrivacyService {
+	return &PrivacyService{
+		repo:        repo,
+		users:       users,
+		orders:      orders,
+		addresses:   addresses,
+		oauth:       oauth,
+		blob:        blob,
+		mailer:      mailer,
+		sessions:    sessions,
+		frontendURL: frontendURL,
+		cfg:         cfg,
+	}
+}
+
+func (s *PrivacyService) activeUser(userID int64) (model.User, error) {
+	user, err := s.users.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.AnonymizedAt != nil) {
+		return model.User{}, ErrUserNotFound
+	}
+	return user, err
+}
+
+// RequestExport ставит выгрузку в очередь. Если предыдущая еще готовится,
+// возвращается она.
+func (s *PrivacyService) RequestExport(userID int64) (model.DataExport, error) {
+	if _, err := s.activeUser(userID); err != nil {
+		return model.DataExport{}, err
+	}
+
+	latest, err := s.repo.GetLatestExport(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DataExport{}, err
+	}
+	if err == nil && (latest.Status == model.DataExportPending || latest.Status == model.DataExportProcessing) {
+		return latest, nil
+	}
+
+	return s.repo.CreateExport(model.DataExport{UserID: userID, Status: model.DataExportPending})
+}
+
+func (s *PrivacyService) LatestExport(userID int64) (model.DataExport, error) {
+	export, err := s.repo.GetLatestExport(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DataExport{}, ErrExportNotFound
+	}
+	return export, err
+}
+
+// OpenExport открывает последний готовый архив для скачивания
+func (s *PrivacyService) OpenExport(ctx context.Context, userID int64) (io.ReadCloser, storage.ObjectInfo, error) {
+	export, err := s.LatestExport(userID)
+	if err != nil {
+		return nil, storage.ObjectInfo{}, err
+	}
+	if export.Status != model.DataExportReady {
+		return nil, storage.ObjectInfo{}, ErrExportNotReady
+	}
+
+	rc, info, err := s.blob.Get(ctx, export.ObjectKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, storage.ObjectInfo{}, ErrExportNotFound
+	}
+	return rc, info, err
+}
+
+// ProcessExports готовит архивы для всех выгрузок в очереди
+func (s *PrivacyService) ProcessExports(ctx context.Context) error {
+	for ctx.Err() == nil {
+		now := time.Now()
+		export, err := s.repo.ClaimExport(now, now.Add(-s.cfg.ExportTimeout))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		key, size, err := s.buildExport(ctx, export)
+		done := time.Now()
+		if err != nil {
+			slog.Error("cannot build data export", slog.Int64("export_id", export.ID), sl.Err(err))
+			if err := s.repo.FailExport(export.ID, err.Error(), done, done.Add(s.cfg.ExportTTL)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		expiresAt := done.Add(s.cfg.ExportTTL)
+		if err := s.repo.FinishExport(export.ID, key, size, done, expiresAt); err != nil {
+			return err
+		}
+		s.sendExportReadyEmail(export.UserID, expiresAt)
+	}
+	return ctx.Err()
+}
+
+// exportedAddress - адрес, на который пользователь оформлял заказы
+type exportedAddress struct {
+	Address  string  `json:"address"`
+	OrderIDs []int64 `json:"order_ids"`
+}
+
+type exportedAddresses struct {
+	Saved  []model.UserAddress `json:"saved"`
+	Orders []exportedAddress   `json:"orders"`
+}
+
+type exportedProfile struct {
+	User       model.User           `json:"user"`
+	Identities []model.UserIdentity `json:"identities"`
+	ExportedAt time.Time            `json:"exported_at"`
+}
+
+func (s *PrivacyService) buildExport(ctx context.Context, export model.DataExport) (string, int64, error) {
+	user, err := s.activeUser(export.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+	identities, err := s.oauth.GetUserIdentities(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	orders, err := s.orders.GetUserOrders(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	reviews, err := s.repo.GetUserReviews(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	sessions, err := s.repo.GetUserSessions(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	saved, err := s.addresses.GetUserAddresses(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	addresses := make([]exportedAddress, 0)
+	byAddress := make(map[string]int)
+	for _, order := range orders {
+		if order.Address == "" {
+			continue
+		}
+		i, ok := byAddress[order.Address]
+		if !ok {
+			i = len(addresses)
+			byAddress[order.Address] = i
+			addresses = append(addresses, exportedAddress{Address: order.Address})
+		}
+		addresses[i].OrderIDs = append(addresses[i].OrderIDs, order.ID)
+	}
+
+	now := time.Now()
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", exportedProfile{User: user, Identities: identities, ExportedAt: now}},
+		{"orders.json", orders},
+		{"reviews.json", reviews},
+		{"sessions.json", sessions},
+		{"addresses.json", exportedAddresses{Saved: saved, Orders: addresses}},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return "", 0, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return "", 0, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("%d/%d.zip", user.ID, export.ID)
+	size := int64(buf.Len())
+	if err := s.blob.Put(ctx, key, bytes.NewReader(buf.Bytes()), size, "application/zip"); err != nil {
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// CleanupExports удаляет просроченные архивы
+func (s *PrivacyService) CleanupExports(ctx context.Context) error {
+	exports, err := s.repo.GetExpiredExports(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := s.deleteExport(ctx, export); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PrivacyService) deleteExport(ctx context.Context, export model.DataExport) error {
+	if export.ObjectKey != "" {
+		if err := s.blob.Delete(ctx, export.ObjectKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return err
+		}
+	}
+	return s.repo.DeleteExport(export.ID)
+}
+
+// ScheduleDeletion назначает удаление аккаунта по истечении срока на отмену.
+// Повторный запрос возвращает уже назначенную дату.
+func (s *PrivacyService) ScheduleDeletion(userID int64) (model.AccountDeletion, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return model.AccountDeletion{}, err
+	}
+	if user.DeletionScheduledAt != nil {
+		return model.AccountDeletion{ScheduledAt: *user.DeletionScheduledAt}, nil
+	}
+	if err := s.requireNoSoleOwnership(userID); err != nil {
+		return model.AccountDeletion{}, err
+	}
+
+	at := time.Now().Add(s.cfg.DeletionGracePeriod)
+	scheduled, err := s.repo.ScheduleDeletion(userID, at)
+	if err != nil {
+		return model.AccountDeletion{}, err
+	}
+	if !scheduled {
+		// Удаление назначили параллельным запросом
+		return s.scheduledDeletion(userID)
+	}
+
+	s.sendDeletionScheduledEmail(user, at)
+	return model.AccountDeletion{ScheduledAt: at}, nil
+}
+
+func (s *PrivacyService) scheduledDeletion(userID int64) (model.AccountDeletion, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return model.AccountDeletion{}, err
+	}
+	if user.DeletionScheduledAt == nil {
+		return model.AccountDeletion{}, ErrDeletionNotScheduled
+	}
+	return model.AccountDeletion{ScheduledAt: *user.DeletionScheduledAt}, nil
+}
+
+func (s *PrivacyService) CancelDeletion(userID int64) error {
+	canceled, err := s.repo.CancelDeletion(userID)
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return ErrDeletionNotScheduled
+	}
+	return nil
+}
+
+// requireNoSoleOwnership возвращает ErrSoleBusinessOwner, если пользователь -
+// последний владелец какого-либо бизнеса: сначала нужно передать владение
+func (s *PrivacyService) requireNoSoleOwnership(userID int64) error {
+	owned, err := s.repo.GetSoleOwnedBusinesses(userID)
+	if err != nil {
+		return err
+	}
+	if len(owned) > 0 {
+		return fmt.Errorf("%w: %v", ErrSoleBusinessOwner, owned)
+	}
+	return nil
+}
+
+// Erase сразу обезличивает аккаунт, без срока на отмену
+func (s *PrivacyService) Erase(ctx context.Context, userID int64) error {
+	if _, err := s.activeUser(userID); err != nil {
+		return err
+	}
+	// Второй владелец мог выйти из бизнеса уже после запроса на удаление
+	if err := s.requireNoSoleOwnership(userID); err != nil {
+		return err
+	}
+
+	exports, err := s.repo.GetUserExports(userID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := s.deleteExport(ctx, export); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.AnonymizeUser(userID, time.Now()); err != nil {
+		return err
+	}
+	// Сессии удалены из базы, но кеш принимал бы их токены доступа до истечения TTL
+	s.sessions.ForgetUser(userID)
+	return nil
+}
+
+// EraseDueAccounts обезличивает аккаунты, срок отмены удаления которых истек
+func (s *PrivacyService) EraseDueAccounts(ctx context.Context) error {
+	ids, err := s.repo.GetUsersDueForErasure(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := s.Erase(ctx, id)
+		if errors.Is(err, ErrSoleBusinessOwner) {
+			// Удаление откладывается, пока владелец не передаст бизнес
+			slog.Warn("account erasure deferred", slog.Int64("user_id", id), sl.Err(err))
+			continue
+		}
+		if err != nil {
+			slog.Error("cannot erase account", slog.Int64("user_id", id), sl.Err(err))
+		}
+	}
+	return nil
+}
+
+const deletionScheduledEmailTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Удаление аккаунта</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Здравствуйте, {{.Name}}!</p>
+            <p style="font-size: 16px; line-height: 1.5;">Мы получили запрос на удаление вашего аккаунта. {{.Date}} персональные данные будут обезличены, войти в аккаунт станет невозможно.</p>
+            <p style="font-size: 14px; line-height: 1.5;">Сведения о заказах сохранятся без привязки к вашим данным: их требует законодательство о бухгалтерском учете.</p>
+            <p>
+                <a href="{{.ProfileLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Отменить удаление</a>
+            </p>
+            <p style="font-size: 14px; color: #666;">Если вы не запрашивали удаление, войдите в аккаунт, отмените его и смените пароль.</p>
+        </div>
+    </body>
+</html>
+`
+
+func makeDeletionScheduledEmailTemplate(name string, at time.Time, profileLink string) (string, error) {
+	tmpl, err := template.New("deletion_scheduled_email").Parse(deletionScheduledEmailTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Name        string
+		Date        string
+		ProfileLink string
+	}{
+		Name:        name,
+		Date:        at.Format("02.01.2006"),
+		ProfileLink: profileLink,
+	})
+
+	return body.String(), err
+}
+
+func (s *PrivacyService) sendDeletionScheduledEmail(user model.User, at time.Time) {
+	body, err := makeDeletionScheduledEmailTemplate(user.Name, at, fmt.Sprintf("%s/profile", s.frontendURL))
+	if err != nil {
+		slog.Error("cannot make deletion scheduled email", sl.Err(err))
+		return
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(user.Email, "Удаление аккаунта", body); err != nil {
+			slog.Error("cannot send deletion scheduled email", sl.Err(err))
+		}
+	}()
+}
+
+const exportReadyEmailTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Ваши данные готовы</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Здравствуйте, {{.Name}}!</p>
+            <p style="font-size: 16px; line-height: 1.5;">Архив с вашими персональными данными готов. Скачать его можно в профиле до {{.Date}}.</p>
+            <p>
+                <a href="{{.ProfileLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Перейти в профиль</a>
+            </p>
+        </div>
+    </body>
+</html>
+`
+
+func makeExportReadyEmailTemplate(name string, expiresAt time.Time, profileLink string) (string, error) {
+	tmpl, err := template.New("export_ready_email").Parse(exportReadyEmailTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Name        string
+		Date        string
+		ProfileLink string
+	}{
+		Name:        name,
+		Date:        expiresAt.Format("02.01.2006"),
+		ProfileLink: profileLink,
+	})
+
+	return body.String(), err
+}
+
+func (s *PrivacyService) sendExportReadyEmail(userID int64, expiresAt time.Time) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		slog.Error("cannot get user for export email", sl.Err(err))
+		return
+	}
+	body, err := makeExportReadyEmailTemplate(user.Name, expiresAt, fmt.Sprintf("%s/profile", s.frontendURL))
+	if err != nil {
+		slog.Error("cannot make export ready email", sl.Err(err))
+		return
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(user.Email, "Выгрузка персональных данных", body); err != nil {
+			slog.Error("cannot send export ready email", sl.Err(err))
+		}
+	}()
+}
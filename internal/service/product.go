@@ -2,34 +2,91 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/RCSE2025/backend-go/internal/model"
 	"github.com/RCSE2025/backend-go/internal/repo"
-	"github.com/RCSE2025/backend-go/internal/utils"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log/slog"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrProductImageNotFound = errors.New("product image not found")
+	ErrInvalidImageOrder    = errors.New("image order must contain every product image exactly once")
 )
 
 // ProductService представляет сервис для работы с продуктами
 type ProductService struct {
 	repo           *repo.ProductRepo
-	s3Worker       *utils.S3WorkerAPI
-	s3WorkerReview *utils.S3WorkerAPI
+	productStorage storage.Blob
+	reviewStorage  storage.Blob
 }
 
 // NewProductService создает новый экземпляр ProductService
-func NewProductService(repo *repo.ProductRepo, s3Worker, s3WorkerReview *utils.S3WorkerAPI) *ProductService {
+func NewProductService(repo *repo.ProductRepo, productStorage, reviewStorage storage.Blob) *ProductService {
 	return &ProductService{
 		repo:           repo,
-		s3Worker:       s3Worker,
-		s3WorkerReview: s3WorkerReview,
+		productStorage: productStorage,
+		reviewStorage:  reviewStorage,
 	}
 }
 
-// GetS3Worker возвращает S3 Worker API
-func (s *ProductService) GetS3Worker() *utils.S3WorkerAPI {
-	return s.s3Worker
+// ProductStorage возвращает хранилище изображений товаров
+func (s *ProductService) ProductStorage() storage.Blob {
+	return s.productStorage
+}
+
+// ReviewStorage возвращает хранилище изображений отзывов
+func (s *ProductService) ReviewStorage() storage.Blob {
+	return s.reviewStorage
+}
+
+// UploadProductFile загружает файл товара в хранилище и возвращает его ключ
+func (s *ProductService) UploadProductFile(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	return uploadMultipart(ctx, s.productStorage, file)
+}
+
+// UploadReviewFile загружает файл отзыва в хранилище и возвращает его ключ
+func (s *ProductService) UploadReviewFile(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	return uploadMultipart(ctx, s.reviewStorage, file)
 }
 
-func (s *ProductService) GetS3WorkerReview() *utils.S3WorkerAPI {
-	return s.s3WorkerReview
+func uploadMultipart(ctx context.Context, blob storage.Blob, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("ошибка при открытии файла: %w", err)
+	}
+	defer src.Close()
+
+	key := uuid.NewString() + strings.ToLower(filepath.Ext(file.Filename))
+	if err := blob.Put(ctx, key, src, file.Size, imageMimeType(file.Filename)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// imageMimeType определяет MIME-тип на основе расширения файла
+func imageMimeType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
 }
 
 // GetProductByID возвращает продукт по его ID
@@ -42,9 +99,31 @@ func (s *ProductService) GetProductReviews(ctx context.Context, productID int64)
 	return s.repo.GetProductReviews(ctx, productID)
 }
 
-// AddProductReview добавляет новый отзыв на продукт
-func (s *ProductService) AddProductReview(ctx context.Context, review model.ProductReview) (*model.ProductReview, error) {
-	return s.repo.AddProductReview(ctx, review)
+// AddProductReview добавляет отзыв пользователя userID на продукт
+func (s *ProductService) AddProductReview(ctx context.Context, userID int64, productID int64, req model.ProductReviewRequest) (*model.ProductReview, error) {
+	if _, err := s.repo.GetProductByID(ctx, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	name, err := s.repo.GetUserName(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.AddProductReview(ctx, model.ProductReview{
+		ProductID: productID,
+		UserID:    userID,
+		UserName:  name,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+		Date:      time.Now(),
+	})
 }
 
 // FilterProducts фильтрует продукты по заданным критериям
@@ -72,9 +151,66 @@ func (s *ProductService) AddProductImage(ctx context.Context, image model.Produc
 	return s.repo.AddProductImage(ctx, image)
 }
 
-// DeleteProductImage удаляет изображение продукта
-func (s *ProductService) DeleteProductImage(ctx context.Context, imageID int64) error {
-	return s.repo.DeleteProductImage(ctx, imageID)
+// getProductImage возвращает изображение, если оно принадлежит продукту
+func (s *ProductService) getProductImage(ctx context.Context, productID, imageID int64) (model.ProductImage, error) {
+	image, err := s.repo.GetProductImage(ctx, imageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && image.ProductID != productID) {
+		return model.ProductImage{}, ErrProductImageNotFound
+	}
+	return image, err
+}
+
+// DeleteProductImage удаляет изображение продукта из базы и из хранилища
+func (s *ProductService) DeleteProductImage(ctx context.Context, productID, imageID int64) error {
+	image, err := s.getProductImage(ctx, productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteProductImage(ctx, imageID); err != nil {
+		return err
+	}
+
+	// Запись уже удалена, поэтому ошибка хранилища оставляет только неиспользуемый объект
+	if err := s.productStorage.Delete(ctx, image.FileUUID); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		slog.Error("cannot delete product image from storage", slog.String("key", image.FileUUID), sl.Err(err))
+	}
+	return nil
+}
+
+// SetPrimaryProductImage делает изображение основным
+func (s *ProductService) SetPrimaryProductImage(ctx context.Context, productID, imageID int64) error {
+	if _, err := s.getProductImage(ctx, productID, imageID); err != nil {
+		return err
+	}
+	return s.repo.SetPrimaryProductImage(ctx, productID, imageID)
+}
+
+// ReorderProductImages задает порядок изображений; список должен содержать все изображения продукта
+func (s *ProductService) ReorderProductImages(ctx context.Context, productID int64, imageIDs []int64) ([]model.ProductImage, error) {
+	images, err := s.repo.GetProductImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) != len(imageIDs) {
+		return nil, ErrInvalidImageOrder
+	}
+	existing := make(map[int64]bool, len(images))
+	for _, image := range images {
+		existing[image.ID] = true
+	}
+	for _, id := range imageIDs {
+		if !existing[id] {
+			return nil, ErrInvalidImageOrder
+		}
+		delete(existing, id)
+	}
+
+	if err := s.repo.ReorderProductImages(ctx, productID, imageIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProductImages(ctx, productID)
 }
 
 // GetProductImages возвращает изображения продукта
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"html/template"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session is revoked")
+)
+
+// sessionCacheLimit - при таком размере кеша из него удаляются устаревшие записи
+const sessionCacheLimit = 10000
+
+type cachedSession struct {
+	userID    int64
+	active    bool
+	fetchedAt time.Time
+}
+
+// SessionChecker проверяет, что сессия токена доступа не отозвана.
+// Ответы кешируются на ttl: отзыв на этом экземпляре приложения действует
+// сразу, на остальных - с задержкой до ttl.
+type SessionChecker struct {
+	repo *repo.TokenRepo
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedSession
+}
+
+func NewSessionChecker(repo *repo.TokenRepo, ttl time.Duration) *SessionChecker {
+	return &SessionChecker{
+		repo:  repo,
+		ttl:   ttl,
+		cache: make(map[string]cachedSession),
+	}
+}
+
+// Active возвращает false для отозванной, истекшей или удаленной сессии
+func (c *SessionChecker) Active(sessionID string) (bool, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	cached, ok := c.cache[sessionID]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < c.ttl {
+		return cached.active, nil
+	}
+
+	session, err := c.repo.GetSession(sessionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	cached = cachedSession{
+		userID:    session.UserID,
+		active:    err == nil && session.RevokedAt == nil && now.Before(session.ExpiresAt),
+		fetchedAt: now,
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= sessionCacheLimit {
+		for id, s := range c.cache {
+			if now.Sub(s.fetchedAt) >= c.ttl {
+				delete(c.cache, id)
+			}
+		}
+	}
+	c.cache[sessionID] = cached
+	c.mu.Unlock()
+	return cached.active, nil
+}
+
+// Forget сбрасывает кеш сессии после ее отзыва
+func (c *SessionChecker) Forget(sessionID string) {
+	c.mu.Lock()
+	delete(c.cache, sessionID)
+	c.mu.Unlock()
+}
+
+// ForgetUser сбрасывает кеш всех сессий пользователя
+func (c *SessionChecker) ForgetUser(userID int64) {
+	c.mu.Lock()
+	for id, s := range c.cache {
+		if s.userID == userID {
+			delete(c.cache, id)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// revokeSession отзывает сессию; токены доступа сессии перестают приниматься сразу
+func (s *UserService) revokeSession(id string, at time.Time) error {
+	if err := s.tokenRepo.RevokeSession(id, at); err != nil {
+		return err
+	}
+	s.sessions.Forget(id)
+	return nil
+}
+
+func (s *UserService) revokeUserSessions(userID int64, at time.Time) error {
+	if err := s.tokenRepo.RevokeUserSessions(userID, at); err != nil {
+		return err
+	}
+	s.sessions.ForgetUser(userID)
+	return nil
+}
+
+// startSession создает сессию для нового входа и предупреждает пользователя,
+// если вход выполнен с устройства, которого раньше не было
+func (s *UserService) startSession(user model.User, meta model.SessionMeta) (model.Session, error) {
+	deviceHash := hashDevice(meta.UserAgent)
+
+	known, err := s.tokenRepo.DeviceKnown(user.ID, deviceHash)
+	if err != nil {
+		return model.Session{}, err
+	}
+	count, err := s.tokenRepo.CountSessions(user.ID)
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	now := time.Now()
+	session, err := s.tokenRepo.CreateSession(model.Session{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		DeviceHash: deviceHash,
+		UserAgent:  truncate(meta.UserAgent, 512),
+		IP:         meta.IP,
+		Location:   s.geo.Locate(meta.IP),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL),
+	})
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	// Первый вход после регистрации не считается подозрительным
+	if !known && count > 0 {
+		s.sendNewDeviceEmail(user, session)
+	}
+
+	return session, nil
+}
+
+// GetSessions возвращает активные сессии пользователя; currentID помечается как текущая
+func (s *UserService) GetSessions(userID int64, currentID string) ([]model.Session, error) {
+	sessions, err := s.tokenRepo.GetActiveSessions(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession завершает сессию пользователя
+func (s *UserService) RevokeSession(userID int64, sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return ErrSessionNotFound
+	}
+
+	session, err := s.tokenRepo.GetSession(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (session.UserID != userID || session.RevokedAt != nil)) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.revokeSession(session.ID, time.Now())
+}
+
+func hashDevice(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+const newDeviceEmailTemplate = `
+<html>
+    <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
+        <div style="max-width: 600px; margin: auto; background-color: #fff; padding: 20px; border-radius: 8px; box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1); text-align: center;">
+            <h1 style="color: #4CAF50;">Вход с нового устройства</h1>
+            <p style="font-size: 16px; line-height: 1.5;">Здравствуйте, {{.Name}}!</p>
+            <p style="font-size: 16px; line-height: 1.5;">В ваш аккаунт выполнен вход с нового устройства.</p>
+            <p style="font-size: 14px; line-height: 1.5;">
+                Время: {{.Time}}<br>
+                Устройство: {{.UserAgent}}<br>
+                IP-адрес: {{.IP}}{{if .Location}}<br>
+                Местоположение: {{.Location}}{{end}}
+            </p>
+            <p>
+                <a href="{{.SessionsLink}}" style="display: inline-block; padding: 12px 20px; font-size: 16px; color: #fff; background-color: #4CAF50; text-decoration: none; border-radius: 5px;">Активные сеансы</a>
+            </p>
+            <p style="font-size: 14px; color: #666;">Если это были не вы, завершите этот сеанс и смените пароль.</p>
+        </div>
+    </body>
+</html>
+`
+
+func makeNewDeviceEmailTemplate(name string, session model.Session, sessionsLink string) (string, error) {
+	tmpl, err := template.New("new_device_email").Parse(newDeviceEmailTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, struct {
+		Name         string
+		Time         string
+		UserAgent    string
+		IP           string
+		Location     string
+		SessionsLink string
+	}{
+		Name:         name,
+		Time:         session.CreatedAt.Format("02.01.2006 15:04 MST"),
+		UserAgent:    session.UserAgent,
+		IP:           session.IP,
+		Location:     session.Location,
+		SessionsLink: sessionsLink,
+	})
+
+	return body.String(), err
+}
+
+func (s *UserService) sendNewDeviceEmail(user model.User, session model.Session) {
+	body, err := makeNewDeviceEmailTemplate(user.Name, session, fmt.Sprintf("%s/profile/sessions", s.frontendURL))
+	if err != nil {
+		slog.Error("cannot make new device email", sl.Err(err))
+		return
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(user.Email, "Вход с нового устройства", body); err != nil {
+			slog.Error("cannot send new device email", sl.Err(err))
+		}
+	}()
+}
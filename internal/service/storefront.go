@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/requisites"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"gorm.io/gorm"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrStorefrontNotFound - витрина есть только у подтвержденного бизнеса
+	ErrStorefrontNotFound = errors.New("storefront not found")
+	ErrInvalidSlug        = errors.New("slug must be 3-63 lowercase latin letters, digits or hyphens and start with a letter")
+	ErrSlugTaken          = errors.New("slug is already taken")
+	ErrInvalidMedia       = errors.New("unknown storefront media")
+)
+
+// slugPattern - адрес витрины. Slug начинается с буквы, поэтому не путается с id бизнеса.
+var slugPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,61}[a-z0-9]$`)
+
+const (
+	storefrontPageSize    = 20
+	storefrontMaxPageSize = 100
+)
+
+// StorefrontService - публичные страницы продавцов. Витрина показывает только
+// подтвержденные бизнесы, их одобренные товары и ограниченный набор реквизитов.
+// Логотип и баннер лежат в бакете товаров и загружаются через UploadService.
+type StorefrontService struct {
+	repo         *repo.StorefrontRepo
+	businessRepo *repo.BusinessRepo
+	productRepo  *repo.ProductRepo
+	blob         storage.Blob
+}
+
+func NewStorefrontService(repo *repo.StorefrontRepo, businessRepo *repo.BusinessRepo, productRepo *repo.ProductRepo, blob storage.Blob) *StorefrontService {
+	return &StorefrontService{
+		repo:         repo,
+		businessRepo: businessRepo,
+		productRepo:  productRepo,
+		blob:         blob,
+	}
+}
+
+// business находит подтвержденный бизнес по slug или id
+func (s *StorefrontService) business(ref string) (model.Business, error) {
+	var (
+		business model.Business
+		err      error
+	)
+	if id, perr := strconv.ParseInt(ref, 10, 64); perr == nil {
+		business, err = s.businessRepo.GetBusinessByID(id)
+	} else {
+		business, err = s.repo.GetBusinessBySlug(strings.ToLower(ref))
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Business{}, ErrStorefrontNotFound
+	}
+	if err != nil {
+		return model.Business{}, err
+	}
+	if business.VerificationStatus != model.BusinessVerified {
+		return model.Business{}, ErrStorefrontNotFound
+	}
+	return business, nil
+}
+
+// GetStorefront возвращает витрину по slug или id бизнеса
+func (s *StorefrontService) GetStorefront(ref string) (model.Storefront, error) {
+	business, err := s.business(ref)
+	if err != nil {
+		return model.Storefront{}, err
+	}
+
+	rating, reviews, err := s.repo.GetRating(business.ID)
+	if err != nil {
+		return model.Storefront{}, err
+	}
+	products, err := s.repo.CountProducts(business.ID)
+	if err != nil {
+		return model.Storefront{}, err
+	}
+
+	return model.Storefront{
+		ID:           business.ID,
+		Slug:         deref(business.Slug),
+		Name:         firstNonEmpty(deref(business.ShortName), deref(business.FullName)),
+		Description:  deref(business.Description),
+		Logo:         deref(business.Logo),
+		Banner:       deref(business.Banner),
+		Rating:       rating,
+		ReviewCount:  reviews,
+		ProductCount: products,
+		Legal:        storefrontLegal(business),
+		VerifiedAt:   business.VerifiedAt,
+	}, nil
+}
+
+func storefrontLegal(b model.Business) model.StorefrontLegal {
+	legal := model.StorefrontLegal{
+		Name:    firstNonEmpty(deref(b.FullName), deref(b.ShortName)),
+		INN:     requisites.FormatINN(b.INN),
+		Address: deref(b.Address),
+	}
+	if b.OGRN != nil {
+		legal.OGRN = strconv.FormatInt(*b.OGRN, 10)
+	}
+	return legal
+}
+
+// GetProducts возвращает страницу одобренных товаров витрины
+func (s *StorefrontService) GetProducts(ctx context.Context, ref string, filters model.ProductQueryParams) (model.ProductPage, error) {
+	business, err := s.business(ref)
+	if err != nil {
+		return model.ProductPage{}, err
+	}
+
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.PageSize < 1 {
+		filters.PageSize = storefrontPageSize
+	}
+	if filters.PageSize > storefrontMaxPageSize {
+		filters.PageSize = storefrontMaxPageSize
+	}
+
+	products, total, err := s.productRepo.FilterBusinessProducts(ctx, business.ID, filters)
+	if err != nil {
+		return model.ProductPage{}, err
+	}
+	if products == nil {
+		products = []model.Product{}
+	}
+
+	return model.ProductPage{
+		Items:   products,
+		Total:   total,
+		Page:    filters.Page,
+		PerPage: filters.PageSize,
+	}, nil
+}
+
+// UpdateStorefront меняет slug и описание витрины
+func (s *StorefrontService) UpdateStorefront(businessID int64, req model.StorefrontUpdate) (model.Business, error) {
+	if _, err := s.businessRepo.GetBusinessByID(businessID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.Business{}, ErrBusinessNotFound
+		}
+		return model.Business{}, err
+	}
+
+	fields := map[string]any{}
+	if req.Slug != nil {
+		slug := strings.ToLower(strings.TrimSpace(*req.Slug))
+		if slug == "" {
+			fields["slug"] = nil
+		} else {
+			if !slugPattern.MatchString(slug) {
+				return model.Business{}, ErrInvalidSlug
+			}
+			other, err := s.repo.GetBusinessBySlug(slug)
+			if err == nil && other.ID != businessID {
+				return model.Business{}, ErrSlugTaken
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return model.Business{}, err
+			}
+			fields["slug"] = slug
+		}
+	}
+	if req.Description != nil {
+		if d := strings.TrimSpace(*req.Description); d == "" {
+			fields["description"] = nil
+		} else {
+			fields["description"] = d
+		}
+	}
+
+	if len(fields) > 0 {
+		if err := s.repo.UpdateStorefront(businessID, fields); err != nil {
+			return model.Business{}, err
+		}
+	}
+	return s.businessRepo.GetBusinessByID(businessID)
+}
+
+// RemoveMedia удаляет логотип или баннер витрины
+func (s *StorefrontService) RemoveMedia(ctx context.Context, businessID int64, media model.StorefrontMedia) error {
+	if media != model.StorefrontLogo && media != model.StorefrontBanner {
+		return ErrInvalidMedia
+	}
+
+	old, err := s.repo.SetMedia(businessID, media, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBusinessNotFound
+	}
+	if err != nil {
+		return err
+	}
+	deleteStorefrontMedia(ctx, s.blob, old)
+	return nil
+}
+
+// deleteStorefrontMedia удаляет замененное изображение витрины. Ошибка только
+// логируется: витрина уже ссылается на новое изображение.
+func deleteStorefrontMedia(ctx context.Context, blob storage.Blob, key *string) {
+	if key == nil || *key == "" {
+		return
+	}
+	if err := blob.Delete(ctx, *key); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		slog.Error("cannot delete storefront media", slog.String("key", *key), sl.Err(err))
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
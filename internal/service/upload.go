@@ -0,0 +1,455 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/repo"
+	"github.com/RCSE2025/backend-go/internal/storage"
+	"github.com/RCSE2025/backend-go/internal/utils"
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrUploadNotFound         = errors.New("upload not found")
+	ErrUploadForbidden        = errors.New("upload belongs to another user")
+	ErrUploadExpired          = errors.New("upload expired")
+	ErrUploadAlreadyConfirmed = errors.New("upload already confirmed")
+	ErrUploadInProgress       = errors.New("upload is being confirmed")
+	ErrUploadNotReceived      = errors.New("file was not uploaded")
+	ErrUploadTooLarge         = errors.New("file is too large")
+	ErrUploadContentType      = errors.New("unsupported content type")
+	ErrUploadInvalidImage     = errors.New("file is not a valid image")
+	ErrUploadRejected         = errors.New("file rejected by moderation")
+	ErrUploadTargetNotFound   = errors.New("upload target not found")
+)
+
+const (
+	// maxImageSide - максимальная ширина и высота изображения в пикселях
+	maxImageSide = 8000
+	// uploadJPEGQuality - качество при перекодировании JPEG
+	uploadJPEGQuality = 90
+	// uploadStuckAfter - через сколько после истечения загрузка, зависшая
+	// в обработке (например, при перезапуске), удаляется очисткой
+	uploadStuckAfter = time.Hour
+)
+
+// uploadImageTypes - допустимые типы файлов и их форматы в image.DecodeConfig
+var uploadImageTypes = map[string]struct {
+	ext    string
+	format string
+}{
+	"image/jpeg": {".jpg", "jpeg"},
+	"image/png":  {".png", "png"},
+	"image/gif":  {".gif", "gif"},
+	"image/webp": {".webp", "webp"},
+}
+
+type UploadService struct {
+	repo           *repo.UploadRepo
+	productRepo    *repo.ProductRepo
+	storefrontRepo *repo.StorefrontRepo
+	businessRepo   *repo.BusinessRepo
+	policy         *PolicyService
+	storages       map[model.UploadTarget]storage.Blob
+	moderator      *utils.ModeratorAPI
+	maxSize        int64
+	urlTTL         time.Duration
+}
+
+func NewUploadService(
+	repo *repo.UploadRepo,
+	productRepo *repo.ProductRepo,
+	storefrontRepo *repo.StorefrontRepo,
+	businessRepo *repo.BusinessRepo,
+	policy *PolicyService,
+	productStorage, reviewStorage storage.Blob,
+	moderator *utils.ModeratorAPI,
+	maxSize int64,
+	urlTTL time.Duration,
+) *UploadService {
+	return &UploadService{
+		repo:           repo,
+		productRepo:    productRepo,
+		storefrontRepo: storefrontRepo,
+		businessRepo:   businessRepo,
+		policy:         policy,
+		storages: map[model.UploadTarget]storage.Blob{
+			model.UploadTargetProduct:        productStorage,
+			model.UploadTargetReview:         reviewStorage,
+			model.UploadTargetBusinessLogo:   productStorage,
+			model.UploadTargetBusinessBanner: productStorage,
+		},
+		moderator: moderator,
+		maxSize:   maxSize,
+		urlTTL:    urlTTL,
+	}
+}
+
+// CreateUpload регистрирует загрузку и выдает ссылку для PUT напрямую в хранилище
+func (s *UploadService) CreateUpload(ctx context.Context, userID int64, req model.UploadCreate) (model.UploadTicket, error) {
+	blob, ok := s.storages[req.Target]
+	if !ok {
+		return model.UploadTicket{}, ErrUploadTargetNotFound
+	}
+
+	imageType, ok := uploadImageTypes[req.ContentType]
+	if !ok {
+		return model.UploadTicket{}, ErrUploadContentType
+	}
+
+	if req.Size > s.maxSize {
+		return model.UploadTicket{}, ErrUploadTooLarge
+	}
+
+	upload := model.Upload{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		Target:      req.Target,
+		ContentType: req.ContentType,
+		MaxSize:     req.Size,
+		Status:      model.UploadStatusPending,
+		ExpiresAt:   time.Now().Add(s.urlTTL),
+	}
+	upload.Key = upload.ID + imageType.ext
+
+	putURL, err := blob.PresignedPutURL(ctx, upload.Key, upload.ContentType, s.urlTTL)
+	if err != nil {
+		return model.UploadTicket{}, err
+	}
+
+	if _, err := s.repo.CreateUpload(upload); err != nil {
+		return model.UploadTicket{}, err
+	}
+
+	return model.UploadTicket{
+		UploadID:  upload.ID,
+		URL:       putURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": upload.ContentType},
+		MaxSize:   upload.MaxSize,
+		ExpiresAt: upload.ExpiresAt,
+	}, nil
+}
+
+// ConfirmUpload проверяет загруженный объект, отправляет его на модерацию,
+// обрабатывает и прикрепляет к товару, отзыву или витрине бизнеса. На время
+// проверки загрузка переводится в processing, поэтому параллельное
+// подтверждение той же загрузки не прикрепит файл дважды.
+func (s *UploadService) ConfirmUpload(ctx context.Context, userID int64, uploadID string, req model.UploadConfirm) (model.UploadConfirmed, error) {
+	upload, err := s.repo.GetUpload(uploadID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UploadConfirmed{}, ErrUploadNotFound
+	}
+	if err != nil {
+		return model.UploadConfirmed{}, err
+	}
+
+	if upload.UserID != userID {
+		return model.UploadConfirmed{}, ErrUploadForbidden
+	}
+	switch upload.Status {
+	case model.UploadStatusConfirmed:
+		return model.UploadConfirmed{}, ErrUploadAlreadyConfirmed
+	case model.UploadStatusProcessing:
+		return model.UploadConfirmed{}, ErrUploadInProgress
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return model.UploadConfirmed{}, ErrUploadExpired
+	}
+
+	if err := s.checkTarget(ctx, userID, upload.Target, req); err != nil {
+		return model.UploadConfirmed{}, err
+	}
+
+	claimed, err := s.repo.UpdateUploadStatus(upload.ID, model.UploadStatusPending, model.UploadStatusProcessing)
+	if err != nil {
+		return model.UploadConfirmed{}, err
+	}
+	if !claimed {
+		return model.UploadConfirmed{}, ErrUploadInProgress
+	}
+
+	blob := s.storages[upload.Target]
+	result, err := s.attach(ctx, blob, upload, req)
+	if err != nil {
+		if errors.Is(err, ErrUploadTooLarge) || errors.Is(err, ErrUploadInvalidImage) || errors.Is(err, ErrUploadRejected) {
+			s.discard(ctx, blob, upload)
+			return model.UploadConfirmed{}, err
+		}
+		// Файл еще не загружен или произошел сбой: клиент может подтвердить снова
+		if _, rerr := s.repo.UpdateUploadStatus(upload.ID, model.UploadStatusProcessing, model.UploadStatusPending); rerr != nil {
+			slog.Error("cannot release upload", slog.String("id", upload.ID), sl.Err(rerr))
+		}
+		return model.UploadConfirmed{}, err
+	}
+
+	if _, err := s.repo.UpdateUploadStatus(upload.ID, model.UploadStatusProcessing, model.UploadStatusConfirmed); err != nil {
+		return model.UploadConfirmed{}, err
+	}
+	upload.Status = model.UploadStatusConfirmed
+	result.Upload = upload
+
+	return result, nil
+}
+
+// attach проверяет и обрабатывает файл загрузки, затем прикрепляет его к цели
+func (s *UploadService) attach(ctx context.Context, blob storage.Blob, upload model.Upload, req model.UploadConfirm) (model.UploadConfirmed, error) {
+	if err := s.verifyObject(ctx, blob, upload); err != nil {
+		return model.UploadConfirmed{}, err
+	}
+	if err := s.processObject(ctx, blob, upload); err != nil {
+		return model.UploadConfirmed{}, err
+	}
+
+	result := model.UploadConfirmed{}
+	switch upload.Target {
+	case model.UploadTargetProduct:
+		image := model.ProductImage{
+			ProductID: req.ProductID,
+			FileUUID:  upload.Key,
+			URL:       upload.Key,
+			IsPrimary: req.IsPrimary,
+		}
+		image.SetTimestamps()
+
+		saved, err := s.productRepo.AddProductImage(ctx, image)
+		if err != nil {
+			return model.UploadConfirmed{}, err
+		}
+		result.ProductImage = saved
+	case model.UploadTargetReview:
+		image := model.ReviewImages{
+			ReviewID:  req.ReviewID,
+			FileUUID:  upload.Key,
+			URL:       upload.Key,
+			IsPrimary: req.IsPrimary,
+		}
+		image.SetTimestamps()
+
+		saved, err := s.productRepo.UploadReviewImages(image)
+		if err != nil {
+			return model.UploadConfirmed{}, err
+		}
+		result.ReviewImage = &saved
+	case model.UploadTargetBusinessLogo, model.UploadTargetBusinessBanner:
+		media := model.StorefrontLogo
+		if upload.Target == model.UploadTargetBusinessBanner {
+			media = model.StorefrontBanner
+		}
+		old, err := s.storefrontRepo.SetMedia(req.BusinessID, media, &upload.Key)
+		if err != nil {
+			return model.UploadConfirmed{}, err
+		}
+		deleteStorefrontMedia(ctx, blob, old)
+
+		business, err := s.businessRepo.GetBusinessByID(req.BusinessID)
+		if err != nil {
+			return model.UploadConfirmed{}, err
+		}
+		result.Business = &business
+	}
+	return result, nil
+}
+
+func (s *UploadService) checkTarget(ctx context.Context, userID int64, target model.UploadTarget, req model.UploadConfirm) error {
+	switch target {
+	case model.UploadTargetProduct:
+		if req.ProductID == 0 {
+			return ErrUploadTargetNotFound
+		}
+		err := s.policy.AuthorizeProduct(ctx, userID, req.ProductID, ActionProducts)
+		if errors.Is(err, ErrProductNotFound) {
+			return ErrUploadTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+	case model.UploadTargetReview:
+		if req.ReviewID == 0 {
+			return ErrUploadTargetNotFound
+		}
+		review, err := s.productRepo.GetReviewByID(req.ReviewID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUploadTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if review.UserID != userID {
+			return ErrUploadForbidden
+		}
+	case model.UploadTargetBusinessLogo, model.UploadTargetBusinessBanner:
+		if req.BusinessID == 0 {
+			return ErrUploadTargetNotFound
+		}
+		err := s.policy.AuthorizeBusiness(ctx, userID, req.BusinessID, ActionWrite)
+		if errors.Is(err, ErrBusinessNotFound) {
+			return ErrUploadTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyObject проверяет размер, формат и содержимое загруженного файла
+func (s *UploadService) verifyObject(ctx context.Context, blob storage.Blob, upload model.Upload) error {
+	info, err := blob.Stat(ctx, upload.Key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return ErrUploadNotReceived
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size > upload.MaxSize {
+		return ErrUploadTooLarge
+	}
+
+	body, _, err := blob.Get(ctx, upload.Key)
+	if err != nil {
+		return err
+	}
+	err = checkImageConfig(body, uploadImageTypes[upload.ContentType].format)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	body, _, err = blob.Get(ctx, upload.Key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	ok, err := s.moderator.IsModerateFile(upload.Key, body)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUploadRejected
+	}
+	return nil
+}
+
+// processObject перекодирует изображение, чтобы убрать из файла метаданные
+// (EXIF с геолокацией, комментарии) и все, что лежит после данных картинки.
+// Кодировщика WebP в golang.org/x/image нет, такие файлы остаются как есть.
+func (s *UploadService) processObject(ctx context.Context, blob storage.Blob, upload model.Upload) error {
+	if upload.ContentType == "image/webp" {
+		return nil
+	}
+
+	body, _, err := blob.Get(ctx, upload.Key)
+	if err != nil {
+		return err
+	}
+	// Файл могли перезаписать после проверки, поэтому размер и заголовок
+	// проверяются еще раз на тех же байтах, что будут декодироваться
+	data, err := io.ReadAll(io.LimitReader(body, upload.MaxSize+1))
+	body.Close()
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > upload.MaxSize {
+		return ErrUploadTooLarge
+	}
+	if err := checkImageConfig(bytes.NewReader(data), uploadImageTypes[upload.ContentType].format); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch upload.ContentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUploadInvalidImage, err)
+		}
+		// Ориентация из EXIF применяется к пикселям, иначе фото с телефона
+		// после удаления метаданных окажется повернутым
+		img = orient(img, jpegOrientation(data))
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: uploadJPEGQuality})
+		if err != nil {
+			return err
+		}
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUploadInvalidImage, err)
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+	case "image/gif":
+		if err := checkGIFFrames(data); err != nil {
+			return err
+		}
+		img, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUploadInvalidImage, err)
+		}
+		if err := gif.EncodeAll(&buf, img); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	return blob.Put(ctx, upload.Key, &buf, int64(buf.Len()), upload.ContentType)
+}
+
+func (s *UploadService) discard(ctx context.Context, blob storage.Blob, upload model.Upload) {
+	if err := blob.Delete(ctx, upload.Key); err != nil {
+		slog.Error("cannot delete rejected upload", slog.String("key", upload.Key), sl.Err(err))
+	}
+	if err := s.repo.DeleteUpload(upload.ID); err != nil {
+		slog.Error("cannot delete rejected upload", slog.String("id", upload.ID), sl.Err(err))
+	}
+}
+
+const uploadCleanupBatch = 100
+
+// CleanupExpiredUploads удаляет неподтвержденные загрузки с истекшим сроком
+// и загрузки, обработка которых прервалась
+func (s *UploadService) CleanupExpiredUploads(ctx context.Context) error {
+	for {
+		uploads, err := s.repo.GetExpiredPendingUploads(time.Now(), uploadStuckAfter, uploadCleanupBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, upload := range uploads {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			blob, ok := s.storages[upload.Target]
+			if ok {
+				if err := blob.Delete(ctx, upload.Key); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+					return err
+				}
+			}
+			if err := s.repo.DeleteUpload(upload.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(uploads) < uploadCleanupBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+)
+
+const (
+	// maxImagePixels - максимальное число пикселей изображения. Декодер выделяет
+	// память под весь кадр сразу, до 8 байт на пиксель.
+	maxImagePixels = 40_000_000
+	// maxGIFFrames и maxGIFPixels ограничивают анимацию: gif.DecodeAll держит
+	// в памяти все кадры
+	maxGIFFrames = 300
+	maxGIFPixels = 100_000_000
+)
+
+// checkImageConfig проверяет формат и размеры изображения по заголовку, не
+// декодируя его
+func checkImageConfig(r io.Reader, format string) error {
+	cfg, got, err := image.DecodeConfig(r)
+	if err != nil || got != format {
+		return ErrUploadInvalidImage
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide || cfg.Width*cfg.Height > maxImagePixels {
+		return fmt.Errorf("%w: %dx%d", ErrUploadInvalidImage, cfg.Width, cfg.Height)
+	}
+	return nil
+}
+
+// checkGIFFrames проходит по блокам GIF и считает кадры и их суммарную
+// площадь, не распаковывая данные
+func checkGIFFrames(data []byte) error {
+	r := bufio.NewReader(bytes.NewReader(data))
+	header := make([]byte, 13)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ErrUploadInvalidImage
+	}
+	if header[10]&0x80 != 0 {
+		if _, err := r.Discard(3 << (header[10]&0x07 + 1)); err != nil {
+			return ErrUploadInvalidImage
+		}
+	}
+
+	frames, pixels := 0, 0
+	for {
+		kind, err := r.ReadByte()
+		if err != nil {
+			return ErrUploadInvalidImage
+		}
+		switch kind {
+		case 0x21: // расширение: метка и подблоки
+			if _, err := r.ReadByte(); err != nil {
+				return ErrUploadInvalidImage
+			}
+		case 0x2C: // кадр: дескриптор, палитра, размер кода LZW и подблоки
+			desc := make([]byte, 9)
+			if _, err := io.ReadFull(r, desc); err != nil {
+				return ErrUploadInvalidImage
+			}
+			frames++
+			pixels += int(binary.LittleEndian.Uint16(desc[4:])) * int(binary.LittleEndian.Uint16(desc[6:]))
+			if frames > maxGIFFrames || pixels > maxGIFPixels {
+				return fmt.Errorf("%w: too many frames", ErrUploadInvalidImage)
+			}
+			n := 1
+			if desc[8]&0x80 != 0 {
+				n += 3 << (desc[8]&0x07 + 1)
+			}
+			if _, err := r.Discard(n); err != nil {
+				return ErrUploadInvalidImage
+			}
+		case 0x3B: // конец файла
+			return nil
+		default:
+			return ErrUploadInvalidImage
+		}
+		if err := skipGIFSubBlocks(r); err != nil {
+			return ErrUploadInvalidImage
+		}
+	}
+}
+
+func skipGIFSubBlocks(r *bufio.Reader) error {
+	for {
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if _, err := r.Discard(int(n)); err != nil {
+			return err
+		}
+	}
+}
+
+// jpegOrientation возвращает значение тега Orientation из EXIF или 1, если
+// тега нет
+func jpegOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		// Дальше начинаются данные изображения, EXIF всегда раньше
+		if marker == 0xDA || size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation ищет тег Orientation (0x0112) в IFD0 блока TIFF
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		// Тип SHORT, значение лежит в первых двух байтах поля
+		if v := int(order.Uint16(tiff[entry+8:])); order.Uint16(tiff[entry+2:]) == 3 && v >= 1 && v <= 8 {
+			return v
+		}
+		return 1
+	}
+	return 1
+}
+
+// orient поворачивает и отражает изображение так, как его показывают по
+// тегу Orientation. После перекодирования тега в файле уже нет.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				dx, dy = w-1-x, y
+			case 3: // поворот на 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				dx, dy = x, h-1-y
+			case 5: // отражение относительно главной диагонали
+				dx, dy = y, x
+			case 6: // поворот на 90° по часовой стрелке
+				dx, dy = h-1-y, x
+			case 7: // отражение относительно побочной диагонали
+				dx, dy = h-1-y, w-1-x
+			case 8: // поворот на 90° против часовой стрелки
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
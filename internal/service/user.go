@@ -2,42 +2,94 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/RCSE2025/backend-go/internal/email"
+	"github.com/RCSE2025/backend-go/internal/geoip"
 	"github.com/RCSE2025/backend-go/internal/model"
+	"github.com/RCSE2025/backend-go/internal/password"
 	"github.com/RCSE2025/backend-go/internal/repo"
-	"github.com/RCSE2025/backend-go/internal/utils"
+	"github.com/RCSE2025/backend-go/internal/sms"
 	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"html/template"
 	"log/slog"
 	"math/big"
+	"strings"
 	"time"
 )
 
 type UserService struct {
 	repo        *repo.UserRepo
+	tokenRepo   *repo.TokenRepo
 	jwtService  JWTService
 	mailer      *email.Mailer
+	geo         geoip.Locator
 	frontendURL string
+	refreshTTL  time.Duration
+	mfaRepo     *repo.MFARepo
+	mfaCfg      MFAConfig
+	guard       *LoginGuard
+	hasher      *password.Hasher
+	policy      *password.Policy
+	otp         *PhoneOTP
+	sessions    *SessionChecker
+	// dummyHash проверяется вместо пароля несуществующего пользователя
+	dummyHash string
 }
 
-func NewUserService(repo *repo.UserRepo, jwtService JWTService, mailer *email.Mailer, frontendURL string) *UserService {
+func NewUserService(repo *repo.UserRepo, tokenRepo *repo.TokenRepo, mfaRepo *repo.MFARepo, jwtService JWTService, mailer *email.Mailer, geo geoip.Locator, frontendURL string, refreshTTL time.Duration, mfaCfg MFAConfig, guard *LoginGuard, hasher *password.Hasher, policy *password.Policy, otp *PhoneOTP, sessions *SessionChecker) *UserService {
+	dummyHash, err := hasher.Hash(uuid.NewString())
+	if err != nil {
+		panic(fmt.Sprintf("cannot hash dummy password: %s", err))
+	}
+
 	return &UserService{
 		repo:        repo,
+		tokenRepo:   tokenRepo,
+		mfaRepo:     mfaRepo,
+		mfaCfg:      mfaCfg,
+		guard:       guard,
+		hasher:      hasher,
+		policy:      policy,
+		otp:         otp,
+		sessions:    sessions,
+		dummyHash:   dummyHash,
 		jwtService:  jwtService,
 		mailer:      mailer,
+		geo:         geo,
 		frontendURL: frontendURL,
+		refreshTTL:  refreshTTL,
 	}
 }
 
 func (s *UserService) CreateUser(user model.UserCreate) (model.User, error) {
+	if err := s.policy.Validate(user.Password, user.Email, user.Name, user.Surname, user.Patronymic); err != nil {
+		return model.User{}, err
+	}
 	if err := s.EmailExistsWithErr(user.Email); err != nil {
 		return model.User{}, err
 	}
 
-	passwordHash, err := utils.HashPassword(user.Password)
+	var phone string
+	if user.Phone != "" {
+		var err error
+		if phone, err = sms.NormalizePhone(user.Phone); err != nil {
+			return model.User{}, err
+		}
+		if err := s.phoneOwner(phone, 0); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	passwordHash, err := s.hasher.Hash(user.Password)
 	if err != nil {
 		return model.User{}, err
 	}
@@ -55,6 +107,18 @@ func (s *UserService) CreateUser(user model.UserCreate) (model.User, error) {
 			INN:               user.INN,
 		},
 	)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if phone != "" {
+		go func() {
+			// Номер привязывается только после ввода кода, регистрацию ошибка SMS не отменяет
+			if err := s.otp.Send(context.Background(), phone, model.PhoneCodeVerify, &userDB.ID); err != nil {
+				slog.Error("cannot send phone verification code", sl.Err(err))
+			}
+		}()
+	}
 
 	code, err := s.CreateVerificationCode(userDB)
 	if err != nil {
@@ -94,82 +158,200 @@ func (s *UserService) GetUserByID(id int64) (model.User, error) {
 
 var ErrWrongEmailOrPassword = errors.New("wrong email or password")
 
-func (s *UserService) GetToken(email, password string) (model.Token, error) {
-	if err := s.EmailNotExistsWithErr(email); err != nil {
-		return model.Token{}, ErrWrongEmailOrPassword
+func (s *UserService) GetToken(ctx context.Context, email, plain string, meta model.SessionMeta) (model.Token, error) {
+	if err := s.guard.Check(ctx, email, meta); err != nil {
+		return model.Token{}, err
 	}
 
-	fmt.Println("email exists")
-
 	user, err := s.repo.GetUserByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Пароль все равно проверяется, чтобы ответ для несуществующего
+		// аккаунта не отличался по времени
+		_, _, _ = s.hasher.Verify(s.dummyHash, plain)
+		return model.Token{}, s.loginFailed(ctx, email, nil, loginFailUnknownUser, meta)
+	}
 	if err != nil {
 		return model.Token{}, err
 	}
 
-	ok, _ := utils.CheckPassword(user.PasswordHash, []byte(password))
-
+	ok, rehash, err := s.hasher.Verify(user.PasswordHash, plain)
+	if err != nil {
+		return model.Token{}, err
+	}
 	if !ok {
-		return model.Token{}, ErrWrongEmailOrPassword
+		return model.Token{}, s.loginFailed(ctx, email, &user, loginFailWrongPassword, meta)
+	}
+	if rehash {
+		s.rehashPassword(user, plain)
+	}
+
+	if err := s.guard.Succeed(ctx, email); err != nil {
+		return model.Token{}, err
+	}
+
+	if token, pending, err := s.loginStep(user); err != nil || pending {
+		return token, err
 	}
 
-	token, err := s.GenerateNewToken(user)
+	token, err := s.GenerateNewToken(user, meta)
 	if err != nil {
 		return model.Token{}, err
 	}
 	return token, nil
 }
 
+// rehashPassword переводит устаревший хеш на текущий алгоритм и параметры.
+// Ошибка не мешает входу: хеш обновится при следующем входе.
+func (s *UserService) rehashPassword(user model.User, plain string) {
+	hash, err := s.hasher.Hash(plain)
+	if err == nil {
+		err = s.repo.ReplacePasswordHash(user.ID, user.PasswordHash, hash)
+	}
+	if err != nil {
+		slog.Error("cannot rehash password", slog.Int64("user_id", user.ID), sl.Err(err))
+	}
+}
+
+// PasswordPolicy возвращает требования к паролю для подсказок в интерфейсе
+func (s *UserService) PasswordPolicy() password.Policy {
+	return *s.policy
+}
+
 func (s *UserService) GetUserRole(user model.User) string {
 	return string(user.Role)
 }
-func (s *UserService) GenerateNewToken(user model.User) (model.Token, error) {
-	accessToken, err := s.jwtService.GenerateToken(user.ID, s.GetUserRole(user))
+
+// GenerateNewToken выдает пару токенов для нового входа и открывает новую сессию
+func (s *UserService) GenerateNewToken(user model.User, meta model.SessionMeta) (model.Token, error) {
+	session, err := s.startSession(user, meta)
 	if err != nil {
 		return model.Token{}, err
 	}
-	refreshToken, err := s.jwtService.GenerateRefreshToken(user.ID, s.GetUserRole(user))
+	return s.issueToken(user, session.ID)
+}
+
+func (s *UserService) issueToken(user model.User, familyID string) (model.Token, error) {
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	_, err = s.tokenRepo.CreateRefreshToken(model.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	accessToken, err := s.jwtService.GenerateToken(user.ID, s.GetUserRole(user), familyID)
 	if err != nil {
 		return model.Token{}, err
 	}
+
 	token := model.Token{
-		RefreshToken: accessToken,
-		AccessToken:  refreshToken,
+		RefreshToken: refreshToken,
+		AccessToken:  accessToken,
 		TokenType:    "bearer",
 	}
 	return token, nil
 }
 
-func (s *UserService) RefreshToken(refreshToken string) (model.Token, error) {
-	token, err := s.jwtService.ValidateToken(refreshToken)
-	if err != nil || !token.Valid {
-		return model.Token{}, errors.New("invalid token")
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, all sessions of this login are revoked")
+)
+
+// RefreshToken обменивает refresh-токен на новую пару. Каждый токен одноразовый:
+// повторное предъявление уже использованного токена означает его утечку,
+// поэтому все семейство отзывается.
+func (s *UserService) RefreshToken(refreshToken string, meta model.SessionMeta) (model.Token, error) {
+	stored, err := s.tokenRepo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Token{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return model.Token{}, err
 	}
 
-	userId, err := s.jwtService.GetUserIDByToken(refreshToken)
+	now := time.Now()
+	if stored.RevokedAt != nil || now.After(stored.ExpiresAt) {
+		return model.Token{}, ErrInvalidRefreshToken
+	}
 
+	ok, err := s.tokenRepo.MarkRefreshTokenUsed(stored.ID, now)
 	if err != nil {
 		return model.Token{}, err
 	}
+	if !ok {
+		if err := s.revokeSession(stored.FamilyID, now); err != nil {
+			return model.Token{}, err
+		}
+		slog.Warn("refresh token reuse detected",
+			slog.Int64("user_id", stored.UserID),
+			slog.String("family_id", stored.FamilyID),
+		)
+		return model.Token{}, ErrRefreshTokenReused
+	}
 
-	if ok, _ := s.UserExists(userId); !ok {
+	user, err := s.repo.GetUserByID(stored.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return model.Token{}, ErrUserNotFound
 	}
-
-	user, err := s.repo.GetUserByID(userId)
-
 	if err != nil {
 		return model.Token{}, err
 	}
 
+	token, err := s.issueToken(user, stored.FamilyID)
 	if err != nil {
 		return model.Token{}, err
 	}
 
-	newToken, err := s.GenerateNewToken(user)
+	err = s.tokenRepo.TouchSession(stored.FamilyID, meta.IP, s.geo.Locate(meta.IP), now, now.Add(s.refreshTTL))
 	if err != nil {
 		return model.Token{}, err
 	}
-	return newToken, nil
+	return token, nil
+}
+
+// Logout отзывает семейство, к которому относится refresh-токен
+func (s *UserService) Logout(refreshToken string) error {
+	stored, err := s.tokenRepo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+	return s.revokeSession(stored.FamilyID, time.Now())
+}
+
+// LogoutAll отзывает все сессии пользователя
+func (s *UserService) LogoutAll(userID int64) error {
+	return s.revokeUserSessions(userID, time.Now())
+}
+
+// CleanupExpiredTokens удаляет refresh-токены с истекшим сроком
+func (s *UserService) CleanupExpiredTokens(ctx context.Context) error {
+	_, err := s.tokenRepo.DeleteExpiredRefreshTokens(time.Now())
+	return err
+}
+
+func newRefreshToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 func (s *UserService) GetUserByEmail(email string) (model.User, error) {
@@ -180,13 +362,6 @@ func (s *UserService) GetUserByEmail(email string) (model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) DeleteUser(id int64) error {
-	if err := s.UserNotExistsWithErr(id); err != nil {
-		return err
-	}
-	return s.repo.DeleteUser(id)
-}
-
 func (s *UserService) GetAllUsers() ([]model.User, error) {
 	users, err := s.repo.GetAllUsers()
 	if err != nil {
@@ -278,7 +453,20 @@ func makeVerificationEmailTemplate(name string, code string) (string, error) {
 	return body.String(), err
 }
 
-var ErrCodeExpired = errors.New("code expired")
+var (
+	ErrCodeExpired          = errors.New("code expired")
+	ErrInvalidCode          = errors.New("invalid verification code")
+	ErrTooManyCodeAttempts  = errors.New("too many invalid attempts, request a new code")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrEmailNotVerified     = errors.New("email is not verified")
+	ErrResendCooldown       = errors.New("verification code was sent recently")
+)
+
+const (
+	// verificationCodeMaxAttempts - после стольких ошибок код перестает действовать
+	verificationCodeMaxAttempts = 5
+	verificationResendCooldown  = time.Minute
+)
 
 func (s *UserService) VerifyEmail(userID int64, code string) error {
 
@@ -286,7 +474,10 @@ func (s *UserService) VerifyEmail(userID int64, code string) error {
 		return err
 	}
 
-	codeDB, err := s.repo.GetVerificationCode(userID, code)
+	codeDB, err := s.repo.GetLatestVerificationCode(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidCode
+	}
 	if err != nil {
 		return err
 	}
@@ -294,8 +485,25 @@ func (s *UserService) VerifyEmail(userID int64, code string) error {
 	if time.Now().After(codeDB.ExpiredAt) {
 		return ErrCodeExpired
 	}
+	if codeDB.Attempts >= verificationCodeMaxAttempts {
+		return ErrTooManyCodeAttempts
+	}
 
-	if err := s.repo.DeleteVerificationCode(codeDB); err != nil {
+	if subtle.ConstantTimeCompare([]byte(codeDB.Code), []byte(code)) != 1 {
+		attempts, err := s.repo.IncrementVerificationAttempts(codeDB.ID)
+		if err != nil {
+			return err
+		}
+		if attempts >= verificationCodeMaxAttempts {
+			if err := s.repo.DeleteVerificationCodes(userID); err != nil {
+				return err
+			}
+			return ErrTooManyCodeAttempts
+		}
+		return ErrInvalidCode
+	}
+
+	if err := s.repo.DeleteVerificationCodes(userID); err != nil {
 		return err
 	}
 
@@ -305,6 +513,68 @@ func (s *UserService) VerifyEmail(userID int64, code string) error {
 	return nil
 }
 
+// ResendVerificationCode отправляет новый код не чаще раза в verificationResendCooldown.
+// Прежние коды перестают действовать.
+func (s *UserService) ResendVerificationCode(userID int64) error {
+	user, err := s.repo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	latest, err := s.repo.GetLatestVerificationCode(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil {
+		if wait := time.Until(latest.SentAt.Add(verificationResendCooldown)); wait > 0 {
+			return &RetryAfterError{Err: ErrResendCooldown, RetryAfter: wait}
+		}
+	}
+
+	if err := s.repo.DeleteVerificationCodes(userID); err != nil {
+		return err
+	}
+	code, err := s.CreateVerificationCode(user)
+	if err != nil {
+		return err
+	}
+	body, err := makeVerificationEmailTemplate(user.Name, code.Code)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.mailer.SendMail(user.Email, "Подтверждение почты", body); err != nil {
+			slog.Error("cannot send verification email", sl.Err(err))
+		}
+	}()
+	return nil
+}
+
+// EmailVerified сообщает, подтвердил ли пользователь почту
+func (s *UserService) EmailVerified(userID int64) (bool, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return user.IsEmailVerified, nil
+}
+
+// CleanupExpiredVerificationCodes удаляет просроченные коды подтверждения
+func (s *UserService) CleanupExpiredVerificationCodes(ctx context.Context) error {
+	_, err := s.repo.DeleteExpiredVerificationCodes(time.Now())
+	return err
+}
+
 const resetPasswordEmailTemplate = `
 <html>
     <body style="font-family: Arial, sans-serif; color: #333; background-color: #f9f9f9; padding: 20px;">
@@ -369,8 +639,36 @@ func (s *UserService) SendResetPasswordEmail(email string) error {
 	return nil
 }
 
-func (s *UserService) UpdateUser(userID int64, user model.User) error {
-	return s.repo.UpdateUser(userID, user)
+var ErrINNLocked = errors.New("inn cannot be changed after passport verification")
+
+// UpdateUser меняет только безопасные поля профиля из UserUpdate
+func (s *UserService) UpdateUser(userID int64, update model.UserUpdate) error {
+	fields := make(map[string]any)
+	if update.Name != nil {
+		fields["name"] = strings.TrimSpace(*update.Name)
+	}
+	if update.Patronymic != nil {
+		fields["patronymic"] = strings.TrimSpace(*update.Patronymic)
+	}
+	if update.Surname != nil {
+		fields["surname"] = strings.TrimSpace(*update.Surname)
+	}
+	if update.DateOfBirth != nil {
+		fields["date_of_birth"] = *update.DateOfBirth
+	}
+
+	if update.INN != nil {
+		user, err := s.repo.GetUserByID(userID)
+		if err != nil {
+			return err
+		}
+		if user.IsPasportVerified && (user.INN == nil || *user.INN != *update.INN) {
+			return ErrINNLocked
+		}
+		fields["inn"] = *update.INN
+	}
+
+	return s.repo.UpdateUser(userID, fields)
 }
 
 func (s *UserService) GenerateRefreshPasswordToken(user model.User) (string, error) {
@@ -381,13 +679,24 @@ func (s *UserService) GenerateRefreshPasswordToken(user model.User) (string, err
 	return token, nil
 }
 
-func (s *UserService) RefreshPassword(token string, password string) error {
+func (s *UserService) RefreshPassword(token string, plain string) error {
 	claims, err := s.jwtService.ValidateRefreshPasswordToken(token)
 	if err != nil {
 		return err
 	}
 
-	passwordHash, err := utils.HashPassword(password)
+	user, err := s.repo.GetUserByID(claims.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Validate(plain, user.Email, user.Name, user.Surname, user.Patronymic); err != nil {
+		return err
+	}
+
+	passwordHash, err := s.hasher.Hash(plain)
 	if err != nil {
 		return err
 	}
@@ -396,5 +705,7 @@ func (s *UserService) RefreshPassword(token string, password string) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// После смены пароля все ранее выданные сессии недействительны
+	return s.revokeUserSessions(claims.UserID, time.Now())
 }
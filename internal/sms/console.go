@@ -0,0 +1,20 @@
+package sms
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ConsoleSender пишет сообщения в лог вместо отправки
+type ConsoleSender struct {
+	log *slog.Logger
+}
+
+func NewConsoleSender(log *slog.Logger) *ConsoleSender {
+	return &ConsoleSender{log: log}
+}
+
+func (s *ConsoleSender) Send(_ context.Context, phone string, text string) error {
+	s.log.Info("sms", slog.String("to", phone), slog.String("text", text))
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package sms sends text messages through a configured gateway.
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// Sender отправляет SMS на номер в формате E.164 (+79991234567)
+type Sender interface {
+	Send(ctx context.Context, phone string, text string) error
+}
+
+const (
+	BackendConsole = "console"
+	BackendSMSRu   = "smsru"
+	BackendSMSC    = "smsc"
+)
+
+// New создает отправителя в соответствии с конфигурацией.
+// Console только пишет сообщения в лог и подходит для разработки.
+func New(cfg config.SMSConfig, log *slog.Logger) (Sender, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	switch cfg.Backend {
+	case BackendConsole, "":
+		return NewConsoleSender(log), nil
+	case BackendSMSRu:
+		if cfg.SMSRuAPIID == "" {
+			return nil, errors.New("SMSRU_API_ID is required for smsru backend")
+		}
+		return NewSMSRuSender(client, cfg.SMSRuAPIID, cfg.Sender), nil
+	case BackendSMSC:
+		if cfg.SMSCLogin == "" || cfg.SMSCPassword == "" {
+			return nil, errors.New("SMSC_LOGIN and SMSC_PASSWORD are required for smsc backend")
+		}
+		return NewSMSCSender(client, cfg.SMSCLogin, cfg.SMSCPassword, cfg.Sender), nil
+	default:
+		return nil, fmt.Errorf("unknown sms backend %q", cfg.Backend)
+	}
+}
+
+// NormalizePhone приводит номер к E.164. Российские номера принимаются
+// в привычных записях: 8 (999) 123-45-67, 7999..., 999...; остальные -
+// только с кодом страны после "+".
+func NormalizePhone(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == '-' || r == '(' || r == ')' || unicode.IsSpace(r):
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	d := digits.String()
+
+	switch {
+	case !international && len(d) == 11 && (d[0] == '8' || d[0] == '7'):
+		d = "7" + d[1:]
+	case !international && len(d) == 10 && d[0] == '9':
+		d = "7" + d
+	case !international:
+		return "", ErrInvalidPhone
+	}
+
+	if len(d) < 8 || len(d) > 15 || d[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	if d[0] == '7' && len(d) != 11 {
+		return "", ErrInvalidPhone
+	}
+	return "+" + d, nil
+}
@@ -0,0 +1,70 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const smscURL = "https://smsc.ru/sys/send.php"
+
+// SMSCSender отправляет сообщения через smsc.ru
+type SMSCSender struct {
+	client   *http.Client
+	login    string
+	password string
+	from     string
+}
+
+func NewSMSCSender(client *http.Client, login string, password string, from string) *SMSCSender {
+	return &SMSCSender{client: client, login: login, password: password, from: from}
+}
+
+type smscResponse struct {
+	ID        int64  `json:"id"`
+	Count     int    `json:"cnt"`
+	Error     string `json:"error"`
+	ErrorCode int    `json:"error_code"`
+}
+
+func (s *SMSCSender) Send(ctx context.Context, phone string, text string) error {
+	form := url.Values{
+		"login":   {s.login},
+		"psw":     {s.password},
+		"phones":  {phone},
+		"mes":     {text},
+		"fmt":     {"3"}, // ответ в JSON
+		"charset": {"utf-8"},
+	}
+	if s.from != "" {
+		form.Set("sender", s.from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, smscURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("smsc: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("smsc: unexpected status %d", resp.StatusCode)
+	}
+
+	var body smscResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("smsc: decode response: %w", err)
+	}
+	if body.Error != "" {
+		return fmt.Errorf("smsc: %d %s", body.ErrorCode, body.Error)
+	}
+	return nil
+}
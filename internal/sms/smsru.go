@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const smsRuURL = "https://sms.ru/sms/send"
+
+// SMSRuSender отправляет сообщения через sms.ru
+type SMSRuSender struct {
+	client *http.Client
+	apiID  string
+	from   string
+}
+
+func NewSMSRuSender(client *http.Client, apiID string, from string) *SMSRuSender {
+	return &SMSRuSender{client: client, apiID: apiID, from: from}
+}
+
+type smsRuStatus struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	StatusText string `json:"status_text"`
+}
+
+type smsRuResponse struct {
+	smsRuStatus
+	SMS map[string]smsRuStatus `json:"sms"`
+}
+
+func (s *SMSRuSender) Send(ctx context.Context, phone string, text string) error {
+	form := url.Values{
+		"api_id": {s.apiID},
+		"to":     {strings.TrimPrefix(phone, "+")},
+		"msg":    {text},
+		"json":   {"1"},
+	}
+	if s.from != "" {
+		form.Set("from", s.from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, smsRuURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms.ru: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sms.ru: unexpected status %d", resp.StatusCode)
+	}
+
+	var body smsRuResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("sms.ru: decode response: %w", err)
+	}
+	if body.Status != "OK" {
+		return fmt.Errorf("sms.ru: %d %s", body.StatusCode, body.StatusText)
+	}
+	for _, st := range body.SMS {
+		if st.Status != "OK" {
+			return fmt.Errorf("sms.ru: %d %s", st.StatusCode, st.StatusText)
+		}
+	}
+	return nil
+}
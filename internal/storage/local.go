@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalBlob хранит объекты на локальном диске. Используется для разработки и тестов.
+type LocalBlob struct {
+	root       string
+	bucket     string
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocalBlob создает бакет в каталоге root/bucket
+func NewLocalBlob(root, bucket, publicURL, signingKey string) (*LocalBlob, error) {
+	dir := filepath.Join(root, bucket)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create storage dir: %w", err)
+	}
+
+	return &LocalBlob{
+		root:       root,
+		bucket:     bucket,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (l *LocalBlob) Bucket() string {
+	return l.bucket
+}
+
+func (l *LocalBlob) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(l.root, l.bucket, filepath.FromSlash(clean)), nil
+}
+
+func (l *LocalBlob) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (l *LocalBlob) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+
+	return f, l.info(key, st), nil
+}
+
+func (l *LocalBlob) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	st, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return l.info(key, st), nil
+}
+
+func (l *LocalBlob) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *LocalBlob) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	dir := filepath.Join(l.root, l.bucket)
+	objects := make([]ObjectInfo, 0)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		st, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, l.info(key, st))
+		return nil
+	})
+
+	return objects, err
+}
+
+func (l *LocalBlob) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return l.signedURL(http.MethodGet, key, "", expires)
+}
+
+func (l *LocalBlob) PresignedPutURL(ctx context.Context, key string, contentType string, expires time.Duration) (string, error) {
+	return l.signedURL(http.MethodPut, key, contentType, expires)
+}
+
+func (l *LocalBlob) signedURL(method, key, contentType string, expires time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("signature", sign(l.signingKey, method, l.bucket, key, contentType, exp))
+
+	return fmt.Sprintf("%s/%s/%s?%s", l.publicURL, l.bucket, key, q.Encode()), nil
+}
+
+func (l *LocalBlob) info(key string, st fs.FileInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          key,
+		Size:         st.Size(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(key)),
+		LastModified: st.ModTime(),
+	}
+}
+
+func sign(secret []byte, method, bucket, key, contentType, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(method + "\n" + bucket + "/" + key + "\n" + contentType + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LocalFileServer отдает и принимает файлы локального хранилища по подписанным ссылкам.
+// Ожидает путь вида /{bucket}/{key}. Тело PUT ограничено maxPutSize байт.
+func LocalFileServer(root, signingKey string, maxPutSize int64) http.Handler {
+	secret := []byte(signingKey)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket, key, ok := strings.Cut(strings.TrimPrefix(path.Clean(r.URL.Path), "/"), "/")
+		if !ok || bucket == "" || key == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		exp := r.URL.Query().Get("expires")
+		expUnix, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil || time.Now().Unix() > expUnix {
+			http.Error(w, "link expired", http.StatusForbidden)
+			return
+		}
+
+		contentType := ""
+		if r.Method == http.MethodPut {
+			contentType = r.Header.Get("Content-Type")
+		}
+
+		expected := sign(secret, r.Method, bucket, key, contentType, exp)
+		if !hmac.Equal([]byte(expected), []byte(r.URL.Query().Get("signature"))) {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			http.ServeFile(w, r, filepath.Join(root, bucket, filepath.FromSlash(key)))
+		case http.MethodPut:
+			blob := &LocalBlob{root: root, bucket: bucket}
+			body := http.MaxBytesReader(w, r.Body, maxPutSize)
+			if err := blob.Put(r.Context(), key, body, r.ContentLength, contentType); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
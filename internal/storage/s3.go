@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Blob работает с S3-совместимым хранилищем напрямую (AWS, MinIO, Yandex Object Storage).
+// Повторы запросов выполняет сам клиент minio.
+type S3Blob struct {
+	client  *minio.Client
+	bucket  string
+	timeout time.Duration
+}
+
+// NewS3Blob создает клиент и при необходимости бакет
+func NewS3Blob(cfg config.StorageConfig, bucket string) (*S3Blob, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+	}
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure:    cfg.S3UseSSL,
+		Region:    cfg.S3Region,
+		Transport: transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create s3 client: %w", err)
+	}
+
+	s := &S3Blob{
+		client:  client,
+		bucket:  bucket,
+		timeout: cfg.Timeout,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("cannot check bucket %s: %w", bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+			return nil, fmt.Errorf("cannot create bucket %s: %w", bucket, err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *S3Blob) Bucket() string {
+	return s.bucket
+}
+
+func (s *S3Blob) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *S3Blob) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, s.wrapErr(err)
+	}
+
+	st, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, ObjectInfo{}, s.wrapErr(err)
+	}
+
+	return obj, objectInfo(st), nil
+}
+
+func (s *S3Blob) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	st, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, s.wrapErr(err)
+	}
+	return objectInfo(st), nil
+}
+
+func (s *S3Blob) Delete(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3Blob) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		objects = append(objects, objectInfo(obj))
+	}
+	return objects, nil
+}
+
+func (s *S3Blob) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expires, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *S3Blob) PresignedPutURL(ctx context.Context, key string, contentType string, expires time.Duration) (string, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+
+	u, err := s.client.PresignHeader(ctx, http.MethodPut, s.bucket, key, expires, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *S3Blob) wrapErr(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrObjectNotFound
+	}
+	return err
+}
+
+func objectInfo(o minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          o.Key,
+		Size:         o.Size,
+		ContentType:  o.ContentType,
+		LastModified: o.LastModified,
+	}
+}
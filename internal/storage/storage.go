@@ -0,0 +1,84 @@
+// Package storage provides object storage backends for product and review files.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/RCSE2025/backend-go/internal/config"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrNotSupported   = errors.New("operation not supported by storage backend")
+)
+
+// ObjectInfo описывает объект в хранилище
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Blob - хранилище объектов одного бакета
+type Blob interface {
+	// Put загружает объект потоком; size может быть -1, если размер неизвестен
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// SignedURL возвращает ссылку на скачивание объекта, действительную expires
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+	// PresignedPutURL возвращает ссылку для прямой загрузки объекта клиентом.
+	// Клиент обязан передать заголовок Content-Type, равный contentType.
+	PresignedPutURL(ctx context.Context, key string, contentType string, expires time.Duration) (string, error)
+	Bucket() string
+}
+
+const (
+	BackendLocal  = "local"
+	BackendS3     = "s3"
+	BackendWorker = "worker"
+)
+
+// New создает хранилище для бакета в соответствии с конфигурацией
+func New(cfg *config.Config, bucket string) (Blob, error) {
+	switch cfg.Storage.Backend {
+	case BackendLocal:
+		return NewLocalBlob(cfg.Storage.LocalDir, bucket, cfg.Storage.PublicURL, cfg.Storage.SigningKey)
+	case BackendS3:
+		return NewS3Blob(cfg.Storage, bucket)
+	case BackendWorker, "":
+		return NewWorkerBlob(cfg.S3WorkerURL, bucket, cfg.Storage.Timeout, cfg.Storage.Retries), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// retry повторяет fn до attempts раз с линейной задержкой.
+// Тело запроса при повторе должно быть перечитываемым, поэтому Put повторяется
+// только для io.Seeker.
+func retry(ctx context.Context, attempts int, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil || errors.Is(err, ErrObjectNotFound) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(i+1) * 200 * time.Millisecond):
+		}
+	}
+	return err
+}
@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WorkerBlob - клиент внешнего HTTP-сервиса S3 worker
+type WorkerBlob struct {
+	workerURL string
+	bucket    string
+	retries   int
+	client    *http.Client
+}
+
+// NewWorkerBlob создает клиент S3 worker для бакета
+func NewWorkerBlob(workerURL, bucket string, timeout time.Duration, retries int) *WorkerBlob {
+	return &WorkerBlob{
+		workerURL: workerURL,
+		bucket:    bucket,
+		retries:   retries,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WorkerBlob) Bucket() string {
+	return s.bucket
+}
+
+// Put отправляет файл multipart-запросом без буферизации в памяти
+func (s *WorkerBlob) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	seeker, seekable := r.(io.Seeker)
+	attempts := 1
+	if seekable {
+		attempts = s.retries
+	}
+
+	first := true
+	return retry(ctx, attempts, func() error {
+		if !first {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		first = false
+		return s.put(ctx, key, r, contentType)
+	})
+}
+
+// put отправляет один запрос. Перед возвратом дожидается горутины, читающей r,
+// чтобы повтор мог безопасно перемотать r в начало.
+func (s *WorkerBlob) put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fw, err := w.CreateFormFile("file", key)
+		if err == nil {
+			_, err = io.Copy(fw, r)
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	// Закрытый pr прерывает запись, если запрос завершился раньше, чем прочитано тело
+	defer func() {
+		pr.Close()
+		<-done
+	}()
+
+	reqURL, err := url.Parse(fmt.Sprintf("%s/upload_file", s.workerURL))
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании URL: %w", err)
+	}
+
+	q := reqURL.Query()
+	q.Add("bucket", s.bucket)
+	q.Add("filename", key)
+	if contentType != "" {
+		q.Add("mimetype", contentType)
+	}
+	reqURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), pr)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.statusErr("ошибка при загрузке файла", resp)
+	}
+	return nil
+}
+
+func (s *WorkerBlob) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	fileURL, err := s.SignedURL(ctx, key, 0)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	var resp *http.Response
+	err = retry(ctx, s.retries, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err = s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return ErrObjectNotFound
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			return s.statusErr("ошибка при получении файла", resp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	lastModified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return resp.Body, ObjectInfo{
+		Key:          key,
+		Size:         resp.ContentLength,
+		ContentType:  resp.Header.Get("Content-Type"),
+		LastModified: lastModified,
+	}, nil
+}
+
+func (s *WorkerBlob) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	body, info, err := s.Get(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	body.Close()
+	return info, nil
+}
+
+func (s *WorkerBlob) Delete(ctx context.Context, key string) error {
+	reqURL, err := url.Parse(fmt.Sprintf("%s/remove_file", s.workerURL))
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании URL: %w", err)
+	}
+
+	q := reqURL.Query()
+	q.Add("bucket", s.bucket)
+	q.Add("filename", key)
+	reqURL.RawQuery = q.Encode()
+
+	return retry(ctx, s.retries, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, reqURL.String(), nil)
+		if err != nil {
+			return fmt.Errorf("ошибка при создании запроса: %w", err)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			return s.statusErr("ошибка при удалении файла", resp)
+		}
+		return nil
+	})
+}
+
+// List возвращает файлы бакета. Worker не поддерживает префиксы и метаданные,
+// поэтому фильтрация выполняется на клиенте, а размер неизвестен.
+func (s *WorkerBlob) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var files []string
+	err := s.getJSON(ctx, fmt.Sprintf("%s/files/%s", s.workerURL, s.bucket), &files, "ошибка при получении списка файлов")
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ObjectInfo, 0, len(files))
+	for _, f := range files {
+		if len(f) >= len(prefix) && f[:len(prefix)] == prefix {
+			objects = append(objects, ObjectInfo{Key: f, Size: -1})
+		}
+	}
+	return objects, nil
+}
+
+// SignedURL возвращает ссылку, выданную worker; срок жизни определяется самим worker
+func (s *WorkerBlob) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	var fileURL string
+	err := s.getJSON(ctx, fmt.Sprintf("%s/file/%s/%s", s.workerURL, s.bucket, url.PathEscape(key)), &fileURL, "ошибка при получении URL файла")
+	return fileURL, err
+}
+
+// PresignedPutURL не поддерживается: worker принимает файлы только через себя
+func (s *WorkerBlob) PresignedPutURL(ctx context.Context, key string, contentType string, expires time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *WorkerBlob) getJSON(ctx context.Context, reqURL string, v any, errMsg string) error {
+	return retry(ctx, s.retries, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return fmt.Errorf("ошибка при создании запроса: %w", err)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return ErrObjectNotFound
+		}
+		if resp.StatusCode != http.StatusOK {
+			return s.statusErr(errMsg, resp)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *WorkerBlob) statusErr(msg string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s: %s, код: %d", msg, string(body), resp.StatusCode)
+}
@@ -105,19 +105,46 @@ func (m *ModeratorAPI) IsModerateContent(content string, files *[]*multipart.Fil
 		return false, err
 	}
 
+	return m.send(&b, writer.FormDataContentType())
+}
+
+// IsModerateFile проверяет один файл, читая его из потока
+func (m *ModeratorAPI) IsModerateFile(filename string, src io.Reader) (bool, error) {
+	var b bytes.Buffer
+	writer := multipart.NewWriter(&b)
+
+	if err := writer.WriteField("text", ""); err != nil {
+		return false, err
+	}
+
+	part, err := writer.CreateFormFile("files", filename)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(part, src); err != nil {
+		return false, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return false, err
+	}
+
+	return m.send(&b, writer.FormDataContentType())
+}
+
+func (m *ModeratorAPI) send(body io.Reader, contentType string) (bool, error) {
 	// Создаем новый HTTP-запрос
-	req, err := http.NewRequest(http.MethodPost, config.Get().ModerateModelURL+"/moderate", &b)
+	req, err := http.NewRequest(http.MethodPost, config.Get().ModerateModelURL+"/moderate", body)
 	if err != nil {
 		return false, err
 	}
 
 	// Устанавливаем заголовки для multipart запроса
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 
 	// Отправляем запрос через HTTP клиент
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := m.Client.Do(req)
 	if err != nil {
 		return false, err
 	}
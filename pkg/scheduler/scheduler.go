@@ -0,0 +1,79 @@
+// Package scheduler runs periodic background jobs.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/RCSE2025/backend-go/pkg/logger/sl"
+)
+
+// Job -.
+type Job func(ctx context.Context) error
+
+type task struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler -.
+type Scheduler struct {
+	log    *slog.Logger
+	tasks  []task
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New -.
+func New(log *slog.Logger) *Scheduler {
+	return &Scheduler{
+		log: log.With(slog.String("component", "scheduler")),
+	}
+}
+
+// Every registers job to run once per interval. Must be called before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.tasks = append(s.tasks, task{name: name, interval: interval, job: job})
+}
+
+// Start runs every registered job in its own goroutine.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.run(ctx, t)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, t task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := t.job(ctx); err != nil {
+				s.log.Error("job failed", slog.String("job", t.name), sl.Err(err))
+				continue
+			}
+			s.log.Debug("job finished", slog.String("job", t.name), slog.String("duration", time.Since(start).String()))
+		}
+	}
+}